@@ -0,0 +1,136 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	DefaultRetryMaxAttempts    = uint64(5)
+	DefaultRetryInitialDelayMs = uint64(500)
+	DefaultRetryMultiplier     = float64(2)
+	DefaultRetryMaxDelayMs     = uint64(30_000)
+
+	// Hard limits on any retry policy, so that a single aggregation cannot query validators indefinitely
+	MaxRetryMaxAttempts = uint64(100)
+	MaxRetryDelayMs     = uint64(10 * 60 * 1_000)
+	MaxRetryDeadlineMs  = uint64(60 * 60 * 1_000)
+)
+
+// Retry policy used when collecting signatures for a Warp message. The delay before each retry grows
+// exponentially, starting at InitialDelayMs and increasing by a factor of Multiplier after each attempt.
+// The delay is capped at MaxDelayMs. Zero-valued fields are replaced by their defaults, with the exception of
+// Jitter and DeadlineMs, which are disabled if zero.
+type RetryPolicy struct {
+	// Maximum number of attempts, including the first. Defaults to 5.
+	MaxAttempts uint64 `mapstructure:"max-attempts" json:"max-attempts"`
+	// Delay before the first retry. Defaults to 500ms.
+	InitialDelayMs uint64 `mapstructure:"initial-delay-ms" json:"initial-delay-ms"`
+	// Factor by which the delay is multiplied after each retry. Must be at least 1. Defaults to 2.
+	Multiplier float64 `mapstructure:"multiplier" json:"multiplier"`
+	// Upper bound on the delay between attempts. Defaults to 30s.
+	MaxDelayMs uint64 `mapstructure:"max-delay-ms" json:"max-delay-ms"`
+	// Fraction of each delay, between 0 and 1, by which that delay is randomly increased or decreased.
+	Jitter float64 `mapstructure:"jitter" json:"jitter"`
+	// Overall time budget across all attempts. No further attempts are made once it would be exceeded.
+	DeadlineMs uint64 `mapstructure:"deadline-ms" json:"deadline-ms"`
+}
+
+func (p *RetryPolicy) Validate() error {
+	if p.Multiplier != 0 && p.Multiplier < 1 {
+		return errors.New("retry policy multiplier must be at least 1")
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return errors.New("retry policy jitter must be between 0 and 1")
+	}
+	if p.MaxAttempts > MaxRetryMaxAttempts {
+		return fmt.Errorf("retry policy max-attempts must be at most %d", MaxRetryMaxAttempts)
+	}
+	if p.InitialDelayMs > MaxRetryDelayMs {
+		return fmt.Errorf("retry policy initial-delay-ms must be at most %d", MaxRetryDelayMs)
+	}
+	if p.MaxDelayMs > MaxRetryDelayMs {
+		return fmt.Errorf("retry policy max-delay-ms must be at most %d", MaxRetryDelayMs)
+	}
+	if p.MaxDelayMs != 0 && p.InitialDelayMs > p.MaxDelayMs {
+		return errors.New("retry policy initial-delay-ms must not exceed max-delay-ms")
+	}
+	if p.DeadlineMs > MaxRetryDeadlineMs {
+		return fmt.Errorf("retry policy deadline-ms must be at most %d", MaxRetryDeadlineMs)
+	}
+	return nil
+}
+
+// WithDefaults returns a copy of the retry policy with zero-valued fields replaced by their defaults.
+// May be called on a nil policy, in which case the default policy is returned.
+func (p *RetryPolicy) WithDefaults() RetryPolicy {
+	var policy RetryPolicy
+	if p != nil {
+		policy = *p
+	}
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if policy.InitialDelayMs == 0 {
+		policy.InitialDelayMs = DefaultRetryInitialDelayMs
+	}
+	if policy.Multiplier == 0 {
+		policy.Multiplier = DefaultRetryMultiplier
+	}
+	if policy.MaxDelayMs == 0 {
+		policy.MaxDelayMs = max(DefaultRetryMaxDelayMs, policy.InitialDelayMs)
+	}
+	return policy
+}
+
+// ClampTo returns a copy of the retry policy with defaults applied, bounded by [limit]: no more attempts are
+// made, no longer delays are waited and no longer deadline is allowed than by [limit]. A zero deadline is
+// replaced by that of [limit], if set. May be called on a nil policy, in which case [limit] is returned with
+// its defaults applied.
+func (p *RetryPolicy) ClampTo(limit *RetryPolicy) RetryPolicy {
+	bound := limit.WithDefaults()
+	if p == nil {
+		return bound
+	}
+	policy := p.WithDefaults()
+	policy.MaxAttempts = min(policy.MaxAttempts, bound.MaxAttempts)
+	policy.MaxDelayMs = min(policy.MaxDelayMs, bound.MaxDelayMs)
+	policy.InitialDelayMs = min(policy.InitialDelayMs, policy.MaxDelayMs)
+	if bound.DeadlineMs != 0 && (policy.DeadlineMs == 0 || policy.DeadlineMs > bound.DeadlineMs) {
+		policy.DeadlineMs = bound.DeadlineMs
+	}
+	return policy
+}
+
+// NextDelay returns the duration to wait after the given failed attempt (starting at 1) before retrying,
+// given that the first attempt started at [start]. Returns false if no further attempts should be made,
+// either because the maximum number of attempts has been reached or the deadline would be exceeded.
+func (p *RetryPolicy) NextDelay(attempt uint64, start time.Time) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	delayMs := float64(p.InitialDelayMs) * math.Pow(p.Multiplier, float64(attempt-1))
+	if p.Jitter != 0 {
+		delayMs *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	// Clamp before converting, since the exponential delay overflows a time.Duration after enough attempts
+	maxDelayMs := p.MaxDelayMs
+	if maxDelayMs == 0 {
+		maxDelayMs = max(DefaultRetryMaxDelayMs, p.InitialDelayMs)
+	}
+	if math.IsNaN(delayMs) || delayMs > float64(maxDelayMs) {
+		delayMs = float64(maxDelayMs)
+	}
+	delay := time.Duration(delayMs * float64(time.Millisecond))
+	if p.DeadlineMs != 0 &&
+		time.Since(start)+delay >= time.Duration(p.DeadlineMs)*time.Millisecond {
+		return 0, false
+	}
+	return delay, true
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyWithDefaults(t *testing.T) {
+	var nilPolicy *RetryPolicy
+	require.Equal(t, RetryPolicy{
+		MaxAttempts:    DefaultRetryMaxAttempts,
+		InitialDelayMs: DefaultRetryInitialDelayMs,
+		Multiplier:     DefaultRetryMultiplier,
+		MaxDelayMs:     DefaultRetryMaxDelayMs,
+	}, nilPolicy.WithDefaults())
+
+	policy := &RetryPolicy{
+		MaxAttempts: 3,
+		Jitter:      0.5,
+	}
+	require.Equal(t, RetryPolicy{
+		MaxAttempts:    3,
+		InitialDelayMs: DefaultRetryInitialDelayMs,
+		Multiplier:     DefaultRetryMultiplier,
+		MaxDelayMs:     DefaultRetryMaxDelayMs,
+		Jitter:         0.5,
+	}, policy.WithDefaults())
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy RetryPolicy
+		valid  bool
+	}{
+		{
+			name:   "empty",
+			policy: RetryPolicy{},
+			valid:  true,
+		},
+		{
+			name:   "multiplier less than one",
+			policy: RetryPolicy{Multiplier: 0.5},
+			valid:  false,
+		},
+		{
+			name:   "negative jitter",
+			policy: RetryPolicy{Jitter: -0.1},
+			valid:  false,
+		},
+		{
+			name:   "jitter greater than one",
+			policy: RetryPolicy{Jitter: 1.5},
+			valid:  false,
+		},
+		{
+			name:   "too many attempts",
+			policy: RetryPolicy{MaxAttempts: MaxRetryMaxAttempts + 1},
+			valid:  false,
+		},
+		{
+			name:   "initial delay too long",
+			policy: RetryPolicy{InitialDelayMs: MaxRetryDelayMs + 1},
+			valid:  false,
+		},
+		{
+			name:   "max delay too long",
+			policy: RetryPolicy{MaxDelayMs: MaxRetryDelayMs + 1},
+			valid:  false,
+		},
+		{
+			name:   "initial delay exceeds max delay",
+			policy: RetryPolicy{InitialDelayMs: 2_000, MaxDelayMs: 1_000},
+			valid:  false,
+		},
+		{
+			name:   "deadline too long",
+			policy: RetryPolicy{DeadlineMs: MaxRetryDeadlineMs + 1},
+			valid:  false,
+		},
+		{
+			name: "at limits",
+			policy: RetryPolicy{
+				MaxAttempts:    MaxRetryMaxAttempts,
+				InitialDelayMs: MaxRetryDelayMs,
+				MaxDelayMs:     MaxRetryDelayMs,
+				DeadlineMs:     MaxRetryDeadlineMs,
+			},
+			valid: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.policy.Validate()
+			if testCase.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyNextDelay(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    4,
+		InitialDelayMs: 100,
+		Multiplier:     2,
+	}
+	start := time.Now()
+	for attempt, expected := range []time.Duration{100, 200, 400} {
+		delay, ok := policy.NextDelay(uint64(attempt+1), start)
+		require.True(t, ok)
+		require.Equal(t, expected*time.Millisecond, delay)
+	}
+	_, ok := policy.NextDelay(4, start)
+	require.False(t, ok)
+
+	// Jitter keeps the delay within the configured fraction
+	policy.Jitter = 0.25
+	for i := 0; i < 100; i++ {
+		delay, ok := policy.NextDelay(2, start)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, delay, 150*time.Millisecond)
+		require.LessOrEqual(t, delay, 250*time.Millisecond)
+	}
+
+	// No retry is made if the delay would exceed the deadline
+	policy.Jitter = 0
+	policy.DeadlineMs = 1_000
+	_, ok = policy.NextDelay(1, start)
+	require.True(t, ok)
+	_, ok = policy.NextDelay(1, start.Add(-950*time.Millisecond))
+	require.False(t, ok)
+
+	// The delay is capped at the max delay, including once the exponential delay overflows
+	policy = RetryPolicy{
+		MaxAttempts:    MaxRetryMaxAttempts,
+		InitialDelayMs: 100,
+		Multiplier:     1_000,
+		MaxDelayMs:     5_000,
+	}
+	for _, attempt := range []uint64{3, 50, MaxRetryMaxAttempts - 1} {
+		delay, ok := policy.NextDelay(attempt, start)
+		require.True(t, ok)
+		require.Equal(t, 5*time.Second, delay)
+	}
+}
+
+func TestRetryPolicyClampTo(t *testing.T) {
+	limit := &RetryPolicy{
+		MaxAttempts: 3,
+		MaxDelayMs:  2_000,
+		DeadlineMs:  10_000,
+	}
+
+	// A nil policy is replaced by the limit
+	var nilPolicy *RetryPolicy
+	require.Equal(t, limit.WithDefaults(), nilPolicy.ClampTo(limit))
+
+	// Fields exceeding the limit are clamped, and an unlimited deadline is replaced by the limit's
+	policy := &RetryPolicy{
+		MaxAttempts:    MaxRetryMaxAttempts,
+		InitialDelayMs: 5_000,
+		Multiplier:     3,
+		MaxDelayMs:     MaxRetryDelayMs,
+	}
+	require.Equal(t, RetryPolicy{
+		MaxAttempts:    3,
+		InitialDelayMs: 2_000,
+		Multiplier:     3,
+		MaxDelayMs:     2_000,
+		DeadlineMs:     10_000,
+	}, policy.ClampTo(limit))
+
+	// Fields within the limit are kept
+	policy = &RetryPolicy{
+		MaxAttempts: 2,
+		Jitter:      0.1,
+		DeadlineMs:  1_000,
+	}
+	require.Equal(t, RetryPolicy{
+		MaxAttempts:    2,
+		InitialDelayMs: DefaultRetryInitialDelayMs,
+		Multiplier:     DefaultRetryMultiplier,
+		MaxDelayMs:     2_000,
+		Jitter:         0.1,
+		DeadlineMs:     1_000,
+	}, policy.ClampTo(limit))
+}
@@ -0,0 +1,27 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import "time"
+
+// Validator set cache configuration. Controls how long a subnet's canonical validator set
+// fetched from the P-Chain may be reused before it is fetched again.
+// If both fields are zero, caching is disabled and the validator set is fetched on every request.
+type ValidatorSetCacheConfig struct {
+	// The cached validator set is used without making any P-Chain API calls until it is
+	// older than this interval.
+	RefreshIntervalSeconds uint64 `mapstructure:"refresh-interval-seconds" json:"refresh-interval-seconds"`
+	// Once the refresh interval has elapsed, the current P-Chain height is fetched. The cached
+	// validator set continues to be used as long as it was fetched at most this many P-Chain
+	// blocks ago.
+	MaxPChainHeightDelta uint64 `mapstructure:"max-p-chain-height-delta" json:"max-p-chain-height-delta"`
+}
+
+func (c *ValidatorSetCacheConfig) Enabled() bool {
+	return c.RefreshIntervalSeconds != 0 || c.MaxPChainHeightDelta != 0
+}
+
+func (c *ValidatorSetCacheConfig) RefreshInterval() time.Duration {
+	return time.Duration(c.RefreshIntervalSeconds) * time.Second
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+// Batch is a set of writes to a relayer ID's state that are applied atomically by WriteBatch.
+// The writes are applied in the order in which they were added. Batch is not thread-safe.
+type Batch struct {
+	ops []batchOp
+}
+
+type batchOp struct {
+	key   Key
+	value []byte
+	// If set, the key is deleted and value is ignored
+	delete bool
+}
+
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Put adds a write of [value] to [key] to the batch
+func (b *Batch) Put(key Key, value []byte) {
+	b.ops = append(b.ops, batchOp{
+		key:   key,
+		value: append([]byte(nil), value...),
+	})
+}
+
+// Delete adds a deletion of [key] to the batch
+func (b *Batch) Delete(key Key) {
+	b.ops = append(b.ops, batchOp{
+		key:    key,
+		delete: true,
+	})
+}
+
+// Len returns the number of writes in the batch
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
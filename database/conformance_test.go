@@ -0,0 +1,388 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/relayer/config"
+	"github.com/stretchr/testify/require"
+)
+
+// Backends that require an external server are only tested if the URL of a server is provided.
+// Each test run writes to fresh relayer IDs, so a shared server may be used.
+const (
+	testRedisURLEnvVar    = "AWM_RELAYER_TEST_REDIS_URL"
+	testPostgresURLEnvVar = "AWM_RELAYER_TEST_POSTGRES_URL"
+)
+
+type newTestDatabaseFunc func(t *testing.T, relayerIDs []RelayerID) RelayerDatabase
+
+func testBackends() map[string]newTestDatabaseFunc {
+	return map[string]newTestDatabaseFunc{
+		"json": func(t *testing.T, relayerIDs []RelayerID) RelayerDatabase {
+			db, err := NewJSONFileStorage(logging.NoLog{}, t.TempDir(), relayerIDs)
+			require.NoError(t, err)
+			return db
+		},
+		"leveldb": func(t *testing.T, relayerIDs []RelayerID) RelayerDatabase {
+			db, err := NewLevelDBStorage(logging.NoLog{}, t.TempDir())
+			require.NoError(t, err)
+			t.Cleanup(func() { require.NoError(t, db.Close()) })
+			return db
+		},
+		"pebble": func(t *testing.T, relayerIDs []RelayerID) RelayerDatabase {
+			db, err := NewPebbleStorage(logging.NoLog{}, t.TempDir())
+			require.NoError(t, err)
+			t.Cleanup(func() { require.NoError(t, db.Close()) })
+			return db
+		},
+		// An in-memory Redis server, so that the Lua scripts, sorted set indexes and transactions of the Redis
+		// backend are tested without an external server.
+		"miniredis": func(t *testing.T, relayerIDs []RelayerID) RelayerDatabase {
+			server := miniredis.RunT(t)
+			db, err := NewRedisDatabase(
+				logging.NoLog{},
+				"redis://"+server.Addr(),
+				&config.RedisConfig{KeyPrefix: "test/"},
+				relayerIDs,
+			)
+			require.NoError(t, err)
+			t.Cleanup(func() { require.NoError(t, db.Close()) })
+			return db
+		},
+		"redis": func(t *testing.T, relayerIDs []RelayerID) RelayerDatabase {
+			redisURL := os.Getenv(testRedisURLEnvVar)
+			if redisURL == "" {
+				t.Skipf("%s not set", testRedisURLEnvVar)
+			}
+			// Keys are prefixed as when sharing the server with other deployments
+			db, err := NewRedisDatabase(logging.NoLog{}, redisURL, &config.RedisConfig{KeyPrefix: "test/"}, relayerIDs)
+			require.NoError(t, err)
+			return db
+		},
+		"postgres": func(t *testing.T, relayerIDs []RelayerID) RelayerDatabase {
+			postgresURL := os.Getenv(testPostgresURLEnvVar)
+			if postgresURL == "" {
+				t.Skipf("%s not set", testPostgresURLEnvVar)
+			}
+			db, err := NewPostgresDatabase(logging.NoLog{}, postgresURL)
+			require.NoError(t, err)
+			t.Cleanup(func() { require.NoError(t, db.Close()) })
+			return db
+		},
+	}
+}
+
+// conformanceTests are run against every RelayerDatabase implementation
+var conformanceTests = map[string]func(t *testing.T, newDB newTestDatabaseFunc){
+	"get missing key":        testGetMissingKey,
+	"put and get":            testPutAndGet,
+	"overwrite":              testOverwrite,
+	"relayer ID isolation":   testRelayerIDIsolation,
+	"concurrent writes":      testConcurrentWrites,
+	"returned value is copy": testReturnedValueIsCopy,
+	"delete":                 testDelete,
+	"get range":              testGetRange,
+	"write batch":            testWriteBatch,
+	"compare and swap":       testCompareAndSwap,
+	"advance height":         testAdvanceLatestProcessedBlockHeight,
+}
+
+func TestRelayerDatabaseConformance(t *testing.T) {
+	for backend, newDB := range testBackends() {
+		t.Run(backend, func(t *testing.T) {
+			for name, test := range conformanceTests {
+				t.Run(name, func(t *testing.T) {
+					test(t, newDB)
+				})
+			}
+		})
+	}
+}
+
+func testGetMissingKey(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+
+	_, err := db.Get(relayerIDs[0].ID, LatestProcessedBlockKey)
+	require.True(t, IsKeyNotFoundError(err), "unexpected error: %v", err)
+}
+
+func testPutAndGet(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+
+	require.NoError(t, db.Put(relayerIDs[0].ID, LatestProcessedBlockKey, []byte("100")))
+	value, err := db.Get(relayerIDs[0].ID, LatestProcessedBlockKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte("100"), value)
+}
+
+func testOverwrite(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+
+	require.NoError(t, db.Put(relayerIDs[0].ID, LatestProcessedBlockKey, []byte("100")))
+	require.NoError(t, db.Put(relayerIDs[0].ID, LatestProcessedBlockKey, []byte("99")))
+	value, err := db.Get(relayerIDs[0].ID, LatestProcessedBlockKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte("99"), value)
+}
+
+func testRelayerIDIsolation(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID(), ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+
+	require.NoError(t, db.Put(relayerIDs[0].ID, LatestProcessedBlockKey, []byte("100")))
+	_, err := db.Get(relayerIDs[1].ID, LatestProcessedBlockKey)
+	require.True(t, IsKeyNotFoundError(err), "unexpected error: %v", err)
+
+	require.NoError(t, db.Put(relayerIDs[1].ID, LatestProcessedBlockKey, []byte("200")))
+	value, err := db.Get(relayerIDs[0].ID, LatestProcessedBlockKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte("100"), value)
+}
+
+func testConcurrentWrites(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+
+	var wg sync.WaitGroup
+	for _, relayerID := range relayerIDs {
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.NoError(t, db.Put(relayerID.ID, LatestProcessedBlockKey, []byte(fmt.Sprint(i))))
+			}()
+		}
+	}
+	wg.Wait()
+
+	// The concurrent writes do not affect subsequent writes and reads
+	for _, relayerID := range relayerIDs {
+		require.NoError(t, db.Put(relayerID.ID, LatestProcessedBlockKey, []byte(relayerID.ID.Hex())))
+	}
+	for _, relayerID := range relayerIDs {
+		value, err := db.Get(relayerID.ID, LatestProcessedBlockKey)
+		require.NoError(t, err)
+		require.Equal(t, []byte(relayerID.ID.Hex()), value)
+	}
+}
+
+func testReturnedValueIsCopy(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+
+	input := []byte("100")
+	require.NoError(t, db.Put(relayerIDs[0].ID, LatestProcessedBlockKey, input))
+	input[0] = '2'
+	value, err := db.Get(relayerIDs[0].ID, LatestProcessedBlockKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte("100"), value)
+
+	value[0] = '3'
+	value, err = db.Get(relayerIDs[0].ID, LatestProcessedBlockKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte("100"), value)
+}
+
+func testDelete(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+
+	// Deleting a missing key is not an error
+	require.NoError(t, db.Delete(relayerIDs[0].ID, LatestProcessedBlockKey))
+
+	require.NoError(t, db.Put(relayerIDs[0].ID, LatestProcessedBlockKey, []byte("100")))
+	require.NoError(t, db.Put(relayerIDs[0].ID, RetryQueueKey.Entry("a"), []byte("1")))
+	require.NoError(t, db.Delete(relayerIDs[0].ID, LatestProcessedBlockKey))
+	require.NoError(t, db.Delete(relayerIDs[0].ID, RetryQueueKey.Entry("a")))
+
+	_, err := db.Get(relayerIDs[0].ID, LatestProcessedBlockKey)
+	require.True(t, IsKeyNotFoundError(err), "unexpected error: %v", err)
+	_, err = db.Get(relayerIDs[0].ID, RetryQueueKey.Entry("a"))
+	require.True(t, IsKeyNotFoundError(err), "unexpected error: %v", err)
+	entries, err := db.GetRange(relayerIDs[0].ID, RetryQueueKey, Range{})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func testGetRange(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID(), ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+
+	entries, err := db.GetRange(relayerIDs[0].ID, DeliveryHistoryKey, Range{})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// Written out of order, along with values that must not be returned
+	for _, id := range []string{"b/2", "a/1", "b/1", "c", "a/2", ""} {
+		require.NoError(t, db.Put(relayerIDs[0].ID, DeliveryHistoryKey.Entry(id), []byte("value "+id)))
+	}
+	require.NoError(t, db.Put(relayerIDs[0].ID, LatestProcessedBlockKey, []byte("100")))
+	require.NoError(t, db.Put(relayerIDs[0].ID, RetryQueueKey.Entry("a/1"), []byte("retry")))
+	require.NoError(t, db.Put(relayerIDs[1].ID, DeliveryHistoryKey.Entry("a/3"), []byte("other relayer")))
+
+	testCases := []struct {
+		name        string
+		r           Range
+		expectedIDs []string
+	}{
+		{
+			name:        "all",
+			r:           Range{},
+			expectedIDs: []string{"", "a/1", "a/2", "b/1", "b/2", "c"},
+		},
+		{
+			name:        "prefix",
+			r:           Range{Prefix: "b/"},
+			expectedIDs: []string{"b/1", "b/2"},
+		},
+		{
+			name:        "start and end",
+			r:           Range{Start: "a/2", End: "b/2"},
+			expectedIDs: []string{"a/2", "b/1"},
+		},
+		{
+			name:        "prefix and start",
+			r:           Range{Prefix: "a/", Start: "a/2"},
+			expectedIDs: []string{"a/2"},
+		},
+		{
+			name:        "end before prefix",
+			r:           Range{Prefix: "b/", End: "a/3"},
+			expectedIDs: nil,
+		},
+		{
+			name:        "limit",
+			r:           Range{Start: "a", Limit: 3},
+			expectedIDs: []string{"a/1", "a/2", "b/1"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			entries, err := db.GetRange(relayerIDs[0].ID, DeliveryHistoryKey, testCase.r)
+			require.NoError(t, err)
+			var ids []string
+			for _, entry := range entries {
+				ids = append(ids, entry.ID)
+				require.Equal(t, []byte("value "+entry.ID), entry.Value)
+			}
+			require.Equal(t, testCase.expectedIDs, ids)
+		})
+	}
+}
+
+func testWriteBatch(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+	require.NoError(t, db.Put(relayerIDs[0].ID, RetryQueueKey.Entry("1"), []byte("retry 1")))
+
+	// The writes are applied in order
+	batch := NewBatch()
+	batch.Put(LatestProcessedBlockKey, []byte("100"))
+	batch.Delete(RetryQueueKey.Entry("1"))
+	batch.Put(RetryQueueKey.Entry("2"), []byte("retry 2"))
+	batch.Put(NonceKey, []byte("1"))
+	batch.Put(NonceKey, []byte("2"))
+	batch.Put(DeliveryHistoryKey.Entry("1"), []byte("delivered"))
+	batch.Delete(DeliveryHistoryKey.Entry("1"))
+	require.Equal(t, 7, batch.Len())
+	require.NoError(t, db.WriteBatch(relayerIDs[0].ID, batch))
+
+	value, err := db.Get(relayerIDs[0].ID, LatestProcessedBlockKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte("100"), value)
+	value, err = db.Get(relayerIDs[0].ID, NonceKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte("2"), value)
+	entries, err := db.GetRange(relayerIDs[0].ID, RetryQueueKey, Range{})
+	require.NoError(t, err)
+	require.Equal(t, []Entry{{ID: "2", Value: []byte("retry 2")}}, entries)
+	entries, err = db.GetRange(relayerIDs[0].ID, DeliveryHistoryKey, Range{})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func testCompareAndSwap(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+	relayerID := relayerIDs[0].ID
+
+	requireValue := func(key Key, expected []byte) {
+		value, err := db.Get(relayerID, key)
+		require.NoError(t, err)
+		require.Equal(t, expected, value)
+	}
+
+	// A nil old value only matches a missing key
+	swapped, err := db.CompareAndSwap(relayerID, LatestProcessedBlockKey, []byte("100"), []byte("101"))
+	require.NoError(t, err)
+	require.False(t, swapped)
+	_, err = db.Get(relayerID, LatestProcessedBlockKey)
+	require.True(t, IsKeyNotFoundError(err), "unexpected error: %v", err)
+	swapped, err = db.CompareAndSwap(relayerID, LatestProcessedBlockKey, nil, []byte("100"))
+	require.NoError(t, err)
+	require.True(t, swapped)
+	requireValue(LatestProcessedBlockKey, []byte("100"))
+	swapped, err = db.CompareAndSwap(relayerID, LatestProcessedBlockKey, nil, []byte("200"))
+	require.NoError(t, err)
+	require.False(t, swapped)
+	requireValue(LatestProcessedBlockKey, []byte("100"))
+
+	// The value is only swapped if it equals the old value
+	swapped, err = db.CompareAndSwap(relayerID, LatestProcessedBlockKey, []byte("99"), []byte("101"))
+	require.NoError(t, err)
+	require.False(t, swapped)
+	requireValue(LatestProcessedBlockKey, []byte("100"))
+	swapped, err = db.CompareAndSwap(relayerID, LatestProcessedBlockKey, []byte("100"), []byte("101"))
+	require.NoError(t, err)
+	require.True(t, swapped)
+	requireValue(LatestProcessedBlockKey, []byte("101"))
+
+	// Entries written by CompareAndSwap are returned by GetRange
+	swapped, err = db.CompareAndSwap(relayerID, RetryQueueKey.Entry("a"), nil, []byte("retry"))
+	require.NoError(t, err)
+	require.True(t, swapped)
+	entries, err := db.GetRange(relayerID, RetryQueueKey, Range{})
+	require.NoError(t, err)
+	require.Equal(t, []Entry{{ID: "a", Value: []byte("retry")}}, entries)
+}
+
+func testAdvanceLatestProcessedBlockHeight(t *testing.T, newDB newTestDatabaseFunc) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	db := newDB(t, relayerIDs)
+
+	// Heights written concurrently and out of order leave the greatest height stored
+	var wg sync.WaitGroup
+	for i := uint64(1); i <= 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			storedHeight, err := AdvanceLatestProcessedBlockHeight(db, relayerIDs[0], 100+(i*7)%20)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, storedHeight, 100+(i*7)%20)
+		}()
+	}
+	wg.Wait()
+	height, err := GetLatestProcessedBlockHeight(db, relayerIDs[0])
+	require.NoError(t, err)
+	require.Equal(t, uint64(119), height)
+
+	// A lower height does not overwrite the stored height
+	storedHeight, err := AdvanceLatestProcessedBlockHeight(db, relayerIDs[0], 50)
+	require.NoError(t, err)
+	require.Equal(t, uint64(119), storedHeight)
+	height, err = GetLatestProcessedBlockHeight(db, relayerIDs[0])
+	require.NoError(t, err)
+	require.Equal(t, uint64(119), height)
+}
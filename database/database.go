@@ -1,11 +1,13 @@
 // Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
-//go:generate mockgen -source=$GOFILE -destination=./mocks/mock_database.go -package=mocks
+//go:generate mockgen -source=$GOFILE -destination=./mocks/mock_database.go -package=mocks -exclude_interfaces=Key
 
 package database
 
 import (
+	"fmt"
+
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/awm-relayer/relayer/config"
 	"github.com/ethereum/go-ethereum/common"
@@ -21,45 +23,147 @@ var (
 
 const (
 	LatestProcessedBlockKey DataKey = iota
+	// Collections of entries, identified by EntryKeys
+	RetryQueueKey
+	DeliveryHistoryKey
+	NonceKey
+	// The shard that owns the relayer ID, when relayer IDs are sharded among instances
+	ShardClaimKey
+	// Collection of the messages processed at heights that have not yet been checkpointed
+	ProcessedMessagesKey
+	// Collection of the messages that are no longer retried after failing to be relayed too many times
+	DeadLetterKey
 )
 
+// Key identifies a value in a relayer ID's state. It is either a DataKey, for values stored directly
+// under a DataKey, or an EntryKey, for entries of a collection stored under a DataKey.
+type Key interface {
+	String() string
+	isKey()
+}
+
 type DataKey int
 
 func (k DataKey) String() string {
 	switch k {
 	case LatestProcessedBlockKey:
 		return "latestProcessedBlock"
+	case RetryQueueKey:
+		return "retryQueue"
+	case DeliveryHistoryKey:
+		return "deliveryHistory"
+	case NonceKey:
+		return "nonce"
+	case ShardClaimKey:
+		return "shardClaim"
+	case ProcessedMessagesKey:
+		return "processedMessages"
+	case DeadLetterKey:
+		return "deadLetters"
 	}
 	return "unknown"
 }
 
+func (DataKey) isKey() {}
+
+// Entry returns the EntryKey of the entry identified by [id] in the collection stored under [k]
+func (k DataKey) Entry(id string) EntryKey {
+	return EntryKey{DataKey: k, ID: id}
+}
+
+// The string representation of every EntryKey of a collection starts with the collection's prefix
+func (k DataKey) entryPrefix() string {
+	return k.String() + entryKeyDelimiter
+}
+
+const entryKeyDelimiter = "/"
+
+// EntryKey identifies an entry in the collection stored under DataKey. The entries of a collection are
+// ordered by their IDs, and can be read with GetRange.
+type EntryKey struct {
+	DataKey DataKey
+	ID      string
+}
+
+func (k EntryKey) String() string {
+	return k.DataKey.entryPrefix() + k.ID
+}
+
+func (EntryKey) isKey() {}
+
+// Entry is an entry of a collection, as returned by GetRange
+type Entry struct {
+	ID    string
+	Value []byte
+}
+
+// Range selects the entries of a collection to return from GetRange.
+// All of the fields are optional, and the zero value selects every entry of the collection.
+type Range struct {
+	// Only entries whose IDs start with Prefix are returned
+	Prefix string
+	// Only entries whose IDs are greater than or equal to Start are returned
+	Start string
+	// If set, only entries whose IDs are less than End are returned
+	End string
+	// If non-zero, at most Limit entries are returned
+	Limit int
+}
+
 // RelayerDatabase is a key-value store for relayer state, with each relayerID maintaining its own state.
 // Implementations should be thread-safe.
 type RelayerDatabase interface {
-	Get(relayerID common.Hash, key DataKey) ([]byte, error)
-	Put(relayerID common.Hash, key DataKey, value []byte) error
+	Get(relayerID common.Hash, key Key) ([]byte, error)
+	Put(relayerID common.Hash, key Key, value []byte) error
+	// Delete removes the value stored under [key]. Deleting a key that is not present is not an error.
+	Delete(relayerID common.Hash, key Key) error
+	// GetRange returns the entries of the collection stored under [key] that are selected by [r],
+	// in ascending order of their IDs.
+	GetRange(relayerID common.Hash, key DataKey, r Range) ([]Entry, error)
+	// WriteBatch atomically applies the writes in [batch] to the state of [relayerID]
+	WriteBatch(relayerID common.Hash, batch *Batch) error
+	// CompareAndSwap atomically stores [value] under [key] if the value stored under [key] equals [old],
+	// or if no value is stored and [old] is nil. Returns whether [value] was stored.
+	CompareAndSwap(relayerID common.Hash, key Key, old []byte, value []byte) (bool, error)
 }
 
+// NewDatabase creates the RelayerDatabase for the storage type configured in [cfg]
 func NewDatabase(logger logging.Logger, cfg *config.Config) (RelayerDatabase, error) {
-	if cfg.RedisURL != "" {
-		db, err := NewRedisDatabase(logger, cfg.RedisURL, GetConfigRelayerIDs(cfg))
-		if err != nil {
-			logger.Error(
-				"Failed to create Redis database",
-				zap.Error(err),
-			)
-			return nil, err
-		}
-		return db, nil
-	} else {
-		db, err := NewJSONFileStorage(logger, cfg.StorageLocation, GetConfigRelayerIDs(cfg))
-		if err != nil {
-			logger.Error(
-				"Failed to create JSON database",
-				zap.Error(err),
-			)
-			return nil, err
-		}
-		return db, nil
+	return NewDatabaseWithRelayerIDs(logger, cfg, GetConfigRelayerIDs(cfg))
+}
+
+// NewDatabaseWithRelayerIDs creates the database configured by [cfg] to store the state of [relayerIDs],
+// which may differ from the relayer IDs of [cfg]
+func NewDatabaseWithRelayerIDs(
+	logger logging.Logger,
+	cfg *config.Config,
+	relayerIDs []RelayerID,
+) (RelayerDatabase, error) {
+	var (
+		db  RelayerDatabase
+		err error
+	)
+	switch storageType := cfg.GetStorageType(); storageType {
+	case config.StorageTypeRedis:
+		db, err = NewRedisDatabase(logger, cfg.RedisURL, &cfg.Redis, relayerIDs)
+	case config.StorageTypePostgres:
+		db, err = NewPostgresDatabase(logger, cfg.PostgresURL)
+	case config.StorageTypeLevelDB:
+		db, err = NewLevelDBStorage(logger, cfg.StorageLocation)
+	case config.StorageTypePebble:
+		db, err = NewPebbleStorage(logger, cfg.StorageLocation)
+	case config.StorageTypeJSON:
+		db, err = NewJSONFileStorage(logger, cfg.StorageLocation, relayerIDs)
+	default:
+		err = errors.Wrap(ErrDatabaseMisconfiguration, fmt.Sprintf("unknown storage type %s", storageType))
+	}
+	if err != nil {
+		logger.Error(
+			"Failed to create database",
+			zap.String("storageType", string(cfg.GetStorageType())),
+			zap.Error(err),
+		)
+		return nil, err
 	}
+	return db, nil
 }
@@ -0,0 +1,180 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Entries of the delivery history collection are stored under two prefixes. The record of each message is
+// stored under its Warp message ID to be looked up, and under the time of its delivery to be listed.
+const (
+	deliveryByMessagePrefix = "message/"
+	deliveryByTimePrefix    = "time/"
+
+	// Bounds the number of expired deliveries pruned by a single write, so that a shortened retention
+	// is applied gradually rather than in one large batch.
+	maxPrunedDeliveryRecords = 100
+)
+
+// DeliveryRecord describes a Warp message delivered to its destination chain by an application relayer
+type DeliveryRecord struct {
+	WarpMessageID ids.ID `json:"warp-message-id"`
+	// ID of the message within its message protocol, such as the Teleporter message ID.
+	// Empty if the protocol does not identify its messages.
+	ProtocolMessageID       ids.ID      `json:"protocol-message-id"`
+	RelayerID               common.Hash `json:"relayer-id"`
+	SourceBlockchainID      ids.ID      `json:"source-blockchain-id"`
+	DestinationBlockchainID ids.ID      `json:"destination-blockchain-id"`
+	// Zero if the message was relayed through the API without specifying the block it was sent in
+	SourceBlockNumber uint64      `json:"source-block-number"`
+	DestinationTxHash common.Hash `json:"destination-tx-hash"`
+	// Zero if the message protocol does not wait for the transaction receipt
+	GasUsed uint64 `json:"gas-used"`
+	// Time from when the relayer started processing the message until it was delivered
+	LatencyMS   int64     `json:"latency-ms"`
+	DeliveredAt time.Time `json:"delivered-at"`
+}
+
+// Entry ID of [record] ordered by time. Delivery times are inverted so that the most recent deliveries are
+// returned first, and suffixed with the message ID to break ties.
+func (record *DeliveryRecord) timeEntryID() string {
+	return timeEntryPrefix(record.DeliveredAt) + record.WarpMessageID.String()
+}
+
+// Prefix of the entry IDs of the deliveries at time [t]. The entries of earlier deliveries are ordered after it.
+func timeEntryPrefix(t time.Time) string {
+	return fmt.Sprintf("%s%020d/", deliveryByTimePrefix, math.MaxInt64-t.UnixNano())
+}
+
+// PutDeliveryRecord atomically adds [record] to the delivery history of its relayer ID. Deliveries made more
+// than [retention] before [record] are pruned in the same batch. A zero [retention] keeps every delivery.
+func PutDeliveryRecord(db RelayerDatabase, record *DeliveryRecord, retention time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	batch := NewBatch()
+	if retention > 0 {
+		if err := pruneDeliveryRecords(db, batch, record, retention); err != nil {
+			return err
+		}
+	}
+	batch.Put(DeliveryHistoryKey.Entry(deliveryByMessagePrefix+record.WarpMessageID.String()), value)
+	batch.Put(DeliveryHistoryKey.Entry(record.timeEntryID()), value)
+	return db.WriteBatch(record.RelayerID, batch)
+}
+
+// Adds the deletion of the deliveries made more than [retention] before [record] by its relayer ID to [batch].
+// The record of each expired message is only deleted if it has not been replaced by a later delivery.
+func pruneDeliveryRecords(db RelayerDatabase, batch *Batch, record *DeliveryRecord, retention time.Duration) error {
+	expired, err := db.GetRange(record.RelayerID, DeliveryHistoryKey, Range{
+		Prefix: deliveryByTimePrefix,
+		Start:  timeEntryPrefix(record.DeliveredAt.Add(-retention)),
+		Limit:  maxPrunedDeliveryRecords,
+	})
+	if err != nil {
+		return err
+	}
+	for _, entry := range expired {
+		batch.Delete(DeliveryHistoryKey.Entry(entry.ID))
+
+		var expiredRecord DeliveryRecord
+		if err := json.Unmarshal(entry.Value, &expiredRecord); err != nil {
+			return fmt.Errorf("failed to unmarshal delivery record: %w", err)
+		}
+		if expiredRecord.WarpMessageID == record.WarpMessageID {
+			// Replaced by [record]
+			continue
+		}
+		messageKey := DeliveryHistoryKey.Entry(deliveryByMessagePrefix + expiredRecord.WarpMessageID.String())
+		value, err := db.Get(record.RelayerID, messageKey)
+		if IsKeyNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(value, entry.Value) {
+			batch.Delete(messageKey)
+		}
+	}
+	return nil
+}
+
+// GetDeliveryRecord returns the most recent delivery of the Warp message [messageID] by any of [relayerIDs].
+// Returns an error satisfying IsKeyNotFoundError if the message has not been delivered.
+func GetDeliveryRecord(db RelayerDatabase, relayerIDs []RelayerID, messageID ids.ID) (*DeliveryRecord, error) {
+	var latest *DeliveryRecord
+	for _, relayerID := range relayerIDs {
+		value, err := db.Get(relayerID.ID, DeliveryHistoryKey.Entry(deliveryByMessagePrefix+messageID.String()))
+		if IsKeyNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var record DeliveryRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal delivery record: %w", err)
+		}
+		if latest == nil || record.DeliveredAt.After(latest.DeliveredAt) {
+			latest = &record
+		}
+	}
+	if latest == nil {
+		return nil, ErrKeyNotFound
+	}
+	return latest, nil
+}
+
+// ListDeliveryRecords returns up to [limit] of the deliveries by [relayerIDs], most recent first, starting from
+// [cursor]. The returned cursor is passed to the next call to continue listing from the next delivery, and is
+// empty if there are no more deliveries. An empty [cursor] starts from the most recent delivery.
+func ListDeliveryRecords(
+	db RelayerDatabase,
+	relayerIDs []RelayerID,
+	cursor string,
+	limit int,
+) ([]*DeliveryRecord, string, error) {
+	// Fetch one entry more than the limit to determine the cursor of the next page
+	r := Range{
+		Prefix: deliveryByTimePrefix,
+		Start:  cursor,
+		Limit:  limit + 1,
+	}
+	var entries []Entry
+	for _, relayerID := range relayerIDs {
+		relayerEntries, err := db.GetRange(relayerID.ID, DeliveryHistoryKey, r)
+		if err != nil {
+			return nil, "", err
+		}
+		entries = append(entries, relayerEntries...)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+
+	var nextCursor string
+	if len(entries) > limit {
+		nextCursor = entries[limit].ID
+		entries = entries[:limit]
+	}
+	records := make([]*DeliveryRecord, len(entries))
+	for i, entry := range entries {
+		records[i] = &DeliveryRecord{}
+		if err := json.Unmarshal(entry.Value, records[i]); err != nil {
+			return nil, "", fmt.Errorf("failed to unmarshal delivery record: %w", err)
+		}
+	}
+	return records, nextCursor, nil
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryHistory(t *testing.T) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID(), ids.GenerateTestID()})
+	db, err := NewJSONFileStorage(logging.NoLog{}, t.TempDir(), relayerIDs)
+	require.NoError(t, err)
+
+	// Deliveries alternate between the relayer IDs, one second apart
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var records []*DeliveryRecord
+	for i := 0; i < 5; i++ {
+		relayerID := relayerIDs[i%2]
+		record := &DeliveryRecord{
+			WarpMessageID:           ids.GenerateTestID(),
+			ProtocolMessageID:       ids.GenerateTestID(),
+			RelayerID:               relayerID.ID,
+			SourceBlockchainID:      relayerID.SourceBlockchainID,
+			DestinationBlockchainID: relayerID.DestinationBlockchainID,
+			SourceBlockNumber:       uint64(100 + i),
+			DestinationTxHash:       common.BigToHash(common.Big1),
+			GasUsed:                 uint64(200_000 + i),
+			LatencyMS:               int64(1000 + i),
+			DeliveredAt:             startTime.Add(time.Duration(i) * time.Second),
+		}
+		require.NoError(t, PutDeliveryRecord(db, record, 0))
+		records = append(records, record)
+	}
+
+	t.Run("get", func(t *testing.T) {
+		record, err := GetDeliveryRecord(db, relayerIDs, records[3].WarpMessageID)
+		require.NoError(t, err)
+		require.Equal(t, records[3], record)
+
+		_, err = GetDeliveryRecord(db, relayerIDs, ids.GenerateTestID())
+		require.True(t, IsKeyNotFoundError(err))
+	})
+
+	t.Run("get most recent delivery", func(t *testing.T) {
+		redelivery := *records[0]
+		redelivery.RelayerID = relayerIDs[1].ID
+		redelivery.DeliveredAt = startTime.Add(time.Minute)
+		require.NoError(t, PutDeliveryRecord(db, &redelivery, 0))
+		t.Cleanup(func() {
+			batch := NewBatch()
+			batch.Delete(DeliveryHistoryKey.Entry(deliveryByMessagePrefix + redelivery.WarpMessageID.String()))
+			batch.Delete(DeliveryHistoryKey.Entry(redelivery.timeEntryID()))
+			require.NoError(t, db.WriteBatch(redelivery.RelayerID, batch))
+		})
+
+		record, err := GetDeliveryRecord(db, relayerIDs, records[0].WarpMessageID)
+		require.NoError(t, err)
+		require.Equal(t, &redelivery, record)
+	})
+
+	t.Run("list pages", func(t *testing.T) {
+		var (
+			listed []*DeliveryRecord
+			cursor string
+		)
+		for {
+			page, nextCursor, err := ListDeliveryRecords(db, relayerIDs, cursor, 2)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(page), 2)
+			listed = append(listed, page...)
+			if nextCursor == "" {
+				break
+			}
+			cursor = nextCursor
+		}
+		// Most recent first
+		require.Equal(t, []*DeliveryRecord{records[4], records[3], records[2], records[1], records[0]}, listed)
+	})
+
+	t.Run("list single relayer ID", func(t *testing.T) {
+		listed, nextCursor, err := ListDeliveryRecords(db, relayerIDs[1:], "", 10)
+		require.NoError(t, err)
+		require.Empty(t, nextCursor)
+		require.Equal(t, []*DeliveryRecord{records[3], records[1]}, listed)
+	})
+}
+
+func TestDeliveryHistoryRetention(t *testing.T) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID(), ids.GenerateTestID()})
+	db, err := NewJSONFileStorage(logging.NoLog{}, t.TempDir(), relayerIDs)
+	require.NoError(t, err)
+	retention := time.Hour
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newRecord := func(relayerID RelayerID, messageID ids.ID, deliveredAt time.Time) *DeliveryRecord {
+		return &DeliveryRecord{
+			WarpMessageID:           messageID,
+			RelayerID:               relayerID.ID,
+			SourceBlockchainID:      relayerID.SourceBlockchainID,
+			DestinationBlockchainID: relayerID.DestinationBlockchainID,
+			DeliveredAt:             deliveredAt,
+		}
+	}
+
+	// Deliveries every 20 minutes by the first relayer ID, and one by the other relayer ID
+	var records []*DeliveryRecord
+	for i := 0; i < 3; i++ {
+		record := newRecord(relayerIDs[0], ids.GenerateTestID(), startTime.Add(time.Duration(i)*20*time.Minute))
+		require.NoError(t, PutDeliveryRecord(db, record, retention))
+		records = append(records, record)
+	}
+	otherRecord := newRecord(relayerIDs[1], ids.GenerateTestID(), startTime)
+	require.NoError(t, PutDeliveryRecord(db, otherRecord, retention))
+
+	// The first message is redelivered later, so only its earlier time entry expires
+	redelivery := newRecord(relayerIDs[0], records[0].WarpMessageID, startTime.Add(50*time.Minute))
+	require.NoError(t, PutDeliveryRecord(db, redelivery, retention))
+
+	// Delivering another message an hour and a half after the first prunes the deliveries more than
+	// an hour older, of the same relayer ID only
+	latest := newRecord(relayerIDs[0], ids.GenerateTestID(), startTime.Add(90*time.Minute))
+	require.NoError(t, PutDeliveryRecord(db, latest, retention))
+
+	listed, _, err := ListDeliveryRecords(db, relayerIDs[:1], "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []*DeliveryRecord{latest, redelivery, records[2]}, listed)
+
+	_, err = GetDeliveryRecord(db, relayerIDs[:1], records[1].WarpMessageID)
+	require.True(t, IsKeyNotFoundError(err))
+	record, err := GetDeliveryRecord(db, relayerIDs[:1], records[0].WarpMessageID)
+	require.NoError(t, err)
+	require.Equal(t, redelivery, record)
+	record, err = GetDeliveryRecord(db, relayerIDs[1:], otherRecord.WarpMessageID)
+	require.NoError(t, err)
+	require.Equal(t, otherRecord, record)
+}
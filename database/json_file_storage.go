@@ -4,10 +4,16 @@
 package database
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -18,9 +24,18 @@ import (
 
 var _ RelayerDatabase = &JSONFileStorage{}
 
+// Suffixes of the files kept alongside the state file of each relayer ID
+const (
+	tmpFileSuffix     = ".tmp"
+	backupFileSuffix  = ".bak"
+	corruptFileSuffix = ".corrupt"
+)
+
 type chainState map[string]string
 
-// JSONFileStorage implements RelayerDatabase
+// JSONFileStorage implements RelayerDatabase, storing the state of each relayer ID in its own JSON file.
+// Values are stored as JSON strings, so must be valid UTF-8. Files are replaced atomically and checksummed,
+// and the previous version of each file is kept as a backup to recover from if the file is corrupted.
 type JSONFileStorage struct {
 	// the directory where the json files are stored
 	dir string
@@ -60,6 +75,14 @@ func NewJSONFileStorage(logger logging.Logger, dir string, relayerIDs []RelayerI
 			}
 			if fileExists {
 				storage.currentState[key] = currentState
+				if err := storage.restore(key, currentState); err != nil {
+					storage.logger.Error(
+						"failed to restore state file",
+						zap.String("relayerID", key.String()),
+						zap.Error(err),
+					)
+					return nil, err
+				}
 			}
 		}
 
@@ -80,7 +103,7 @@ func NewJSONFileStorage(logger logging.Logger, dir string, relayerIDs []RelayerI
 }
 
 // Get the latest chain state from the JSON database, and retrieve the value from the key
-func (s *JSONFileStorage) Get(relayerID common.Hash, dataKey DataKey) ([]byte, error) {
+func (s *JSONFileStorage) Get(relayerID common.Hash, dataKey Key) ([]byte, error) {
 	mutex, ok := s.mutexes[relayerID]
 	if !ok {
 		return nil, errors.Wrap(
@@ -109,8 +132,7 @@ func (s *JSONFileStorage) Get(relayerID common.Hash, dataKey DataKey) ([]byte, e
 
 // Helper to get the current state of a relayerID. Not thread-safe.
 func (s *JSONFileStorage) getCurrentState(relayerID common.Hash) (chainState, bool, error) {
-	currentState := make(chainState)
-	fileExists, err := s.read(relayerID, &currentState)
+	currentState, fileExists, err := s.read(relayerID)
 	if err != nil {
 		s.logger.Error(
 			"failed to read file",
@@ -124,7 +146,95 @@ func (s *JSONFileStorage) getCurrentState(relayerID common.Hash) (chainState, bo
 
 // Put the value into the JSON database. Read the current chain state and overwrite the key, if it exists
 // If the file corresponding to {relayerID} does not exist, then it will be created
-func (s *JSONFileStorage) Put(relayerID common.Hash, dataKey DataKey, value []byte) error {
+func (s *JSONFileStorage) Put(relayerID common.Hash, dataKey Key, value []byte) error {
+	return s.update(relayerID, func(state chainState) bool {
+		state[dataKey.String()] = string(value)
+		return true
+	})
+}
+
+// Delete the key from the JSON database. The file corresponding to {relayerID} is rewritten,
+// even if the key does not exist.
+func (s *JSONFileStorage) Delete(relayerID common.Hash, dataKey Key) error {
+	return s.update(relayerID, func(state chainState) bool {
+		delete(state, dataKey.String())
+		return true
+	})
+}
+
+// GetRange returns the entries of the collection stored under {dataKey} in the JSON database
+func (s *JSONFileStorage) GetRange(relayerID common.Hash, dataKey DataKey, r Range) ([]Entry, error) {
+	mutex, ok := s.mutexes[relayerID]
+	if !ok {
+		return nil, errors.Wrap(
+			ErrDatabaseMisconfiguration,
+			fmt.Sprintf("database not configured for key %s", relayerID.String()),
+		)
+	}
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	currentState, _, err := s.getCurrentState(relayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := dataKey.entryPrefix()
+	start, end, hasEnd := r.bounds()
+	var entries []Entry
+	for key, value := range currentState {
+		id, ok := strings.CutPrefix(key, prefix)
+		if !ok || !inBounds(id, start, end, hasEnd) {
+			continue
+		}
+		entries = append(entries, Entry{ID: id, Value: []byte(value)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	if r.Limit > 0 && len(entries) > r.Limit {
+		entries = entries[:r.Limit]
+	}
+	return entries, nil
+}
+
+// WriteBatch applies the writes in {batch} to the chain state, and writes the file once.
+// Since the file is replaced atomically, either all or none of the writes are persisted.
+func (s *JSONFileStorage) WriteBatch(relayerID common.Hash, batch *Batch) error {
+	return s.update(relayerID, func(state chainState) bool {
+		for _, op := range batch.ops {
+			if op.delete {
+				delete(state, op.key.String())
+			} else {
+				state[op.key.String()] = string(op.value)
+			}
+		}
+		return true
+	})
+}
+
+// CompareAndSwap stores {value} under {dataKey} if the stored value equals {old}. The comparison and the
+// write are made under the relayer ID's mutex, so are atomic with respect to other writes of this instance.
+func (s *JSONFileStorage) CompareAndSwap(relayerID common.Hash, dataKey Key, old []byte, value []byte) (bool, error) {
+	var swapped bool
+	err := s.update(relayerID, func(state chainState) bool {
+		current, ok := state[dataKey.String()]
+		if ok != (old != nil) || current != string(old) {
+			return false
+		}
+		state[dataKey.String()] = string(value)
+		swapped = true
+		return true
+	})
+	if err != nil {
+		return false, err
+	}
+	return swapped, nil
+}
+
+// Applies {apply} to a copy of the in-memory state of {relayerID}, and writes it to disk unless {apply}
+// returns false. The in-memory state is only updated if the write succeeds.
+func (s *JSONFileStorage) update(relayerID common.Hash, apply func(state chainState) bool) error {
 	mutex, ok := s.mutexes[relayerID]
 	if !ok {
 		return errors.Wrap(
@@ -136,21 +246,32 @@ func (s *JSONFileStorage) Put(relayerID common.Hash, dataKey DataKey, value []by
 	mutex.Lock()
 	defer mutex.Unlock()
 
-	// Update the in-memory state and write to disk
-	s.currentState[relayerID][dataKey.String()] = string(value)
-	return s.write(relayerID, s.currentState[relayerID])
+	newState := maps.Clone(s.currentState[relayerID])
+	if !apply(newState) {
+		return nil
+	}
+	if err := s.write(relayerID, newState); err != nil {
+		return err
+	}
+	s.currentState[relayerID] = newState
+	return nil
 }
 
 func (s *JSONFileStorage) getFileName(relayerID common.Hash) string {
 	return filepath.Join(s.dir, relayerID.String()+".json")
 }
 
-// Write the value to the file. The caller is responsible for ensuring proper synchronization
-func (s *JSONFileStorage) write(relayerID common.Hash, v interface{}) error {
+// Write the state to the file. The caller is responsible for ensuring proper synchronization
+//
+// The state is first written and synced to a temporary file, which then replaces the state file, so that
+// a crash cannot leave a partially written state file. The previous state file is kept as a backup to
+// recover from if the state file is nevertheless found to be corrupted.
+func (s *JSONFileStorage) write(relayerID common.Hash, state chainState) error {
 	fnlPath := s.getFileName(relayerID)
-	tmpPath := fnlPath + ".tmp"
+	tmpPath := fnlPath + tmpFileSuffix
+	bakPath := fnlPath + backupFileSuffix
 
-	b, err := json.MarshalIndent(v, "", "\t")
+	b, err := marshalStateFile(state)
 	if err != nil {
 		return err
 	}
@@ -159,46 +280,174 @@ func (s *JSONFileStorage) write(relayerID common.Hash, v interface{}) error {
 	// If  the write fails, the original file is not affected.
 	// Set file permissions to 0644 so only the owner can read and write.
 	// Everyone else can only read. No one can execute the file.
-	if err := os.WriteFile(tmpPath, b, 0644); err != nil {
+	if err := writeFileSync(tmpPath, b, 0644); err != nil {
 		return errors.Wrap(err, "failed to write file")
 	}
 
+	// Keep the current file as the backup. The file does not exist before the first write.
+	if err := os.Rename(fnlPath, bakPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrap(err, "failed to back up file")
+	}
+
 	// Move final file into place
 	if err := os.Rename(tmpPath, fnlPath); err != nil {
 		return errors.Wrap(err, "failed to rename file")
 	}
 
+	// Persist the renames
+	if err := syncDir(s.dir); err != nil {
+		return errors.Wrap(err, "failed to sync directory")
+	}
+
 	return nil
 }
 
-// Read from disk and unmarshal into v
+// Read the state from disk, recovering it from the backup file if the state file is missing or corrupted.
 // Returns a bool indicating whether the file exists, and an error.
 // If an error is returned, the bool should be ignored.
 // The caller is responsible for ensuring proper synchronization
-func (s *JSONFileStorage) read(relayerID common.Hash, v interface{}) (bool, error) {
+func (s *JSONFileStorage) read(relayerID common.Hash) (chainState, bool, error) {
 	path := s.getFileName(relayerID)
 
-	// If the file does not exist, return false, but do not return an error as this
-	// is an expected case
-	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
-		s.logger.Debug(
-			"file does not exist",
+	state, fileExists, err := readStateFile(path)
+	if fileExists && err == nil {
+		return state, true, nil
+	}
+
+	// The state file is missing if the relayer stopped while replacing it, in which case the backup holds
+	// the last state that was written in full
+	backupState, backupExists, backupErr := readStateFile(path + backupFileSuffix)
+	switch {
+	case backupExists && backupErr == nil:
+		s.logger.Warn(
+			"recovered state from backup file",
 			zap.String("path", path),
 			zap.Error(err),
 		)
-		return false, nil
+		return backupState, true, nil
+	case err != nil:
+		return nil, false, err
+	case backupErr != nil:
+		return nil, false, backupErr
+	}
+
+	// If neither file exists, return false, but do not return an error as this
+	// is an expected case
+	s.logger.Debug(
+		"file does not exist",
+		zap.String("path", path),
+	)
+	return nil, false, nil
+}
+
+// Replaces the state file of {relayerID} with {state} if the state file is missing or corrupted, so that the
+// next write keeps the recovered state as the backup. A corrupted state file is kept for inspection.
+// Not thread-safe.
+func (s *JSONFileStorage) restore(relayerID common.Hash, state chainState) error {
+	path := s.getFileName(relayerID)
+	_, fileExists, err := readStateFile(path)
+	if fileExists && err == nil {
+		return nil
+	}
+	if fileExists {
+		if err := os.Rename(path, path+corruptFileSuffix); err != nil {
+			return errors.Wrap(err, "failed to move corrupted file")
+		}
+	}
+	s.logger.Info(
+		"restoring state file",
+		zap.String("path", path),
+	)
+	return s.write(relayerID, state)
+}
+
+// On-disk format of the state of a relayer ID. The checksum is the hex encoded SHA-256 hash of the
+// compact JSON encoding of the state, which is deterministic since map keys are sorted.
+type stateFile struct {
+	Checksum string     `json:"checksum"`
+	State    chainState `json:"state"`
+}
+
+func stateChecksum(state chainState) (string, error) {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return "", err
 	}
+	hash := sha256.Sum256(b)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func marshalStateFile(state chainState) ([]byte, error) {
+	checksum, err := stateChecksum(state)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(stateFile{Checksum: checksum, State: state}, "", "\t")
+}
 
+// Reads and validates the state file at {path}. Returns whether the file exists, even if it fails to be
+// read or validated. Files written before checksums were added are read without validation.
+func readStateFile(path string) (chainState, bool, error) {
 	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
 	if err != nil {
-		return false, errors.Wrap(err, "failed to read file")
+		return nil, true, errors.Wrap(err, "failed to read file")
 	}
 
-	// Unmarshal data
-	if err = json.Unmarshal(b, &v); err != nil {
-		return false, errors.Wrap(err, "failed to unmarshal json file")
+	// Unmarshal data. The state of earlier versions is stored directly, without the checksum,
+	// so does not match the fields of stateFile.
+	var file stateFile
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		state := make(chainState)
+		if legacyErr := json.Unmarshal(b, &state); legacyErr != nil {
+			return nil, true, errors.Wrap(err, "failed to unmarshal json file")
+		}
+		return state, true, nil
+	}
+	if file.Checksum == "" && file.State == nil {
+		// An empty state of an earlier version
+		return make(chainState), true, nil
 	}
 
-	// Return true to indicate that the file exists and we read from it successfully
-	return true, nil
+	checksum, err := stateChecksum(file.State)
+	if err != nil {
+		return nil, true, err
+	}
+	if checksum != file.Checksum {
+		return nil, true, fmt.Errorf("checksum mismatch: expected %s, computed %s", file.Checksum, checksum)
+	}
+	if file.State == nil {
+		file.State = make(chainState)
+	}
+	return file.State, true, nil
+}
+
+// Writes {data} to the file at {path}, and syncs it to disk before returning
+func writeFileSync(path string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
 }
@@ -4,6 +4,7 @@
 package database
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func createRelayerIDs(blockchainIDs []ids.ID) []RelayerID {
@@ -149,3 +151,126 @@ func testWrite(storage *JSONFileStorage, relayerID RelayerID, height uint64) {
 		return
 	}
 }
+
+// Test that the state is recovered from the backup file when the state file is corrupted,
+// as by a write that was interrupted partway.
+func TestJSONFileStorageRecovery(t *testing.T) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	relayerID := relayerIDs[0]
+
+	// Write two states, so that the state file holds height 2 and the backup holds height 1
+	newStorage := func(t *testing.T) (*JSONFileStorage, string) {
+		jsonStorage := setupJsonStorage(t, relayerIDs)
+		testWrite(jsonStorage, relayerID, 1)
+		testWrite(jsonStorage, relayerID, 2)
+		return jsonStorage, jsonStorage.getFileName(relayerID.ID)
+	}
+
+	testCases := []struct {
+		name           string
+		corrupt        func(t *testing.T, path string)
+		expectedHeight uint64
+		recovered      bool
+	}{
+		{
+			name:           "intact",
+			corrupt:        func(t *testing.T, path string) {},
+			expectedHeight: 2,
+		},
+		{
+			name: "interrupted temp file write",
+			corrupt: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path+tmpFileSuffix, []byte(`{"checksum": "ab`), 0644))
+			},
+			expectedHeight: 2,
+		},
+		{
+			name: "state file missing",
+			corrupt: func(t *testing.T, path string) {
+				require.NoError(t, os.Remove(path))
+			},
+			expectedHeight: 1,
+			recovered:      true,
+		},
+		{
+			name: "checksum mismatch",
+			corrupt: func(t *testing.T, path string) {
+				b, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, bytes.Replace(b, []byte(`"2"`), []byte(`"3"`), 1), 0644))
+			},
+			expectedHeight: 1,
+			recovered:      true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			jsonStorage, path := newStorage(t)
+			testCase.corrupt(t, path)
+			requireRecoveredHeight(t, jsonStorage, relayerID, testCase.expectedHeight, testCase.recovered)
+		})
+	}
+
+	// Truncate the state file at every length, as by a write that was interrupted partway
+	t.Run("truncated state file", func(t *testing.T) {
+		jsonStorage, path := newStorage(t)
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		backup, err := os.ReadFile(path + backupFileSuffix)
+		require.NoError(t, err)
+		for i := 0; i < len(b); i++ {
+			require.NoError(t, os.WriteFile(path, b[:i], 0644))
+			require.NoError(t, os.WriteFile(path+backupFileSuffix, backup, 0644))
+			requireRecoveredHeight(t, jsonStorage, relayerID, 1, true)
+		}
+	})
+
+	t.Run("no backup", func(t *testing.T) {
+		jsonStorage, path := newStorage(t)
+		require.NoError(t, os.Remove(path+backupFileSuffix))
+		require.NoError(t, os.WriteFile(path, []byte(`{"checksum": "ab`), 0644))
+		_, err := NewJSONFileStorage(jsonStorage.logger, jsonStorage.dir, relayerIDs)
+		require.Error(t, err)
+	})
+}
+
+// Test that state files written before checksums were added can be read
+func TestJSONFileStorageLegacyFormat(t *testing.T) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	relayerID := relayerIDs[0]
+	jsonStorage := setupJsonStorage(t, relayerIDs)
+	legacy := fmt.Sprintf(`{"%s": "5"}`, LatestProcessedBlockKey)
+	require.NoError(t, os.WriteFile(jsonStorage.getFileName(relayerID.ID), []byte(legacy), 0644))
+
+	requireRecoveredHeight(t, jsonStorage, relayerID, 5, false)
+	testWrite(jsonStorage, relayerID, 6)
+	requireRecoveredHeight(t, jsonStorage, relayerID, 6, false)
+}
+
+// Reopens the storage of [jsonStorage], and checks the height that is read. If the state was [recovered]
+// from the backup file, checks that the state file is restored.
+func requireRecoveredHeight(
+	t *testing.T,
+	jsonStorage *JSONFileStorage,
+	relayerID RelayerID,
+	expectedHeight uint64,
+	recovered bool,
+) {
+	path := jsonStorage.getFileName(relayerID.ID)
+	_, _, errBefore := readStateFile(path)
+
+	reopened, err := NewJSONFileStorage(jsonStorage.logger, jsonStorage.dir, []RelayerID{relayerID})
+	require.NoError(t, err)
+	height, err := GetLatestProcessedBlockHeight(reopened, relayerID)
+	require.NoError(t, err)
+	require.Equal(t, expectedHeight, height)
+
+	state, fileExists, err := readStateFile(path)
+	require.NoError(t, err)
+	require.True(t, fileExists)
+	require.Equal(t, strconv.FormatUint(expectedHeight, 10), state[LatestProcessedBlockKey.String()])
+	if recovered && errBefore != nil {
+		_, err := os.Stat(path + corruptFileSuffix)
+		require.NoError(t, err)
+	}
+}
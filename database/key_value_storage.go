@@ -0,0 +1,208 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"sync"
+
+	avalancheDatabase "github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var _ RelayerDatabase = &KeyValueStorage{}
+
+// KeyValueStorage implements RelayerDatabase on top of an embedded avalanchego key-value database,
+// storing each value under the relayer ID followed by the data key. Unlike JSONFileStorage, each write
+// only updates the written key.
+type KeyValueStorage struct {
+	logger logging.Logger
+	db     avalancheDatabase.Database
+	// Serializes writes, so that CompareAndSwap is atomic with respect to the other writes. The embedded
+	// database is only opened by this process, so no other process can write to it concurrently.
+	writeLock sync.Mutex
+}
+
+// NewLevelDBStorage opens or creates a LevelDB database in [dir]
+func NewLevelDBStorage(logger logging.Logger, dir string) (*KeyValueStorage, error) {
+	db, err := leveldb.New(dir, nil, logger, prometheus.NewRegistry())
+	if err != nil {
+		logger.Error(
+			"Failed to open LevelDB database",
+			zap.String("dir", dir),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return NewKeyValueStorage(logger, db), nil
+}
+
+// NewPebbleStorage opens or creates a Pebble database in [dir]
+func NewPebbleStorage(logger logging.Logger, dir string) (*KeyValueStorage, error) {
+	db, err := pebbledb.New(dir, nil, logger, prometheus.NewRegistry())
+	if err != nil {
+		logger.Error(
+			"Failed to open Pebble database",
+			zap.String("dir", dir),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return NewKeyValueStorage(logger, db), nil
+}
+
+// NewKeyValueStorage creates a KeyValueStorage backed by [db]. The returned KeyValueStorage takes ownership
+// of [db], and closes it on Close.
+func NewKeyValueStorage(logger logging.Logger, db avalancheDatabase.Database) *KeyValueStorage {
+	return &KeyValueStorage{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (s *KeyValueStorage) Get(relayerID common.Hash, key Key) ([]byte, error) {
+	value, err := s.db.Get(keyValueStorageKey(relayerID, key))
+	if err != nil {
+		s.logger.Debug("Error retrieving key from key-value storage",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.String("key", key.String()),
+			zap.Error(err))
+		if errors.Is(err, avalancheDatabase.ErrNotFound) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return value, nil
+}
+
+func (s *KeyValueStorage) Put(relayerID common.Hash, key Key, value []byte) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	err := s.db.Put(keyValueStorageKey(relayerID, key), value)
+	if err != nil {
+		s.logger.Error("Error storing key in key-value storage",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.String("key", key.String()),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *KeyValueStorage) Delete(relayerID common.Hash, key Key) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	err := s.db.Delete(keyValueStorageKey(relayerID, key))
+	if err != nil {
+		s.logger.Error("Error deleting key from key-value storage",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.String("key", key.String()),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *KeyValueStorage) GetRange(relayerID common.Hash, key DataKey, r Range) ([]Entry, error) {
+	start, end, hasEnd := r.bounds()
+	prefix := keyValueStorageKey(relayerID, key.Entry(""))
+	it := s.db.NewIteratorWithStartAndPrefix(keyValueStorageKey(relayerID, key.Entry(start)), prefix)
+	defer it.Release()
+
+	var entries []Entry
+	for it.Next() {
+		if r.Limit > 0 && len(entries) == r.Limit {
+			break
+		}
+		id := string(it.Key()[len(prefix):])
+		if hasEnd && id >= end {
+			break
+		}
+		// The iterator's value may be modified by the next call to Next
+		entries = append(entries, Entry{ID: id, Value: slices.Clone(it.Value())})
+	}
+	if err := it.Error(); err != nil {
+		s.logger.Error("Error iterating key-value storage",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.String("key", key.String()),
+			zap.Error(err))
+		return nil, err
+	}
+	return entries, nil
+}
+
+// WriteBatch applies the writes using a batch of the underlying database, which is written atomically
+func (s *KeyValueStorage) WriteBatch(relayerID common.Hash, batch *Batch) error {
+	dbBatch := s.db.NewBatch()
+	for _, op := range batch.ops {
+		var err error
+		if op.delete {
+			err = dbBatch.Delete(keyValueStorageKey(relayerID, op.key))
+		} else {
+			err = dbBatch.Put(keyValueStorageKey(relayerID, op.key), op.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	if err := dbBatch.Write(); err != nil {
+		s.logger.Error("Error writing batch to key-value storage",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.Int("writes", batch.Len()),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// CompareAndSwap reads the stored value and writes [value] while holding the write lock
+func (s *KeyValueStorage) CompareAndSwap(relayerID common.Hash, key Key, old []byte, value []byte) (bool, error) {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	dbKey := keyValueStorageKey(relayerID, key)
+	current, err := s.db.Get(dbKey)
+	if errors.Is(err, avalancheDatabase.ErrNotFound) {
+		current, err = nil, nil
+	} else if err == nil && current == nil {
+		// Distinguish a stored empty value from a missing value
+		current = []byte{}
+	}
+	if err != nil {
+		s.logger.Error("Error retrieving key from key-value storage",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.String("key", key.String()),
+			zap.Error(err))
+		return false, err
+	}
+	if (current == nil) != (old == nil) || !bytes.Equal(current, old) {
+		return false, nil
+	}
+	if err := s.db.Put(dbKey, value); err != nil {
+		s.logger.Error("Error storing key in key-value storage",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.String("key", key.String()),
+			zap.Error(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// Close closes the underlying database
+func (s *KeyValueStorage) Close() error {
+	return s.db.Close()
+}
+
+// The relayer ID is fixed length, so the key cannot collide with that of another relayer ID
+func keyValueStorageKey(relayerID common.Hash, key Key) []byte {
+	return append(relayerID.Bytes(), key.String()...)
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Value of the entries of the processed messages collection, which only records the presence of each entry
+var processedMessageValue = []byte("processed")
+
+// RetryEntry describes a Warp message that failed to be relayed, and is queued in the retry queue of its
+// relayer ID to be retried. Once the message has failed to be relayed too many times, the entry is moved to
+// the dead letters of its relayer ID.
+type RetryEntry struct {
+	WarpMessageID     ids.ID `json:"warp-message-id"`
+	SourceBlockNumber uint64 `json:"source-block-number"`
+	// Number of failed attempts to relay the message
+	Attempts  uint64    `json:"attempts"`
+	LastError string    `json:"last-error"`
+	QueuedAt  time.Time `json:"queued-at"`
+	// Time before which the message is not retried. Retried immediately if zero.
+	NextAttemptAt time.Time `json:"next-attempt-at"`
+}
+
+// Prefix of the entry IDs of the messages sent at [height]. Heights are zero padded so that entries are
+// ordered by height.
+func heightEntryPrefix(height uint64) string {
+	return fmt.Sprintf("%020d/", height)
+}
+
+// Entry ID of the Warp message [messageID] sent at [height], in both the processed messages collection and
+// the retry queue
+func messageEntryID(height uint64, messageID ids.ID) string {
+	return heightEntryPrefix(height) + messageID.String()
+}
+
+func (entry *RetryEntry) entryID() string {
+	return messageEntryID(entry.SourceBlockNumber, entry.WarpMessageID)
+}
+
+// PutProcessedMessage records that [relayerID] has processed the Warp message [messageID] sent at [height],
+// so that the message is skipped if the height is processed again before it is checkpointed
+func PutProcessedMessage(db RelayerDatabase, relayerID common.Hash, height uint64, messageID ids.ID) error {
+	return db.Put(relayerID, ProcessedMessagesKey.Entry(messageEntryID(height, messageID)), processedMessageValue)
+}
+
+// GetProcessedMessages returns the IDs of the Warp messages sent at [height] that [relayerID] has processed
+func GetProcessedMessages(db RelayerDatabase, relayerID common.Hash, height uint64) (set.Set[ids.ID], error) {
+	prefix := heightEntryPrefix(height)
+	entries, err := db.GetRange(relayerID, ProcessedMessagesKey, Range{Prefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+	messageIDs := set.NewSet[ids.ID](len(entries))
+	for _, entry := range entries {
+		messageID, err := ids.FromString(strings.TrimPrefix(entry.ID, prefix))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse processed message ID: %w", err)
+		}
+		messageIDs.Add(messageID)
+	}
+	return messageIDs, nil
+}
+
+// DeleteProcessedMessages removes the records of the messages processed by [relayerID] at heights up to and
+// including [height], once the height has been checkpointed
+func DeleteProcessedMessages(db RelayerDatabase, relayerID common.Hash, height uint64) error {
+	// The entries of the following heights start with a greater prefix
+	end, _ := prefixUpperBound(heightEntryPrefix(height))
+	entries, err := db.GetRange(relayerID, ProcessedMessagesKey, Range{End: end})
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	batch := NewBatch()
+	for _, entry := range entries {
+		batch.Delete(ProcessedMessagesKey.Entry(entry.ID))
+	}
+	return db.WriteBatch(relayerID, batch)
+}
+
+// QueueRetryEntry atomically adds [entry] to the retry queue of [relayerID], and records the message as
+// processed at its height so that the height can be checkpointed
+func QueueRetryEntry(db RelayerDatabase, relayerID common.Hash, entry *RetryEntry) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	batch := NewBatch()
+	batch.Put(RetryQueueKey.Entry(entry.entryID()), value)
+	batch.Put(ProcessedMessagesKey.Entry(entry.entryID()), processedMessageValue)
+	return db.WriteBatch(relayerID, batch)
+}
+
+// PutRetryEntry updates [entry] in the retry queue of [relayerID], as after a failed retry
+func PutRetryEntry(db RelayerDatabase, relayerID common.Hash, entry *RetryEntry) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return db.Put(relayerID, RetryQueueKey.Entry(entry.entryID()), value)
+}
+
+// DeleteRetryEntry removes [entry] from the retry queue of [relayerID], once the message has been relayed
+func DeleteRetryEntry(db RelayerDatabase, relayerID common.Hash, entry *RetryEntry) error {
+	return db.Delete(relayerID, RetryQueueKey.Entry(entry.entryID()))
+}
+
+// ListRetryEntries returns the entries of the retry queue of [relayerID], in order of height
+func ListRetryEntries(db RelayerDatabase, relayerID common.Hash) ([]*RetryEntry, error) {
+	return listRetryEntries(db, relayerID, RetryQueueKey)
+}
+
+// MoveToDeadLetters atomically moves [entry] from the retry queue of [relayerID] to its dead letters, once
+// the message is no longer retried
+func MoveToDeadLetters(db RelayerDatabase, relayerID common.Hash, entry *RetryEntry) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	batch := NewBatch()
+	batch.Delete(RetryQueueKey.Entry(entry.entryID()))
+	batch.Put(DeadLetterKey.Entry(entry.entryID()), value)
+	return db.WriteBatch(relayerID, batch)
+}
+
+// RequeueDeadLetter atomically moves [entry] from the dead letters of [relayerID] back to its retry queue.
+// The attempts of the entry are reset, and it is retried immediately.
+func RequeueDeadLetter(db RelayerDatabase, relayerID common.Hash, entry *RetryEntry) error {
+	requeued := *entry
+	requeued.Attempts = 0
+	requeued.NextAttemptAt = time.Time{}
+	value, err := json.Marshal(&requeued)
+	if err != nil {
+		return err
+	}
+	batch := NewBatch()
+	batch.Delete(DeadLetterKey.Entry(entry.entryID()))
+	batch.Put(RetryQueueKey.Entry(entry.entryID()), value)
+	return db.WriteBatch(relayerID, batch)
+}
+
+// ListDeadLetters returns the dead letters of [relayerID], in order of height
+func ListDeadLetters(db RelayerDatabase, relayerID common.Hash) ([]*RetryEntry, error) {
+	return listRetryEntries(db, relayerID, DeadLetterKey)
+}
+
+func listRetryEntries(db RelayerDatabase, relayerID common.Hash, key DataKey) ([]*RetryEntry, error) {
+	entries, err := db.GetRange(relayerID, key, Range{})
+	if err != nil {
+		return nil, err
+	}
+	retryEntries := make([]*RetryEntry, len(entries))
+	for i, entry := range entries {
+		retryEntries[i] = &RetryEntry{}
+		if err := json.Unmarshal(entry.Value, retryEntries[i]); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal retry entry: %w", err)
+		}
+	}
+	return retryEntries, nil
+}
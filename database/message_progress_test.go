@@ -0,0 +1,119 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessedMessages(t *testing.T) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID(), ids.GenerateTestID()})
+	db, err := NewJSONFileStorage(logging.NoLog{}, t.TempDir(), relayerIDs)
+	require.NoError(t, err)
+	relayerID := relayerIDs[0].ID
+
+	// Messages processed at consecutive heights, including heights whose decimal representations are
+	// prefixes of one another
+	processed := map[uint64][]ids.ID{}
+	for _, height := range []uint64{1, 9, 10, 11, 100} {
+		for i := 0; i < 2; i++ {
+			messageID := ids.GenerateTestID()
+			require.NoError(t, PutProcessedMessage(db, relayerID, height, messageID))
+			processed[height] = append(processed[height], messageID)
+		}
+	}
+	require.NoError(t, PutProcessedMessage(db, relayerIDs[1].ID, 10, ids.GenerateTestID()))
+
+	for height, messageIDs := range processed {
+		messages, err := GetProcessedMessages(db, relayerID, height)
+		require.NoError(t, err)
+		require.Equal(t, set.Of(messageIDs...), messages)
+	}
+	messages, err := GetProcessedMessages(db, relayerID, 2)
+	require.NoError(t, err)
+	require.Empty(t, messages)
+
+	// Deleting the messages of checkpointed heights keeps those of later heights
+	require.NoError(t, DeleteProcessedMessages(db, relayerID, 10))
+	for height, messageIDs := range processed {
+		messages, err := GetProcessedMessages(db, relayerID, height)
+		require.NoError(t, err)
+		if height <= 10 {
+			require.Empty(t, messages)
+		} else {
+			require.Equal(t, set.Of(messageIDs...), messages)
+		}
+	}
+	messages, err = GetProcessedMessages(db, relayerIDs[1].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+}
+
+func TestRetryQueue(t *testing.T) {
+	relayerIDs := createRelayerIDs([]ids.ID{ids.GenerateTestID()})
+	db, err := NewJSONFileStorage(logging.NoLog{}, t.TempDir(), relayerIDs)
+	require.NoError(t, err)
+	relayerID := relayerIDs[0].ID
+
+	queuedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []*RetryEntry{
+		{WarpMessageID: ids.GenerateTestID(), SourceBlockNumber: 20, Attempts: 1, LastError: "b", QueuedAt: queuedAt},
+		{WarpMessageID: ids.GenerateTestID(), SourceBlockNumber: 3, Attempts: 1, LastError: "a", QueuedAt: queuedAt},
+	}
+	for _, entry := range entries {
+		require.NoError(t, QueueRetryEntry(db, relayerID, entry))
+	}
+
+	// Queued messages are listed in order of height, and recorded as processed at their height
+	listed, err := ListRetryEntries(db, relayerID)
+	require.NoError(t, err)
+	require.Equal(t, []*RetryEntry{entries[1], entries[0]}, listed)
+	messages, err := GetProcessedMessages(db, relayerID, 20)
+	require.NoError(t, err)
+	require.Equal(t, set.Of(entries[0].WarpMessageID), messages)
+
+	// Entries are updated after failed retries, and removed once relayed
+	entries[0].Attempts++
+	entries[0].LastError = "c"
+	require.NoError(t, PutRetryEntry(db, relayerID, entries[0]))
+	require.NoError(t, DeleteRetryEntry(db, relayerID, entries[1]))
+	listed, err = ListRetryEntries(db, relayerID)
+	require.NoError(t, err)
+	require.Equal(t, []*RetryEntry{entries[0]}, listed)
+
+	// Checkpointing the heights of queued messages does not remove them from the retry queue
+	require.NoError(t, DeleteProcessedMessages(db, relayerID, 20))
+	listed, err = ListRetryEntries(db, relayerID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	// Entries that are no longer retried are moved to the dead letters
+	require.NoError(t, MoveToDeadLetters(db, relayerID, entries[0]))
+	listed, err = ListRetryEntries(db, relayerID)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+	deadLetters, err := ListDeadLetters(db, relayerID)
+	require.NoError(t, err)
+	require.Equal(t, []*RetryEntry{entries[0]}, deadLetters)
+
+	// Requeued dead letters are retried immediately, with their attempts reset
+	entries[0].NextAttemptAt = queuedAt.Add(time.Hour)
+	require.NoError(t, RequeueDeadLetter(db, relayerID, entries[0]))
+	deadLetters, err = ListDeadLetters(db, relayerID)
+	require.NoError(t, err)
+	require.Empty(t, deadLetters)
+	listed, err = ListRetryEntries(db, relayerID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, entries[0].WarpMessageID, listed[0].WarpMessageID)
+	require.Equal(t, "c", listed[0].LastError)
+	require.Zero(t, listed[0].Attempts)
+	require.True(t, listed[0].NextAttemptAt.IsZero())
+}
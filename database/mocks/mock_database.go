@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=database.go -destination=./mocks/mock_database.go -package=mocks
+//	mockgen -source=database.go -destination=./mocks/mock_database.go -package=mocks -exclude_interfaces=Key
 //
 
 // Package mocks is a generated GoMock package.
@@ -40,8 +40,37 @@ func (m *MockRelayerDatabase) EXPECT() *MockRelayerDatabaseMockRecorder {
 	return m.recorder
 }
 
+// CompareAndSwap mocks base method.
+func (m *MockRelayerDatabase) CompareAndSwap(relayerID common.Hash, key database.Key, old, value []byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSwap", relayerID, key, old, value)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareAndSwap indicates an expected call of CompareAndSwap.
+func (mr *MockRelayerDatabaseMockRecorder) CompareAndSwap(relayerID, key, old, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSwap", reflect.TypeOf((*MockRelayerDatabase)(nil).CompareAndSwap), relayerID, key, old, value)
+}
+
+// Delete mocks base method.
+func (m *MockRelayerDatabase) Delete(relayerID common.Hash, key database.Key) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", relayerID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRelayerDatabaseMockRecorder) Delete(relayerID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRelayerDatabase)(nil).Delete), relayerID, key)
+}
+
 // Get mocks base method.
-func (m *MockRelayerDatabase) Get(relayerID common.Hash, key database.DataKey) ([]byte, error) {
+func (m *MockRelayerDatabase) Get(relayerID common.Hash, key database.Key) ([]byte, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", relayerID, key)
 	ret0, _ := ret[0].([]byte)
@@ -55,8 +84,23 @@ func (mr *MockRelayerDatabaseMockRecorder) Get(relayerID, key any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRelayerDatabase)(nil).Get), relayerID, key)
 }
 
+// GetRange mocks base method.
+func (m *MockRelayerDatabase) GetRange(relayerID common.Hash, key database.DataKey, r database.Range) ([]database.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRange", relayerID, key, r)
+	ret0, _ := ret[0].([]database.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRange indicates an expected call of GetRange.
+func (mr *MockRelayerDatabaseMockRecorder) GetRange(relayerID, key, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockRelayerDatabase)(nil).GetRange), relayerID, key, r)
+}
+
 // Put mocks base method.
-func (m *MockRelayerDatabase) Put(relayerID common.Hash, key database.DataKey, value []byte) error {
+func (m *MockRelayerDatabase) Put(relayerID common.Hash, key database.Key, value []byte) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Put", relayerID, key, value)
 	ret0, _ := ret[0].(error)
@@ -68,3 +112,17 @@ func (mr *MockRelayerDatabaseMockRecorder) Put(relayerID, key, value any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockRelayerDatabase)(nil).Put), relayerID, key, value)
 }
+
+// WriteBatch mocks base method.
+func (m *MockRelayerDatabase) WriteBatch(relayerID common.Hash, batch *database.Batch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteBatch", relayerID, batch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteBatch indicates an expected call of WriteBatch.
+func (mr *MockRelayerDatabaseMockRecorder) WriteBatch(relayerID, batch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteBatch", reflect.TypeOf((*MockRelayerDatabase)(nil).WriteBatch), relayerID, batch)
+}
@@ -0,0 +1,231 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	// Registers the "postgres" driver with database/sql
+	_ "github.com/lib/pq"
+)
+
+var _ RelayerDatabase = &PostgresDatabase{}
+
+const (
+	createRelayerStateTableQuery = `CREATE TABLE IF NOT EXISTS relayer_state (
+		relayer_id TEXT NOT NULL,
+		data_key TEXT COLLATE "C" NOT NULL,
+		value BYTEA NOT NULL,
+		PRIMARY KEY (relayer_id, data_key)
+	)`
+	getRelayerStateQuery = `SELECT value FROM relayer_state WHERE relayer_id = $1 AND data_key = $2`
+	putRelayerStateQuery = `INSERT INTO relayer_state (relayer_id, data_key, value) VALUES ($1, $2, $3)
+		ON CONFLICT (relayer_id, data_key) DO UPDATE SET value = EXCLUDED.value`
+	// Each statement of CompareAndSwap is atomic. The insert only succeeds if no value is stored, and the
+	// update only succeeds if the stored value equals the expected value.
+	insertRelayerStateIfAbsentQuery = `INSERT INTO relayer_state (relayer_id, data_key, value) VALUES ($1, $2, $3)
+		ON CONFLICT (relayer_id, data_key) DO NOTHING`
+	swapRelayerStateQuery = `UPDATE relayer_state SET value = $4
+		WHERE relayer_id = $1 AND data_key = $2 AND value = $3`
+	deleteRelayerStateQuery = `DELETE FROM relayer_state WHERE relayer_id = $1 AND data_key = $2`
+	// Data keys are declared with the "C" collation, so they are compared bytewise regardless of the
+	// database's default collation, matching the order of Range, and range scans can use the primary key.
+	// A limit of 0 returns every row in the range.
+	getRelayerStateRangeQuery = `SELECT data_key, value FROM relayer_state
+		WHERE relayer_id = $1 AND data_key >= $2 AND data_key < $3
+		ORDER BY data_key
+		LIMIT NULLIF($4::BIGINT, 0)`
+)
+
+// PostgresDatabase implements RelayerDatabase, storing each relayer ID's state as rows of a single table,
+// keyed by the relayer ID and data key.
+type PostgresDatabase struct {
+	logger logging.Logger
+	db     *sql.DB
+}
+
+// NewPostgresDatabase connects to the PostgreSQL database at [postgresURL], and creates the relayer state table
+// if it does not already exist.
+func NewPostgresDatabase(logger logging.Logger, postgresURL string) (*PostgresDatabase, error) {
+	db, err := sql.Open("postgres", postgresURL)
+	if err != nil {
+		logger.Error(
+			"Failed to open Postgres database",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	if _, err := db.ExecContext(context.Background(), createRelayerStateTableQuery); err != nil {
+		db.Close()
+		msg := "Failed to create relayer state table"
+		logger.Error(msg, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return &PostgresDatabase{
+		logger: logger,
+		db:     db,
+	}, nil
+}
+
+func (p *PostgresDatabase) Get(relayerID common.Hash, key Key) ([]byte, error) {
+	var value []byte
+	err := p.db.QueryRowContext(
+		context.Background(),
+		getRelayerStateQuery,
+		relayerID.Hex(),
+		key.String(),
+	).Scan(&value)
+	if err != nil {
+		p.logger.Debug("Error retrieving key from Postgres",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.String("key", key.String()),
+			zap.Error(err))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return value, nil
+}
+
+func (p *PostgresDatabase) Put(relayerID common.Hash, key Key, value []byte) error {
+	batch := NewBatch()
+	batch.Put(key, value)
+	return p.WriteBatch(relayerID, batch)
+}
+
+func (p *PostgresDatabase) Delete(relayerID common.Hash, key Key) error {
+	batch := NewBatch()
+	batch.Delete(key)
+	return p.WriteBatch(relayerID, batch)
+}
+
+func (p *PostgresDatabase) GetRange(relayerID common.Hash, key DataKey, r Range) ([]Entry, error) {
+	prefix := key.entryPrefix()
+	start, end, hasEnd := r.bounds()
+	startKey, endKey := prefix+start, prefix+end
+	if !hasEnd {
+		// Bound the range by the data keys of the collection's entries. Since the prefix ends with the
+		// entry key delimiter, the upper bound always exists.
+		endKey, _ = prefixUpperBound(prefix)
+	}
+	rows, err := p.db.QueryContext(
+		context.Background(),
+		getRelayerStateRangeQuery,
+		relayerID.Hex(),
+		startKey,
+		endKey,
+		r.Limit,
+	)
+	if err != nil {
+		p.logger.Error("Error retrieving range from Postgres",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.String("key", key.String()),
+			zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var (
+			dataKey string
+			value   []byte
+		)
+		if err := rows.Scan(&dataKey, &value); err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{ID: strings.TrimPrefix(dataKey, prefix), Value: value})
+	}
+	return entries, rows.Err()
+}
+
+// WriteBatch applies the writes in a single transaction
+func (p *PostgresDatabase) WriteBatch(relayerID common.Hash, batch *Batch) error {
+	ctx := context.Background()
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		p.logger.Error("Error starting Postgres transaction", zap.Error(err))
+		return err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, op := range batch.ops {
+		if op.delete {
+			_, err = tx.ExecContext(ctx, deleteRelayerStateQuery, relayerID.Hex(), op.key.String())
+		} else {
+			_, err = tx.ExecContext(ctx, putRelayerStateQuery, relayerID.Hex(), op.key.String(), op.value)
+		}
+		if err != nil {
+			p.logger.Error("Error storing key in Postgres",
+				zap.String("relayerID", relayerID.Hex()),
+				zap.String("key", op.key.String()),
+				zap.Error(err))
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		p.logger.Error("Error committing Postgres transaction",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (p *PostgresDatabase) CompareAndSwap(relayerID common.Hash, key Key, old []byte, value []byte) (bool, error) {
+	var (
+		result sql.Result
+		err    error
+	)
+	if old == nil {
+		result, err = p.db.ExecContext(
+			context.Background(),
+			insertRelayerStateIfAbsentQuery,
+			relayerID.Hex(),
+			key.String(),
+			value,
+		)
+	} else {
+		result, err = p.db.ExecContext(
+			context.Background(),
+			swapRelayerStateQuery,
+			relayerID.Hex(),
+			key.String(),
+			old,
+			value,
+		)
+	}
+	if err != nil {
+		p.logger.Error("Error swapping key in Postgres",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.String("key", key.String()),
+			zap.Error(err))
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// Ping checks that the database can be reached
+func (p *PostgresDatabase) Ping(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
+
+// Close closes the connections to the database
+func (p *PostgresDatabase) Close() error {
+	return p.db.Close()
+}
@@ -5,9 +5,10 @@ package database
 
 import (
 	"context"
-	"strings"
+	"net"
 
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/relayer/config"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -15,12 +16,85 @@ import (
 
 var _ RelayerDatabase = &RedisDatabase{}
 
+// Sets KEYS[1] to ARGV[3] if it is not set and ARGV[1] is "0", or if it is set to ARGV[2] and ARGV[1] is "1".
+// If KEYS[2] is given, ARGV[4] is added to the index KEYS[2], as when writing an EntryKey. Returns 1 if the
+// key was set.
+var compareAndSwapScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if ARGV[1] == "0" then
+	if current then
+		return 0
+	end
+elseif current ~= ARGV[2] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[3])
+if KEYS[2] then
+	redis.call("ZADD", KEYS[2], 0, ARGV[4])
+end
+return 1
+`)
+
+// RedisDatabase implements RelayerDatabase, storing each value under a key made of the key prefix, the relayer ID
+// and the data key. The entries of each collection are additionally indexed by a sorted set.
 type RedisDatabase struct {
-	logger logging.Logger
-	client *redis.Client
+	logger    logging.Logger
+	client    redis.UniversalClient
+	keyPrefix string
+	// Whether the relayer ID is wrapped in a hash tag, so that a Redis Cluster stores every key of a relayer ID
+	// in the same slot, as required by transactions and scripts that access multiple keys
+	hashTags bool
+}
+
+func NewRedisDatabase(
+	logger logging.Logger,
+	redisURL string,
+	cfg *config.RedisConfig,
+	relayerIDs []RelayerID,
+) (*RedisDatabase, error) {
+	client, err := NewRedisClient(logger, redisURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisDatabase{
+		logger:    logger,
+		client:    client,
+		keyPrefix: cfg.KeyPrefix,
+		hashTags:  len(cfg.ClusterAddresses) != 0,
+	}, nil
 }
 
-func NewRedisDatabase(logger logging.Logger, redisURL string, relayerIDs []RelayerID) (*RedisDatabase, error) {
+// NewRedisClient creates a client of the Redis Cluster or Sentinel configured in [cfg], if any, and of the
+// Redis server at [redisURL] otherwise.
+func NewRedisClient(logger logging.Logger, redisURL string, cfg *config.RedisConfig) (redis.UniversalClient, error) {
+	tlsConfig, err := cfg.TLS.GetTLSConfig()
+	if err != nil {
+		logger.Error(
+			"Failed to load Redis TLS configuration",
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	switch {
+	case len(cfg.ClusterAddresses) != 0:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     cfg.ClusterAddresses,
+			Username:  cfg.Username,
+			Password:  cfg.Password,
+			TLSConfig: tlsConfig,
+		}), nil
+	case len(cfg.SentinelAddresses) != 0:
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.SentinelAddresses,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			TLSConfig:        tlsConfig,
+		}), nil
+	}
+
 	opts, err := redis.ParseURL(redisURL)
 	if err != nil {
 		logger.Error(
@@ -30,20 +104,22 @@ func NewRedisDatabase(logger logging.Logger, redisURL string, relayerIDs []Relay
 		)
 		return nil, err
 	}
+	if tlsConfig != nil {
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName, _, _ = net.SplitHostPort(opts.Addr)
+		}
+		opts.TLSConfig = tlsConfig
+	}
 
 	// Create a new Redis client.
 	// The server address, password, db index, and protocol version are extracted from the URL
 	// If not provided in the URL, request timeouts use the default value of 3 seconds
-	client := redis.NewClient(opts)
-	return &RedisDatabase{
-		logger: logger,
-		client: client,
-	}, nil
+	return redis.NewClient(opts), nil
 }
 
-func (r *RedisDatabase) Get(relayerID common.Hash, key DataKey) ([]byte, error) {
+func (r *RedisDatabase) Get(relayerID common.Hash, key Key) ([]byte, error) {
 	ctx := context.Background()
-	compositeKey := constructCompositeKey(relayerID, key)
+	compositeKey := r.compositeKey(relayerID, key)
 	val, err := r.client.Get(ctx, compositeKey).Result()
 	if err != nil {
 		r.logger.Debug("Error retrieving key from Redis",
@@ -57,22 +133,141 @@ func (r *RedisDatabase) Get(relayerID common.Hash, key DataKey) ([]byte, error)
 	return []byte(val), nil
 }
 
-func (r *RedisDatabase) Put(relayerID common.Hash, key DataKey, value []byte) error {
+func (r *RedisDatabase) Put(relayerID common.Hash, key Key, value []byte) error {
+	batch := NewBatch()
+	batch.Put(key, value)
+	return r.WriteBatch(relayerID, batch)
+}
+
+func (r *RedisDatabase) Delete(relayerID common.Hash, key Key) error {
+	batch := NewBatch()
+	batch.Delete(key)
+	return r.WriteBatch(relayerID, batch)
+}
+
+// GetRange reads the IDs of the selected entries from the collection's index, and then their values.
+// Entries that are deleted between the two reads are omitted.
+func (r *RedisDatabase) GetRange(relayerID common.Hash, key DataKey, rng Range) ([]Entry, error) {
 	ctx := context.Background()
-	compositeKey := constructCompositeKey(relayerID, key)
+	indexKey := r.indexKey(relayerID, key)
+	start, end, hasEnd := rng.bounds()
+	by := &redis.ZRangeBy{
+		Min:   "[" + start,
+		Max:   "+",
+		Count: int64(rng.Limit),
+	}
+	if hasEnd {
+		by.Max = "(" + end
+	}
+	ids, err := r.client.ZRangeByLex(ctx, indexKey, by).Result()
+	if err != nil {
+		r.logger.Error("Error retrieving index from Redis",
+			zap.String("key", indexKey),
+			zap.Error(err))
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
 
-	// Persistently store the value in Redis
-	err := r.client.Set(ctx, compositeKey, value, 0).Err()
+	compositeKeys := make([]string, len(ids))
+	for i, id := range ids {
+		compositeKeys[i] = r.compositeKey(relayerID, key.Entry(id))
+	}
+	values, err := r.client.MGet(ctx, compositeKeys...).Result()
 	if err != nil {
-		r.logger.Error("Error storing key in Redis",
-			zap.String("key", compositeKey),
+		r.logger.Error("Error retrieving entries from Redis",
+			zap.String("key", indexKey),
+			zap.Error(err))
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(ids))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			entries = append(entries, Entry{ID: ids[i], Value: []byte(s)})
+		}
+	}
+	return entries, nil
+}
+
+// WriteBatch applies the writes in a MULTI/EXEC transaction. Writes to an EntryKey also update the index
+// of the entry's collection.
+func (r *RedisDatabase) WriteBatch(relayerID common.Hash, batch *Batch) error {
+	ctx := context.Background()
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, op := range batch.ops {
+			compositeKey := r.compositeKey(relayerID, op.key)
+			entryKey, isEntry := op.key.(EntryKey)
+			if op.delete {
+				pipe.Del(ctx, compositeKey)
+				if isEntry {
+					pipe.ZRem(ctx, r.indexKey(relayerID, entryKey.DataKey), entryKey.ID)
+				}
+				continue
+			}
+			// Persistently store the value in Redis
+			pipe.Set(ctx, compositeKey, op.value, 0)
+			if isEntry {
+				// All members have the same score, so that they are ordered lexicographically
+				pipe.ZAdd(ctx, r.indexKey(relayerID, entryKey.DataKey), redis.Z{Member: entryKey.ID})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Error storing keys in Redis",
+			zap.String("relayerID", relayerID.Hex()),
+			zap.Int("writes", batch.Len()),
 			zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func constructCompositeKey(relayerID common.Hash, key DataKey) string {
+// CompareAndSwap compares and writes the value in a Lua script, which Redis runs atomically
+func (r *RedisDatabase) CompareAndSwap(relayerID common.Hash, key Key, old []byte, value []byte) (bool, error) {
+	keys := []string{r.compositeKey(relayerID, key)}
+	hasOld := "0"
+	if old != nil {
+		hasOld = "1"
+	}
+	args := []interface{}{hasOld, old, value}
+	if entryKey, ok := key.(EntryKey); ok {
+		keys = append(keys, r.indexKey(relayerID, entryKey.DataKey))
+		args = append(args, entryKey.ID)
+	}
+	swapped, err := compareAndSwapScript.Run(context.Background(), r.client, keys, args...).Int()
+	if err != nil {
+		r.logger.Error("Error swapping key in Redis",
+			zap.String("key", keys[0]),
+			zap.Error(err))
+		return false, err
+	}
+	return swapped == 1, nil
+}
+
+// Ping checks that the Redis server can be reached
+func (r *RedisDatabase) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// Close closes the connections to the Redis server
+func (r *RedisDatabase) Close() error {
+	return r.client.Close()
+}
+
+// Returns the key of the value stored under [key]. Keys in a Redis Cluster use the relayer ID as their hash tag.
+func (r *RedisDatabase) compositeKey(relayerID common.Hash, key Key) string {
 	const keyDelimiter = "-"
-	return strings.Join([]string{relayerID.Hex(), key.String()}, keyDelimiter)
+	id := relayerID.Hex()
+	if r.hashTags {
+		id = "{" + id + "}"
+	}
+	return r.keyPrefix + id + keyDelimiter + key.String()
+}
+
+// Returns the key of the sorted set holding the IDs of the entries of the collection stored under [key]
+func (r *RedisDatabase) indexKey(relayerID common.Hash, key DataKey) string {
+	const indexSuffix = "-index"
+	return r.compositeKey(relayerID, key) + indexSuffix
 }
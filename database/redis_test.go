@@ -0,0 +1,86 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/relayer/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisKeys(t *testing.T) {
+	relayerID := common.Hash(ids.GenerateTestID())
+	testCases := []struct {
+		name          string
+		cfg           config.RedisConfig
+		expectedKey   string
+		expectedIndex string
+	}{
+		{
+			name:          "no prefix",
+			cfg:           config.RedisConfig{},
+			expectedKey:   relayerID.Hex() + "-retryQueue/a",
+			expectedIndex: relayerID.Hex() + "-retryQueue-index",
+		},
+		{
+			name:          "prefix",
+			cfg:           config.RedisConfig{KeyPrefix: "staging:"},
+			expectedKey:   "staging:" + relayerID.Hex() + "-retryQueue/a",
+			expectedIndex: "staging:" + relayerID.Hex() + "-retryQueue-index",
+		},
+		{
+			name:          "cluster",
+			cfg:           config.RedisConfig{KeyPrefix: "staging:", ClusterAddresses: []string{"localhost:7000"}},
+			expectedKey:   "staging:{" + relayerID.Hex() + "}-retryQueue/a",
+			expectedIndex: "staging:{" + relayerID.Hex() + "}-retryQueue-index",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, err := NewRedisDatabase(logging.NoLog{}, "redis://localhost:6379/0", &testCase.cfg, nil)
+			require.NoError(t, err)
+			defer db.Close()
+			require.Equal(t, testCase.expectedKey, db.compositeKey(relayerID, RetryQueueKey.Entry("a")))
+			require.Equal(t, testCase.expectedIndex, db.indexKey(relayerID, RetryQueueKey))
+		})
+	}
+}
+
+func TestNewRedisClient(t *testing.T) {
+	// Clients connect lazily, so no server is required
+	client, err := NewRedisClient(logging.NoLog{}, "", &config.RedisConfig{
+		ClusterAddresses: []string{"localhost:7000", "localhost:7001"},
+	})
+	require.NoError(t, err)
+	require.IsType(t, &redis.ClusterClient{}, client)
+	require.NoError(t, client.Close())
+
+	client, err = NewRedisClient(logging.NoLog{}, "", &config.RedisConfig{
+		SentinelAddresses: []string{"localhost:26379"},
+		MasterName:        "relayer",
+		DB:                2,
+	})
+	require.NoError(t, err)
+	require.IsType(t, &redis.Client{}, client)
+	require.Equal(t, 2, client.(*redis.Client).Options().DB)
+	require.NoError(t, client.Close())
+
+	// TLS options apply to the Redis URL, verifying the host of the URL by default
+	client, err = NewRedisClient(logging.NoLog{}, "redis://redis.example.com:6379/0", &config.RedisConfig{
+		TLS: config.RedisTLSConfig{Enabled: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "redis.example.com", client.(*redis.Client).Options().TLSConfig.ServerName)
+	require.NoError(t, client.Close())
+
+	_, err = NewRedisClient(logging.NoLog{}, "", &config.RedisConfig{
+		TLS: config.RedisTLSConfig{Enabled: true, CAFile: "missing.pem"},
+	})
+	require.Error(t, err)
+}
@@ -84,3 +84,66 @@ func GetLatestProcessedBlockHeight(db RelayerDatabase, relayerID RelayerID) (uin
 	}
 	return latestProcessedBlock, nil
 }
+
+// AdvanceLatestProcessedBlockHeight stores [height] as the latest processed block height of [relayerID], unless a
+// greater height is already stored. The stored height is updated with CompareAndSwap, so that it never decreases,
+// even when written concurrently by instances sharing the database. Returns the stored height.
+func AdvanceLatestProcessedBlockHeight(db RelayerDatabase, relayerID RelayerID, height uint64) (uint64, error) {
+	value := []byte(strconv.FormatUint(height, 10))
+	for {
+		old, err := db.Get(relayerID.ID, LatestProcessedBlockKey)
+		if IsKeyNotFoundError(err) {
+			old = nil
+		} else if err != nil {
+			return 0, err
+		} else {
+			storedHeight, err := strconv.ParseUint(string(old), 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			if storedHeight >= height {
+				return storedHeight, nil
+			}
+		}
+		swapped, err := db.CompareAndSwap(relayerID.ID, LatestProcessedBlockKey, old, value)
+		if err != nil {
+			return 0, err
+		}
+		if swapped {
+			return height, nil
+		}
+		// The stored height was written concurrently. Compare against the new value.
+	}
+}
+
+// Returns the bounds of the IDs selected by [r]. IDs greater than or equal to start, and less than end are
+// selected. If hasEnd is false, the IDs are not bounded from above.
+func (r Range) bounds() (start string, end string, hasEnd bool) {
+	start = r.Start
+	if r.Prefix > start {
+		start = r.Prefix
+	}
+	end, hasEnd = r.End, r.End != ""
+	if prefixEnd, ok := prefixUpperBound(r.Prefix); ok && (!hasEnd || prefixEnd < end) {
+		end, hasEnd = prefixEnd, true
+	}
+	return start, end, hasEnd
+}
+
+// Returns the smallest string that is greater than every string with [prefix],
+// or false if there is no such string.
+func prefixUpperBound(prefix string) (string, bool) {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1]), true
+		}
+	}
+	return "", false
+}
+
+// Returns true if [id] is selected by the bounds of a Range
+func inBounds(id string, start string, end string, hasEnd bool) bool {
+	return id >= start && (!hasEnd || id < end)
+}
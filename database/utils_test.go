@@ -69,7 +69,7 @@ func TestCalculateStartingBlockHeight(t *testing.T) {
 
 	for _, testCase := range testCases {
 		db := &mockDB{}
-		db.getFunc = func(relayerID common.Hash, key DataKey) ([]byte, error) {
+		db.getFunc = func(relayerID common.Hash, key Key) ([]byte, error) {
 			return []byte(strconv.FormatUint(testCase.dbBlock, 10)), testCase.dbError
 		}
 
@@ -85,13 +85,29 @@ func TestCalculateStartingBlockHeight(t *testing.T) {
 
 // in-package mock to allow for unit testing of non-receiver functions that use the RelayerDatabase interface
 type mockDB struct {
-	getFunc func(relayerID common.Hash, key DataKey) ([]byte, error)
+	getFunc func(relayerID common.Hash, key Key) ([]byte, error)
 }
 
-func (m *mockDB) Get(relayerID common.Hash, key DataKey) ([]byte, error) {
+func (m *mockDB) Get(relayerID common.Hash, key Key) ([]byte, error) {
 	return m.getFunc(relayerID, key)
 }
 
-func (m *mockDB) Put(relayerID common.Hash, key DataKey, value []byte) error {
+func (m *mockDB) Put(relayerID common.Hash, key Key, value []byte) error {
 	return nil
 }
+
+func (m *mockDB) Delete(relayerID common.Hash, key Key) error {
+	return nil
+}
+
+func (m *mockDB) GetRange(relayerID common.Hash, key DataKey, r Range) ([]Entry, error) {
+	return nil, nil
+}
+
+func (m *mockDB) WriteBatch(relayerID common.Hash, batch *Batch) error {
+	return nil
+}
+
+func (m *mockDB) CompareAndSwap(relayerID common.Hash, key Key, old []byte, value []byte) (bool, error) {
+	return true, nil
+}
@@ -19,6 +19,17 @@ type MessageHandlerFactory interface {
 	NewMessageHandler(unsignedMessage *warp.UnsignedMessage) (MessageHandler, error)
 }
 
+// Delivery describes the delivery of a Warp message to the destination chain
+type Delivery struct {
+	// Hash of the transaction that delivered the message
+	TxHash common.Hash
+	// ID of the message within its message protocol, such as the Teleporter message ID.
+	// Empty if the protocol does not identify its messages.
+	ProtocolMessageID ids.ID
+	// Gas used by the transaction. Zero if the handler does not wait for the transaction receipt.
+	GasUsed uint64
+}
+
 // MessageHandlers relay a single Warp message. A new instance should be created for each Warp message.
 type MessageHandler interface {
 	// ShouldSendMessage returns true if the message should be sent to the destination chain
@@ -27,8 +38,8 @@ type MessageHandler interface {
 
 	// SendMessage sends the signed message to the destination chain. The payload parsed according to
 	// the VM rules is also passed in, since MessageManager does not assume any particular VM
-	// returns the transaction hash if the transaction is successful.
-	SendMessage(signedMessage *warp.Message, destinationClient vms.DestinationClient) (common.Hash, error)
+	// returns the delivery of the message if the transaction is successful.
+	SendMessage(signedMessage *warp.Message, destinationClient vms.DestinationClient) (*Delivery, error)
 
 	// GetMessageRoutingInfo returns the source chain ID, origin sender address,
 	// destination chain ID, and destination address.
@@ -114,10 +114,10 @@ func (mr *MockMessageHandlerMockRecorder) GetUnsignedMessage() *gomock.Call {
 }
 
 // SendMessage mocks base method.
-func (m *MockMessageHandler) SendMessage(signedMessage *warp.Message, destinationClient vms.DestinationClient) (common.Hash, error) {
+func (m *MockMessageHandler) SendMessage(signedMessage *warp.Message, destinationClient vms.DestinationClient) (*messages.Delivery, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SendMessage", signedMessage, destinationClient)
-	ret0, _ := ret[0].(common.Hash)
+	ret0, _ := ret[0].(*messages.Delivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
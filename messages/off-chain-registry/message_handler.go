@@ -153,7 +153,7 @@ func (m *messageHandler) ShouldSendMessage(destinationClient vms.DestinationClie
 func (m *messageHandler) SendMessage(
 	signedMessage *warp.Message,
 	destinationClient vms.DestinationClient,
-) (common.Hash, error) {
+) (*messages.Delivery, error) {
 	// Construct the transaction call data to call the TeleporterRegistry contract.
 	// Only one off-chain registry Warp message is sent at a time, so we hardcode the index to 0 in the call.
 	callData, err := teleporterregistry.PackAddProtocolVersion(0)
@@ -166,7 +166,7 @@ func (m *messageHandler) SendMessage(
 			),
 			zap.String("warpMessageID", signedMessage.ID().String()),
 		)
-		return common.Hash{}, err
+		return nil, err
 	}
 
 	txHash, err := destinationClient.SendTx(
@@ -185,14 +185,15 @@ func (m *messageHandler) SendMessage(
 			zap.String("warpMessageID", signedMessage.ID().String()),
 			zap.Error(err),
 		)
-		return common.Hash{}, err
+		return nil, err
 	}
 	m.logger.Info(
 		"Sent message to destination chain",
 		zap.String("destinationBlockchainID", destinationClient.DestinationBlockchainID().String()),
 		zap.String("warpMessageID", signedMessage.ID().String()),
 	)
-	return txHash, nil
+	// The transaction receipt is not awaited, so the gas used is not known
+	return &messages.Delivery{TxHash: txHash}, nil
 }
 
 func (m *messageHandler) GetMessageRoutingInfo() (
@@ -262,7 +262,7 @@ func (m *messageHandler) getShouldSendMessageFromDecider() (bool, error) {
 func (m *messageHandler) SendMessage(
 	signedMessage *warp.Message,
 	destinationClient vms.DestinationClient,
-) (common.Hash, error) {
+) (*messages.Delivery, error) {
 	destinationBlockchainID := destinationClient.DestinationBlockchainID()
 	teleporterMessageID, err := teleporterUtils.CalculateMessageID(
 		m.factory.protocolAddress,
@@ -271,7 +271,7 @@ func (m *messageHandler) SendMessage(
 		m.teleporterMessage.MessageNonce,
 	)
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to calculate Teleporter message ID: %w", err)
+		return nil, fmt.Errorf("failed to calculate Teleporter message ID: %w", err)
 	}
 
 	m.logger.Info(
@@ -288,7 +288,7 @@ func (m *messageHandler) SendMessage(
 			zap.String("warpMessageID", signedMessage.ID().String()),
 			zap.String("teleporterMessageID", teleporterMessageID.String()),
 		)
-		return common.Hash{}, err
+		return nil, err
 	}
 
 	gasLimit, err := gasUtils.CalculateReceiveMessageGasLimit(
@@ -305,7 +305,7 @@ func (m *messageHandler) SendMessage(
 			zap.String("warpMessageID", signedMessage.ID().String()),
 			zap.String("teleporterMessageID", teleporterMessageID.String()),
 		)
-		return common.Hash{}, err
+		return nil, err
 	}
 	// Construct the transaction call data to call the receive cross chain message method of the receiver precompile.
 	callData, err := teleportermessenger.PackReceiveCrossChainMessage(
@@ -319,7 +319,7 @@ func (m *messageHandler) SendMessage(
 			zap.String("warpMessageID", signedMessage.ID().String()),
 			zap.String("teleporterMessageID", teleporterMessageID.String()),
 		)
-		return common.Hash{}, err
+		return nil, err
 	}
 
 	txHash, err := destinationClient.SendTx(
@@ -336,13 +336,13 @@ func (m *messageHandler) SendMessage(
 			zap.String("teleporterMessageID", teleporterMessageID.String()),
 			zap.Error(err),
 		)
-		return common.Hash{}, err
+		return nil, err
 	}
 
 	// Wait for the message to be included in a block before returning
-	err = m.waitForReceipt(signedMessage, destinationClient, txHash, teleporterMessageID)
+	receipt, err := m.waitForReceipt(signedMessage, destinationClient, txHash, teleporterMessageID)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
 
 	m.logger.Info(
@@ -352,7 +352,11 @@ func (m *messageHandler) SendMessage(
 		zap.String("teleporterMessageID", teleporterMessageID.String()),
 		zap.String("txHash", txHash.String()),
 	)
-	return txHash, nil
+	return &messages.Delivery{
+		TxHash:            txHash,
+		ProtocolMessageID: teleporterMessageID,
+		GasUsed:           receipt.GasUsed,
+	}, nil
 }
 
 func (m *messageHandler) waitForReceipt(
@@ -360,7 +364,7 @@ func (m *messageHandler) waitForReceipt(
 	destinationClient vms.DestinationClient,
 	txHash common.Hash,
 	teleporterMessageID ids.ID,
-) error {
+) (*types.Receipt, error) {
 	destinationBlockchainID := destinationClient.DestinationBlockchainID()
 	callCtx, callCtxCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer callCtxCancel()
@@ -378,7 +382,7 @@ func (m *messageHandler) waitForReceipt(
 			zap.String("teleporterMessageID", teleporterMessageID.String()),
 			zap.Error(err),
 		)
-		return err
+		return nil, err
 	}
 	if receipt.Status != types.ReceiptStatusSuccessful {
 		m.logger.Error(
@@ -388,9 +392,9 @@ func (m *messageHandler) waitForReceipt(
 			zap.String("teleporterMessageID", teleporterMessageID.String()),
 			zap.String("txHash", txHash.String()),
 		)
-		return fmt.Errorf("transaction failed with status: %d", receipt.Status)
+		return nil, fmt.Errorf("transaction failed with status: %d", receipt.Status)
 	}
-	return nil
+	return receipt, nil
 }
 
 // parseTeleporterMessage returns the Warp message's corresponding Teleporter message from the cache if it exists.
@@ -31,12 +31,13 @@ const (
 )
 
 type AppRequestNetwork interface {
-	ConnectPeers(nodeIDs set.Set[ids.NodeID]) set.Set[ids.NodeID]
-	ConnectToCanonicalValidators(subnetID ids.ID) (
+	ConnectPeers(ctx context.Context, nodeIDs set.Set[ids.NodeID]) set.Set[ids.NodeID]
+	ConnectToCanonicalValidators(ctx context.Context, subnetID ids.ID, pChainHeight uint64) (
 		*ConnectedCanonicalValidators,
 		error,
 	)
-	GetSubnetID(blockchainID ids.ID) (ids.ID, error)
+	GetSubnetID(ctx context.Context, blockchainID ids.ID) (ids.ID, error)
+	GetProposedHeight(ctx context.Context, blockchainID ids.ID) (uint64, error)
 	RegisterAppRequest(requestID ids.RequestID)
 	RegisterRequestID(
 		requestID uint32,
@@ -118,7 +119,11 @@ func NewNetwork(
 		return nil, err
 	}
 
-	validatorClient := validators.NewCanonicalValidatorClient(logger, cfg.GetPChainAPI())
+	validatorClient := validators.NewCanonicalValidatorClient(
+		logger,
+		cfg.GetPChainAPI(),
+		cfg.GetValidatorSetCacheConfig(),
+	)
 
 	arNetwork := &appRequestNetwork{
 		network:         testNetwork,
@@ -138,7 +143,7 @@ func NewNetwork(
 
 // ConnectPeers connects the network to peers with the given nodeIDs.
 // Returns the set of nodeIDs that were successfully connected to.
-func (n *appRequestNetwork) ConnectPeers(nodeIDs set.Set[ids.NodeID]) set.Set[ids.NodeID] {
+func (n *appRequestNetwork) ConnectPeers(ctx context.Context, nodeIDs set.Set[ids.NodeID]) set.Set[ids.NodeID] {
 	n.lock.Lock()
 	defer n.lock.Unlock()
 
@@ -155,7 +160,7 @@ func (n *appRequestNetwork) ConnectPeers(nodeIDs set.Set[ids.NodeID]) set.Set[id
 
 	startInfoAPICall := time.Now()
 	// Get the list of peers
-	peers, err := n.infoAPI.Peers(context.Background())
+	peers, err := n.infoAPI.Peers(ctx)
 	n.setInfoAPICallLatencyMS(float64(time.Since(startInfoAPICall).Milliseconds()))
 	if err != nil {
 		n.logger.Error(
@@ -180,7 +185,7 @@ func (n *appRequestNetwork) ConnectPeers(nodeIDs set.Set[ids.NodeID]) set.Set[id
 	// If the Info API node is in nodeIDs, it will not be reflected in the call to info.Peers.
 	// In this case, we need to manually track the API node.
 	startInfoAPICall = time.Now()
-	apiNodeID, _, err := n.infoAPI.GetNodeID(context.Background())
+	apiNodeID, _, err := n.infoAPI.GetNodeID(ctx)
 	n.setInfoAPICallLatencyMS(float64(time.Since(startInfoAPICall).Milliseconds()))
 	if err != nil {
 		n.logger.Error(
@@ -189,7 +194,7 @@ func (n *appRequestNetwork) ConnectPeers(nodeIDs set.Set[ids.NodeID]) set.Set[id
 		)
 	} else if nodeIDs.Contains(apiNodeID) {
 		startInfoAPICall = time.Now()
-		apiNodeIPPort, err := n.infoAPI.GetNodeIP(context.Background())
+		apiNodeIPPort, err := n.infoAPI.GetNodeIP(ctx)
 		n.setInfoAPICallLatencyMS(float64(time.Since(startInfoAPICall).Milliseconds()))
 		if err != nil {
 			n.logger.Error(
@@ -213,6 +218,8 @@ type ConnectedCanonicalValidators struct {
 	TotalValidatorWeight  uint64
 	ValidatorSet          []*warp.Validator
 	NodeValidatorIndexMap map[ids.NodeID]int
+	// The validator nodes that were successfully connected to
+	ConnectedNodes set.Set[ids.NodeID]
 }
 
 // Returns the Warp Validator and its index in the canonical Validator ordering for a given nodeID
@@ -220,12 +227,20 @@ func (c *ConnectedCanonicalValidators) GetValidator(nodeID ids.NodeID) (*warp.Va
 	return c.ValidatorSet[c.NodeValidatorIndexMap[nodeID]], c.NodeValidatorIndexMap[nodeID]
 }
 
-// ConnectToCanonicalValidators connects to the canonical validators of the given subnet and returns the connected
-// validator information
-func (n *appRequestNetwork) ConnectToCanonicalValidators(subnetID ids.ID) (*ConnectedCanonicalValidators, error) {
-	// Get the subnet's current canonical validator set
+// ConnectToCanonicalValidators connects to the canonical validators of the given subnet at [pChainHeight] and returns
+// the connected validator information. If [pChainHeight] is zero, the current canonical validators are used.
+func (n *appRequestNetwork) ConnectToCanonicalValidators(
+	ctx context.Context,
+	subnetID ids.ID,
+	pChainHeight uint64,
+) (*ConnectedCanonicalValidators, error) {
+	// Get the subnet's canonical validator set
 	startPChainAPICall := time.Now()
-	validatorSet, totalValidatorWeight, err := n.validatorClient.GetCurrentCanonicalValidatorSet(subnetID)
+	validatorSet, totalValidatorWeight, err := n.validatorClient.GetCanonicalValidatorSet(
+		ctx,
+		subnetID,
+		pChainHeight,
+	)
 	n.setPChainAPICallLatencyMS(float64(time.Since(startPChainAPICall).Milliseconds()))
 	if err != nil {
 		return nil, err
@@ -247,7 +262,7 @@ func (n *appRequestNetwork) ConnectToCanonicalValidators(subnetID ids.ID) (*Conn
 	for node := range nodeValidatorIndexMap {
 		nodeIDs.Add(node)
 	}
-	connectedNodes := n.ConnectPeers(nodeIDs)
+	connectedNodes := n.ConnectPeers(ctx, nodeIDs)
 
 	// Check if we've connected to a stake threshold of nodes
 	connectedWeight := uint64(0)
@@ -259,6 +274,7 @@ func (n *appRequestNetwork) ConnectToCanonicalValidators(subnetID ids.ID) (*Conn
 		TotalValidatorWeight:  totalValidatorWeight,
 		ValidatorSet:          validatorSet,
 		NodeValidatorIndexMap: nodeValidatorIndexMap,
+		ConnectedNodes:        connectedNodes,
 	}, nil
 }
 
@@ -277,8 +293,13 @@ func (n *appRequestNetwork) RegisterAppRequest(requestID ids.RequestID) {
 func (n *appRequestNetwork) RegisterRequestID(requestID uint32, numExpectedResponse int) chan message.InboundMessage {
 	return n.handler.RegisterRequestID(requestID, numExpectedResponse)
 }
-func (n *appRequestNetwork) GetSubnetID(blockchainID ids.ID) (ids.ID, error) {
-	return n.validatorClient.GetSubnetID(context.Background(), blockchainID)
+func (n *appRequestNetwork) GetSubnetID(ctx context.Context, blockchainID ids.ID) (ids.ID, error) {
+	return n.validatorClient.GetSubnetID(ctx, blockchainID)
+}
+
+// GetProposedHeight returns the P-Chain height that the given blockchain currently verifies Warp messages against
+func (n *appRequestNetwork) GetProposedHeight(ctx context.Context, blockchainID ids.ID) (uint64, error) {
+	return n.validatorClient.GetProposedHeight(ctx, blockchainID)
 }
 
 //
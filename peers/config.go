@@ -9,4 +9,5 @@ import "github.com/ava-labs/awm-relayer/config"
 type Config interface {
 	GetInfoAPI() *config.APIConfig
 	GetPChainAPI() *config.APIConfig
+	GetValidatorSetCacheConfig() *config.ValidatorSetCacheConfig
 }
@@ -10,6 +10,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	ids "github.com/ava-labs/avalanchego/ids"
@@ -44,47 +45,62 @@ func (m *MockAppRequestNetwork) EXPECT() *MockAppRequestNetworkMockRecorder {
 }
 
 // ConnectPeers mocks base method.
-func (m *MockAppRequestNetwork) ConnectPeers(nodeIDs set.Set[ids.NodeID]) set.Set[ids.NodeID] {
+func (m *MockAppRequestNetwork) ConnectPeers(ctx context.Context, nodeIDs set.Set[ids.NodeID]) set.Set[ids.NodeID] {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ConnectPeers", nodeIDs)
+	ret := m.ctrl.Call(m, "ConnectPeers", ctx, nodeIDs)
 	ret0, _ := ret[0].(set.Set[ids.NodeID])
 	return ret0
 }
 
 // ConnectPeers indicates an expected call of ConnectPeers.
-func (mr *MockAppRequestNetworkMockRecorder) ConnectPeers(nodeIDs any) *gomock.Call {
+func (mr *MockAppRequestNetworkMockRecorder) ConnectPeers(ctx, nodeIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectPeers", reflect.TypeOf((*MockAppRequestNetwork)(nil).ConnectPeers), nodeIDs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectPeers", reflect.TypeOf((*MockAppRequestNetwork)(nil).ConnectPeers), ctx, nodeIDs)
 }
 
 // ConnectToCanonicalValidators mocks base method.
-func (m *MockAppRequestNetwork) ConnectToCanonicalValidators(subnetID ids.ID) (*peers.ConnectedCanonicalValidators, error) {
+func (m *MockAppRequestNetwork) ConnectToCanonicalValidators(ctx context.Context, subnetID ids.ID, pChainHeight uint64) (*peers.ConnectedCanonicalValidators, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ConnectToCanonicalValidators", subnetID)
+	ret := m.ctrl.Call(m, "ConnectToCanonicalValidators", ctx, subnetID, pChainHeight)
 	ret0, _ := ret[0].(*peers.ConnectedCanonicalValidators)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ConnectToCanonicalValidators indicates an expected call of ConnectToCanonicalValidators.
-func (mr *MockAppRequestNetworkMockRecorder) ConnectToCanonicalValidators(subnetID any) *gomock.Call {
+func (mr *MockAppRequestNetworkMockRecorder) ConnectToCanonicalValidators(ctx, subnetID, pChainHeight any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectToCanonicalValidators", reflect.TypeOf((*MockAppRequestNetwork)(nil).ConnectToCanonicalValidators), subnetID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectToCanonicalValidators", reflect.TypeOf((*MockAppRequestNetwork)(nil).ConnectToCanonicalValidators), ctx, subnetID, pChainHeight)
+}
+
+// GetProposedHeight mocks base method.
+func (m *MockAppRequestNetwork) GetProposedHeight(ctx context.Context, blockchainID ids.ID) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProposedHeight", ctx, blockchainID)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProposedHeight indicates an expected call of GetProposedHeight.
+func (mr *MockAppRequestNetworkMockRecorder) GetProposedHeight(ctx, blockchainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProposedHeight", reflect.TypeOf((*MockAppRequestNetwork)(nil).GetProposedHeight), ctx, blockchainID)
 }
 
 // GetSubnetID mocks base method.
-func (m *MockAppRequestNetwork) GetSubnetID(blockchainID ids.ID) (ids.ID, error) {
+func (m *MockAppRequestNetwork) GetSubnetID(ctx context.Context, blockchainID ids.ID) (ids.ID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSubnetID", blockchainID)
+	ret := m.ctrl.Call(m, "GetSubnetID", ctx, blockchainID)
 	ret0, _ := ret[0].(ids.ID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSubnetID indicates an expected call of GetSubnetID.
-func (mr *MockAppRequestNetworkMockRecorder) GetSubnetID(blockchainID any) *gomock.Call {
+func (mr *MockAppRequestNetworkMockRecorder) GetSubnetID(ctx, blockchainID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetID", reflect.TypeOf((*MockAppRequestNetwork)(nil).GetSubnetID), blockchainID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetID", reflect.TypeOf((*MockAppRequestNetwork)(nil).GetSubnetID), ctx, blockchainID)
 }
 
 // RegisterAppRequest mocks base method.
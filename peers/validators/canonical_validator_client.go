@@ -5,9 +5,14 @@ package validators
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	avajson "github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
@@ -17,29 +22,75 @@ import (
 	"go.uber.org/zap"
 )
 
-var _ validators.State = &CanonicalValidatorClient{}
+var (
+	_ validators.State = &CanonicalValidatorClient{}
+	_ validators.State = currentValidatorState{}
+)
 
 // CanonicalValidatorClient wraps platformvm.Client and implements validators.State
 type CanonicalValidatorClient struct {
 	logger  logging.Logger
 	client  platformvm.Client
+	baseURL string
 	options []rpc.Option
+
+	cacheConfig config.ValidatorSetCacheConfig
+	// protected by cacheLock
+	validatorSetCache map[ids.ID]*cachedValidatorSet
+	// The most recently fetched validator set of each subnet at an explicit P-Chain height.
+	// protected by cacheLock
+	heightValidatorSetCache map[ids.ID]*cachedValidatorSet
+	cacheLock               sync.Mutex
+}
+
+// A canonical validator set pinned to the P-Chain height at which it was fetched.
+type cachedValidatorSet struct {
+	validatorSet         []*avalancheWarp.Validator
+	totalValidatorWeight uint64
+	pChainHeight         uint64
+	// The last time the validator set was fetched or confirmed to be within the allowed P-Chain height delta
+	refreshedAt time.Time
 }
 
-func NewCanonicalValidatorClient(logger logging.Logger, apiConfig *config.APIConfig) *CanonicalValidatorClient {
+func NewCanonicalValidatorClient(
+	logger logging.Logger,
+	apiConfig *config.APIConfig,
+	cacheConfig *config.ValidatorSetCacheConfig,
+) *CanonicalValidatorClient {
 	client := platformvm.NewClient(apiConfig.BaseURL)
 	options := utils.InitializeOptions(apiConfig)
-	return &CanonicalValidatorClient{
-		logger:  logger,
-		client:  client,
-		options: options,
+	v := &CanonicalValidatorClient{
+		logger:                  logger,
+		client:                  client,
+		baseURL:                 strings.TrimSuffix(apiConfig.BaseURL, "/"),
+		options:                 options,
+		validatorSetCache:       make(map[ids.ID]*cachedValidatorSet),
+		heightValidatorSetCache: make(map[ids.ID]*cachedValidatorSet),
+	}
+	if cacheConfig != nil {
+		v.cacheConfig = *cacheConfig
 	}
+	return v
 }
 
+// GetCurrentCanonicalValidatorSet returns the canonical validator set of the given subnet. If the validator set
+// cache is enabled, a previously fetched validator set is returned as long as it is within the configured refresh
+// interval or P-Chain height delta.
 func (v *CanonicalValidatorClient) GetCurrentCanonicalValidatorSet(
+	ctx context.Context,
 	subnetID ids.ID,
 ) ([]*avalancheWarp.Validator, uint64, error) {
-	height, err := v.GetCurrentHeight(context.Background())
+	cached := v.getCachedValidatorSet(subnetID)
+	if cached != nil && time.Since(cached.refreshedAt) < v.cacheConfig.RefreshInterval() {
+		v.logger.Debug(
+			"Using cached canonical validator set",
+			zap.String("subnetID", subnetID.String()),
+			zap.Uint64("pChainHeight", cached.pChainHeight),
+		)
+		return cached.validatorSet, cached.totalValidatorWeight, nil
+	}
+
+	height, err := v.GetCurrentHeight(ctx)
 	if err != nil {
 		v.logger.Error(
 			"Failed to get P-Chain height",
@@ -48,10 +99,25 @@ func (v *CanonicalValidatorClient) GetCurrentCanonicalValidatorSet(
 		return nil, 0, err
 	}
 
+	// Reuse the cached validator set if the P-Chain has not advanced too far since it was fetched.
+	if cached != nil &&
+		v.cacheConfig.MaxPChainHeightDelta != 0 &&
+		height >= cached.pChainHeight &&
+		height-cached.pChainHeight <= v.cacheConfig.MaxPChainHeightDelta {
+		v.logger.Debug(
+			"Using cached canonical validator set within P-Chain height delta",
+			zap.String("subnetID", subnetID.String()),
+			zap.Uint64("pChainHeight", cached.pChainHeight),
+			zap.Uint64("currentPChainHeight", height),
+		)
+		v.refreshCachedValidatorSet(subnetID, cached)
+		return cached.validatorSet, cached.totalValidatorWeight, nil
+	}
+
 	// Get the current canonical validator set of the source subnet.
 	canonicalSubnetValidators, totalValidatorWeight, err := avalancheWarp.GetCanonicalValidatorSet(
-		context.Background(),
-		v,
+		ctx,
+		currentValidatorState{v},
 		height,
 		subnetID,
 	)
@@ -63,10 +129,140 @@ func (v *CanonicalValidatorClient) GetCurrentCanonicalValidatorSet(
 		)
 		return nil, 0, err
 	}
+	v.setCachedValidatorSet(subnetID, &cachedValidatorSet{
+		validatorSet:         canonicalSubnetValidators,
+		totalValidatorWeight: totalValidatorWeight,
+		pChainHeight:         height,
+		refreshedAt:          time.Now(),
+	})
 
 	return canonicalSubnetValidators, totalValidatorWeight, nil
 }
 
+// GetCanonicalValidatorSet returns the canonical validator set of the given subnet at [pChainHeight]. If
+// [pChainHeight] is zero, the current canonical validator set is returned, as by GetCurrentCanonicalValidatorSet.
+// If the validator set cache is enabled, the most recently fetched validator set at an explicit height is
+// cached per subnet, since the validator set at a given height never changes. Unlike the current validator
+// set, an explicit height requires the P-Chain API to support getValidatorsAt.
+func (v *CanonicalValidatorClient) GetCanonicalValidatorSet(
+	ctx context.Context,
+	subnetID ids.ID,
+	pChainHeight uint64,
+) ([]*avalancheWarp.Validator, uint64, error) {
+	if pChainHeight == 0 {
+		return v.GetCurrentCanonicalValidatorSet(ctx, subnetID)
+	}
+	if cached := v.getCachedValidatorSetAtHeight(subnetID, pChainHeight); cached != nil {
+		v.logger.Debug(
+			"Using cached canonical validator set",
+			zap.String("subnetID", subnetID.String()),
+			zap.Uint64("pChainHeight", pChainHeight),
+		)
+		return cached.validatorSet, cached.totalValidatorWeight, nil
+	}
+	canonicalSubnetValidators, totalValidatorWeight, err := avalancheWarp.GetCanonicalValidatorSet(
+		ctx,
+		v,
+		pChainHeight,
+		subnetID,
+	)
+	if err != nil {
+		v.logger.Error(
+			"Failed to get the canonical subnet validator set",
+			zap.String("subnetID", subnetID.String()),
+			zap.Uint64("pChainHeight", pChainHeight),
+			zap.Error(err),
+		)
+		return nil, 0, err
+	}
+	v.setCachedValidatorSetAtHeight(subnetID, &cachedValidatorSet{
+		validatorSet:         canonicalSubnetValidators,
+		totalValidatorWeight: totalValidatorWeight,
+		pChainHeight:         pChainHeight,
+		refreshedAt:          time.Now(),
+	})
+	return canonicalSubnetValidators, totalValidatorWeight, nil
+}
+
+// GetProposedHeight returns the P-Chain height that the proposervm of the given blockchain would currently
+// use for a new block, which is the height against which Warp messages delivered to it are verified.
+// The P-Chain API node must track the blockchain, and serve the proposervm API.
+func (v *CanonicalValidatorClient) GetProposedHeight(ctx context.Context, blockchainID ids.ID) (uint64, error) {
+	requester := rpc.NewEndpointRequester(
+		fmt.Sprintf("%s/ext/bc/%s/proposervm", v.baseURL, blockchainID),
+	)
+	var res struct {
+		Height avajson.Uint64 `json:"height"`
+	}
+	err := requester.SendRequest(ctx, "proposervm.getProposedHeight", struct{}{}, &res, v.options...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get proposed P-Chain height of blockchain %s: %w", blockchainID, err)
+	}
+	return uint64(res.Height), nil
+}
+
+// Returns the cached validator set for the subnet, or nil if caching is disabled or the subnet is not cached.
+func (v *CanonicalValidatorClient) getCachedValidatorSet(subnetID ids.ID) *cachedValidatorSet {
+	if !v.cacheConfig.Enabled() {
+		return nil
+	}
+	v.cacheLock.Lock()
+	defer v.cacheLock.Unlock()
+	return v.validatorSetCache[subnetID]
+}
+
+func (v *CanonicalValidatorClient) setCachedValidatorSet(subnetID ids.ID, cached *cachedValidatorSet) {
+	if !v.cacheConfig.Enabled() {
+		return
+	}
+	v.cacheLock.Lock()
+	defer v.cacheLock.Unlock()
+	v.validatorSetCache[subnetID] = cached
+}
+
+// Returns the cached validator set for the subnet at [pChainHeight], or nil if caching is disabled or
+// the validator set at that height is not cached.
+func (v *CanonicalValidatorClient) getCachedValidatorSetAtHeight(
+	subnetID ids.ID,
+	pChainHeight uint64,
+) *cachedValidatorSet {
+	if !v.cacheConfig.Enabled() {
+		return nil
+	}
+	v.cacheLock.Lock()
+	defer v.cacheLock.Unlock()
+	cached := v.heightValidatorSetCache[subnetID]
+	if cached == nil || cached.pChainHeight != pChainHeight {
+		return nil
+	}
+	return cached
+}
+
+// Replaces the cached validator set of the subnet at an explicit height. Only the most recent height is kept,
+// since the destination's proposed height only moves forward.
+func (v *CanonicalValidatorClient) setCachedValidatorSetAtHeight(subnetID ids.ID, cached *cachedValidatorSet) {
+	if !v.cacheConfig.Enabled() {
+		return
+	}
+	v.cacheLock.Lock()
+	defer v.cacheLock.Unlock()
+	if existing := v.heightValidatorSetCache[subnetID]; existing != nil && existing.pChainHeight > cached.pChainHeight {
+		return
+	}
+	v.heightValidatorSetCache[subnetID] = cached
+}
+
+// Marks the cached validator set as refreshed, without modifying the pinned P-Chain height.
+// Cached entries are treated as immutable, so a new entry replaces the existing one.
+func (v *CanonicalValidatorClient) refreshCachedValidatorSet(subnetID ids.ID, cached *cachedValidatorSet) {
+	v.setCachedValidatorSet(subnetID, &cachedValidatorSet{
+		validatorSet:         cached.validatorSet,
+		totalValidatorWeight: cached.totalValidatorWeight,
+		pChainHeight:         cached.pChainHeight,
+		refreshedAt:          time.Now(),
+	})
+}
+
 func (v *CanonicalValidatorClient) GetMinimumHeight(ctx context.Context) (uint64, error) {
 	return v.client.GetHeight(ctx, v.options...)
 }
@@ -79,24 +275,48 @@ func (v *CanonicalValidatorClient) GetSubnetID(ctx context.Context, blockchainID
 	return v.client.ValidatedBy(ctx, blockchainID, v.options...)
 }
 
-// Gets the validator set of the given subnet at the given P-chain block height.
-// Attempts to use the "getValidatorsAt" API first. If not available, falls back
-// to use "getCurrentValidators", ignoring the specified P-chain block height.
+// Gets the validator set of the given subnet at the given P-chain block height using the
+// "getValidatorsAt" API. Errors are returned rather than falling back to the current validator
+// set, so that callers never act on a validator set at a different height than requested.
 func (v *CanonicalValidatorClient) GetValidatorSet(
 	ctx context.Context,
 	height uint64,
 	subnetID ids.ID,
 ) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
-	// First, attempt to use the "getValidatorsAt" RPC method. This method may not be available on
-	// all API nodes, in which case we can fall back to using "getCurrentValidators" if needed.
 	res, err := v.client.GetValidatorsAt(ctx, subnetID, height, v.options...)
 	if err != nil {
 		v.logger.Debug(
+			"P-chain RPC to getValidatorAt returned error",
+			zap.String("subnetID", subnetID.String()),
+			zap.Uint64("pChainHeight", height),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get validator set of subnet %s at P-Chain height %d: %w", subnetID, height, err)
+	}
+	return res, nil
+}
+
+// currentValidatorState is used to fetch the validator set at the current P-Chain height.
+// Attempts to use the "getValidatorsAt" API first. If not available, falls back to use
+// "getCurrentValidators", which is equivalent as long as the height is the current one.
+type currentValidatorState struct {
+	*CanonicalValidatorClient
+}
+
+func (s currentValidatorState) GetValidatorSet(
+	ctx context.Context,
+	height uint64,
+	subnetID ids.ID,
+) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	// First, attempt to use the "getValidatorsAt" RPC method. This method may not be available on
+	// all API nodes, in which case we can fall back to using "getCurrentValidators" if needed.
+	res, err := s.client.GetValidatorsAt(ctx, subnetID, height, s.options...)
+	if err != nil {
+		s.logger.Debug(
 			"P-chain RPC to getValidatorAt returned error. Falling back to getCurrentValidators",
 			zap.String("subnetID", subnetID.String()),
 			zap.Uint64("pChainHeight", height),
 			zap.Error(err))
-		return v.getCurrentValidatorSet(ctx, subnetID)
+		return s.getCurrentValidatorSet(ctx, subnetID)
 	}
 	return res, nil
 }
@@ -0,0 +1,274 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/awm-relayer/config"
+	"github.com/stretchr/testify/require"
+)
+
+// pChainClientStub implements the subset of platformvm.Client used by CanonicalValidatorClient,
+// and counts the number of calls made to each method.
+type pChainClientStub struct {
+	platformvm.Client
+	height               uint64
+	validatorSet         map[ids.NodeID]*validators.GetValidatorOutput
+	getHeightCalls       int
+	getValidatorsAtCalls int
+	// The P-Chain height of each GetValidatorsAt call
+	validatorsAtHeights []uint64
+	// If set, GetValidatorsAt returns this error, as for API nodes that do not support it
+	getValidatorsAtErr        error
+	getCurrentValidatorsCalls int
+}
+
+func (p *pChainClientStub) GetHeight(context.Context, ...rpc.Option) (uint64, error) {
+	p.getHeightCalls++
+	return p.height, nil
+}
+
+func (p *pChainClientStub) GetValidatorsAt(
+	_ context.Context,
+	_ ids.ID,
+	height uint64,
+	_ ...rpc.Option,
+) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	p.getValidatorsAtCalls++
+	p.validatorsAtHeights = append(p.validatorsAtHeights, height)
+	if p.getValidatorsAtErr != nil {
+		return nil, p.getValidatorsAtErr
+	}
+	return p.validatorSet, nil
+}
+
+func (p *pChainClientStub) GetCurrentValidators(
+	_ context.Context,
+	_ ids.ID,
+	_ []ids.NodeID,
+	_ ...rpc.Option,
+) ([]platformvm.ClientPermissionlessValidator, error) {
+	p.getCurrentValidatorsCalls++
+	res := make([]platformvm.ClientPermissionlessValidator, 0, len(p.validatorSet))
+	for nodeID, validator := range p.validatorSet {
+		res = append(res, platformvm.ClientPermissionlessValidator{
+			ClientStaker: platformvm.ClientStaker{
+				NodeID: nodeID,
+				Weight: validator.Weight,
+			},
+		})
+	}
+	return res, nil
+}
+
+func newPChainClientStub(t *testing.T, validatorCount int) *pChainClientStub {
+	validatorSet := make(map[ids.NodeID]*validators.GetValidatorOutput, validatorCount)
+	for i := 0; i < validatorCount; i++ {
+		secretKey, err := bls.NewSecretKey()
+		require.NoError(t, err)
+		nodeID := ids.GenerateTestNodeID()
+		validatorSet[nodeID] = &validators.GetValidatorOutput{
+			NodeID:    nodeID,
+			PublicKey: bls.PublicFromSecretKey(secretKey),
+			Weight:    1,
+		}
+	}
+	return &pChainClientStub{
+		height:       100,
+		validatorSet: validatorSet,
+	}
+}
+
+func TestGetCurrentCanonicalValidatorSetCache(t *testing.T) {
+	testCases := []struct {
+		name                         string
+		cacheConfig                  config.ValidatorSetCacheConfig
+		heightIncrease               uint64
+		expectedGetHeightCalls       int
+		expectedGetValidatorsAtCalls int
+	}{
+		{
+			name:                         "cache disabled",
+			cacheConfig:                  config.ValidatorSetCacheConfig{},
+			expectedGetHeightCalls:       2,
+			expectedGetValidatorsAtCalls: 2,
+		},
+		{
+			name: "within refresh interval",
+			cacheConfig: config.ValidatorSetCacheConfig{
+				RefreshIntervalSeconds: 60,
+			},
+			heightIncrease:               100,
+			expectedGetHeightCalls:       1,
+			expectedGetValidatorsAtCalls: 1,
+		},
+		{
+			name: "within P-Chain height delta",
+			cacheConfig: config.ValidatorSetCacheConfig{
+				MaxPChainHeightDelta: 10,
+			},
+			heightIncrease:               10,
+			expectedGetHeightCalls:       2,
+			expectedGetValidatorsAtCalls: 1,
+		},
+		{
+			name: "exceeds P-Chain height delta",
+			cacheConfig: config.ValidatorSetCacheConfig{
+				MaxPChainHeightDelta: 10,
+			},
+			heightIncrease:               11,
+			expectedGetHeightCalls:       2,
+			expectedGetValidatorsAtCalls: 2,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			stub := newPChainClientStub(t, 3)
+			client := &CanonicalValidatorClient{
+				logger:                  logging.NoLog{},
+				client:                  stub,
+				cacheConfig:             testCase.cacheConfig,
+				validatorSetCache:       make(map[ids.ID]*cachedValidatorSet),
+				heightValidatorSetCache: make(map[ids.ID]*cachedValidatorSet),
+			}
+			subnetID := ids.GenerateTestID()
+			ctx := context.Background()
+
+			validatorSet, totalWeight, err := client.GetCurrentCanonicalValidatorSet(ctx, subnetID)
+			require.NoError(t, err)
+			require.Len(t, validatorSet, 3)
+			require.Equal(t, uint64(3), totalWeight)
+
+			stub.height += testCase.heightIncrease
+			cachedValidatorSet, cachedTotalWeight, err := client.GetCurrentCanonicalValidatorSet(ctx, subnetID)
+			require.NoError(t, err)
+			require.Equal(t, validatorSet, cachedValidatorSet)
+			require.Equal(t, totalWeight, cachedTotalWeight)
+
+			require.Equal(t, testCase.expectedGetHeightCalls, stub.getHeightCalls)
+			require.Equal(t, testCase.expectedGetValidatorsAtCalls, stub.getValidatorsAtCalls)
+		})
+	}
+}
+
+func TestGetCurrentCanonicalValidatorSetCacheExpires(t *testing.T) {
+	stub := newPChainClientStub(t, 1)
+	client := &CanonicalValidatorClient{
+		logger:                  logging.NoLog{},
+		client:                  stub,
+		cacheConfig:             config.ValidatorSetCacheConfig{RefreshIntervalSeconds: 60},
+		validatorSetCache:       make(map[ids.ID]*cachedValidatorSet),
+		heightValidatorSetCache: make(map[ids.ID]*cachedValidatorSet),
+	}
+	subnetID := ids.GenerateTestID()
+	ctx := context.Background()
+
+	_, _, err := client.GetCurrentCanonicalValidatorSet(ctx, subnetID)
+	require.NoError(t, err)
+
+	// Age the cached entry past the refresh interval
+	client.validatorSetCache[subnetID].refreshedAt = time.Now().Add(-2 * time.Minute)
+
+	_, _, err = client.GetCurrentCanonicalValidatorSet(ctx, subnetID)
+	require.NoError(t, err)
+	require.Equal(t, 2, stub.getHeightCalls)
+	require.Equal(t, 2, stub.getValidatorsAtCalls)
+}
+
+func TestGetCanonicalValidatorSetAtHeight(t *testing.T) {
+	stub := newPChainClientStub(t, 3)
+	client := &CanonicalValidatorClient{
+		logger: logging.NoLog{},
+		client: stub,
+		cacheConfig: config.ValidatorSetCacheConfig{
+			RefreshIntervalSeconds: 60,
+		},
+		validatorSetCache:       make(map[ids.ID]*cachedValidatorSet),
+		heightValidatorSetCache: make(map[ids.ID]*cachedValidatorSet),
+	}
+	subnetID := ids.GenerateTestID()
+	ctx := context.Background()
+
+	// A zero height uses the current validator set, which is cached
+	_, _, err := client.GetCanonicalValidatorSet(ctx, subnetID, 0)
+	require.NoError(t, err)
+
+	// An explicit height does not use the current validator set, nor query the current height
+	validatorSet, totalWeight, err := client.GetCanonicalValidatorSet(ctx, subnetID, 42)
+	require.NoError(t, err)
+	require.Len(t, validatorSet, 3)
+	require.Equal(t, uint64(3), totalWeight)
+	require.Equal(t, 1, stub.getHeightCalls)
+	require.Equal(t, []uint64{stub.height, 42}, stub.validatorsAtHeights)
+
+	// The validator set at the same height is cached
+	cachedValidatorSet, cachedTotalWeight, err := client.GetCanonicalValidatorSet(ctx, subnetID, 42)
+	require.NoError(t, err)
+	require.Equal(t, validatorSet, cachedValidatorSet)
+	require.Equal(t, totalWeight, cachedTotalWeight)
+	require.Equal(t, []uint64{stub.height, 42}, stub.validatorsAtHeights)
+
+	// A later height replaces the cached validator set, and an earlier height is never cached over it
+	_, _, err = client.GetCanonicalValidatorSet(ctx, subnetID, 43)
+	require.NoError(t, err)
+	_, _, err = client.GetCanonicalValidatorSet(ctx, subnetID, 42)
+	require.NoError(t, err)
+	_, _, err = client.GetCanonicalValidatorSet(ctx, subnetID, 43)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{stub.height, 42, 43, 42}, stub.validatorsAtHeights)
+}
+
+func TestGetCanonicalValidatorSetAtHeightNotCachedWhenDisabled(t *testing.T) {
+	stub := newPChainClientStub(t, 1)
+	client := &CanonicalValidatorClient{
+		logger:                  logging.NoLog{},
+		client:                  stub,
+		validatorSetCache:       make(map[ids.ID]*cachedValidatorSet),
+		heightValidatorSetCache: make(map[ids.ID]*cachedValidatorSet),
+	}
+	subnetID := ids.GenerateTestID()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, _, err := client.GetCanonicalValidatorSet(ctx, subnetID, 42)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, stub.getValidatorsAtCalls)
+}
+
+func TestGetValidatorsAtUnsupported(t *testing.T) {
+	stub := newPChainClientStub(t, 3)
+	stub.getValidatorsAtErr = errors.New("method not found")
+	client := &CanonicalValidatorClient{
+		logger:                  logging.NoLog{},
+		client:                  stub,
+		validatorSetCache:       make(map[ids.ID]*cachedValidatorSet),
+		heightValidatorSetCache: make(map[ids.ID]*cachedValidatorSet),
+	}
+	subnetID := ids.GenerateTestID()
+	ctx := context.Background()
+
+	// The current validator set falls back to getCurrentValidators
+	_, totalWeight, err := client.GetCanonicalValidatorSet(ctx, subnetID, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), totalWeight)
+	require.Equal(t, 2, stub.getCurrentValidatorsCalls)
+
+	// An explicit height is never silently replaced by the current validator set
+	_, _, err = client.GetCanonicalValidatorSet(ctx, subnetID, 42)
+	require.ErrorIs(t, err, stub.getValidatorsAtErr)
+	_, err = client.GetValidatorSet(ctx, 42, subnetID)
+	require.ErrorIs(t, err, stub.getValidatorsAtErr)
+	require.Equal(t, 2, stub.getCurrentValidatorsCalls)
+}
@@ -0,0 +1,155 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: signatureaggregator/signature_aggregator.proto
+
+package signatureaggregator
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	SignatureAggregatorService_AggregateSignatures_FullMethodName      = "/signatureaggregator.SignatureAggregatorService/AggregateSignatures"
+	SignatureAggregatorService_AggregateSignaturesBatch_FullMethodName = "/signatureaggregator.SignatureAggregatorService/AggregateSignaturesBatch"
+)
+
+// SignatureAggregatorServiceClient is the client API for SignatureAggregatorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SignatureAggregatorServiceClient interface {
+	// Aggregates signatures for a single unsigned Warp message. If the signatures cannot be aggregated due to
+	// insufficient connected or signed stake weight, the returned status includes an AggregationErrorDetails.
+	AggregateSignatures(ctx context.Context, in *AggregateSignaturesRequest, opts ...grpc.CallOption) (*AggregateSignaturesResponse, error)
+	// Aggregates signatures for a batch of unsigned Warp messages, connecting to each signing subnet's
+	// validators once for the whole batch.
+	AggregateSignaturesBatch(ctx context.Context, in *AggregateSignaturesBatchRequest, opts ...grpc.CallOption) (*AggregateSignaturesBatchResponse, error)
+}
+
+type signatureAggregatorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignatureAggregatorServiceClient(cc grpc.ClientConnInterface) SignatureAggregatorServiceClient {
+	return &signatureAggregatorServiceClient{cc}
+}
+
+func (c *signatureAggregatorServiceClient) AggregateSignatures(ctx context.Context, in *AggregateSignaturesRequest, opts ...grpc.CallOption) (*AggregateSignaturesResponse, error) {
+	out := new(AggregateSignaturesResponse)
+	err := c.cc.Invoke(ctx, SignatureAggregatorService_AggregateSignatures_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signatureAggregatorServiceClient) AggregateSignaturesBatch(ctx context.Context, in *AggregateSignaturesBatchRequest, opts ...grpc.CallOption) (*AggregateSignaturesBatchResponse, error) {
+	out := new(AggregateSignaturesBatchResponse)
+	err := c.cc.Invoke(ctx, SignatureAggregatorService_AggregateSignaturesBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignatureAggregatorServiceServer is the server API for SignatureAggregatorService service.
+// All implementations must embed UnimplementedSignatureAggregatorServiceServer
+// for forward compatibility
+type SignatureAggregatorServiceServer interface {
+	// Aggregates signatures for a single unsigned Warp message. If the signatures cannot be aggregated due to
+	// insufficient connected or signed stake weight, the returned status includes an AggregationErrorDetails.
+	AggregateSignatures(context.Context, *AggregateSignaturesRequest) (*AggregateSignaturesResponse, error)
+	// Aggregates signatures for a batch of unsigned Warp messages, connecting to each signing subnet's
+	// validators once for the whole batch.
+	AggregateSignaturesBatch(context.Context, *AggregateSignaturesBatchRequest) (*AggregateSignaturesBatchResponse, error)
+	mustEmbedUnimplementedSignatureAggregatorServiceServer()
+}
+
+// UnimplementedSignatureAggregatorServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedSignatureAggregatorServiceServer struct {
+}
+
+func (UnimplementedSignatureAggregatorServiceServer) AggregateSignatures(context.Context, *AggregateSignaturesRequest) (*AggregateSignaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AggregateSignatures not implemented")
+}
+func (UnimplementedSignatureAggregatorServiceServer) AggregateSignaturesBatch(context.Context, *AggregateSignaturesBatchRequest) (*AggregateSignaturesBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AggregateSignaturesBatch not implemented")
+}
+func (UnimplementedSignatureAggregatorServiceServer) mustEmbedUnimplementedSignatureAggregatorServiceServer() {
+}
+
+// UnsafeSignatureAggregatorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SignatureAggregatorServiceServer will
+// result in compilation errors.
+type UnsafeSignatureAggregatorServiceServer interface {
+	mustEmbedUnimplementedSignatureAggregatorServiceServer()
+}
+
+func RegisterSignatureAggregatorServiceServer(s grpc.ServiceRegistrar, srv SignatureAggregatorServiceServer) {
+	s.RegisterService(&SignatureAggregatorService_ServiceDesc, srv)
+}
+
+func _SignatureAggregatorService_AggregateSignatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateSignaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignatureAggregatorServiceServer).AggregateSignatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignatureAggregatorService_AggregateSignatures_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignatureAggregatorServiceServer).AggregateSignatures(ctx, req.(*AggregateSignaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SignatureAggregatorService_AggregateSignaturesBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateSignaturesBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignatureAggregatorServiceServer).AggregateSignaturesBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignatureAggregatorService_AggregateSignaturesBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignatureAggregatorServiceServer).AggregateSignaturesBatch(ctx, req.(*AggregateSignaturesBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SignatureAggregatorService_ServiceDesc is the grpc.ServiceDesc for SignatureAggregatorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SignatureAggregatorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "signatureaggregator.SignatureAggregatorService",
+	HandlerType: (*SignatureAggregatorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AggregateSignatures",
+			Handler:    _SignatureAggregatorService_AggregateSignatures_Handler,
+		},
+		{
+			MethodName: "AggregateSignaturesBatch",
+			Handler:    _SignatureAggregatorService_AggregateSignaturesBatch_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "signatureaggregator/signature_aggregator.proto",
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/alexliesenfeld/health"
 	"github.com/ava-labs/avalanchego/ids"
@@ -12,14 +13,33 @@ import (
 	"go.uber.org/zap"
 )
 
-const HealthAPIPath = "/health"
+const (
+	HealthAPIPath = "/health"
 
-func HandleHealthCheck(logger logging.Logger, relayerHealth map[ids.ID]*atomic.Bool) {
-	http.Handle(HealthAPIPath, healthCheckHandler(logger, relayerHealth))
+	dependencyCheckTimeout = 5 * time.Second
+)
+
+// Pinger checks the connection to a service the relayer depends on, such as its database
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HandleHealthCheck serves the health of the relayers of each source blockchain, and of the connection to
+// each of [dependencies], by name
+func HandleHealthCheck(
+	logger logging.Logger,
+	relayerHealth map[ids.ID]*atomic.Bool,
+	dependencies map[string]Pinger,
+) {
+	http.Handle(HealthAPIPath, healthCheckHandler(logger, relayerHealth, dependencies))
 }
 
-func healthCheckHandler(logger logging.Logger, relayerHealth map[ids.ID]*atomic.Bool) http.Handler {
-	return health.NewHandler(health.NewChecker(
+func healthCheckHandler(
+	logger logging.Logger,
+	relayerHealth map[ids.ID]*atomic.Bool,
+	dependencies map[string]Pinger,
+) http.Handler {
+	opts := []health.CheckerOption{
 		health.WithCheck(health.Check{
 			Name: "relayers-all",
 			Check: func(context.Context) error {
@@ -38,5 +58,19 @@ func healthCheckHandler(logger logging.Logger, relayerHealth map[ids.ID]*atomic.
 				return nil
 			},
 		}),
-	))
+	}
+	for name, pinger := range dependencies {
+		opts = append(opts, health.WithCheck(health.Check{
+			Name:    name,
+			Timeout: dependencyCheckTimeout,
+			Check: func(ctx context.Context) error {
+				if err := pinger.Ping(ctx); err != nil {
+					logger.Error("Failed to ping dependency", zap.String("dependency", name), zap.Error(err))
+					return fmt.Errorf("failed to ping %s: %w", name, err)
+				}
+				return nil
+			},
+		}))
+	}
+	return health.NewHandler(health.NewChecker(opts...))
 }
@@ -0,0 +1,305 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ava-labs/awm-relayer/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+const (
+	MessagesAPIPath = "/messages"
+
+	defaultListMessagesLimit = 50
+	maxListMessagesLimit     = 1000
+)
+
+type ListMessagesResponse struct {
+	// Deliveries, most recent first
+	Messages []*database.DeliveryRecord `json:"messages"`
+	// Passed as the cursor query parameter to list the next page. Empty if there are no more deliveries.
+	NextCursor string `json:"next-cursor"`
+}
+
+// A message that is no longer retried after failing to be relayed too many times
+type DeadLetter struct {
+	RelayerID common.Hash `json:"relayer-id"`
+	*database.RetryEntry
+}
+
+type ListDeadLettersResponse struct {
+	DeadLetters []DeadLetter `json:"dead-letters"`
+}
+
+type RequeueDeadLetterResponse struct {
+	// The dead letters of the message that were moved back to the retry queue, as they were before being requeued
+	Requeued []DeadLetter `json:"requeued"`
+}
+
+// HandleMessages registers the endpoints to look up the deliveries recorded by [relayerIDs]:
+//   - GET /messages/{id} returns the most recent delivery of the Warp message with the cb58 or hex encoded ID
+//   - GET /messages lists deliveries, most recent first. Accepts the optional query parameters relayer-id,
+//     source-blockchain-id and destination-blockchain-id to filter the deliveries, and limit and cursor to
+//     paginate them.
+//   - GET /messages/dead-letters lists the messages that are no longer retried after failing to be relayed too
+//     many times. Accepts the same filters as GET /messages.
+//   - POST /messages/dead-letters/{id}/requeue moves the dead letters of the Warp message with the cb58 or hex
+//     encoded ID back to the retry queue, to be retried immediately with their attempts reset. Accepts the same
+//     filters as GET /messages.
+func HandleMessages(logger logging.Logger, db database.RelayerDatabase, relayerIDs []database.RelayerID) {
+	http.Handle("GET "+MessagesAPIPath+"/dead-letters", listDeadLettersAPIHandler(logger, db, relayerIDs))
+	http.Handle(
+		"POST "+MessagesAPIPath+"/dead-letters/{id}/requeue",
+		requeueDeadLetterAPIHandler(logger, db, relayerIDs),
+	)
+	http.Handle("GET "+MessagesAPIPath+"/{id}", getMessageAPIHandler(logger, db, relayerIDs))
+	http.Handle("GET "+MessagesAPIPath, listMessagesAPIHandler(logger, db, relayerIDs))
+}
+
+func getMessageAPIHandler(
+	logger logging.Logger,
+	db database.RelayerDatabase,
+	relayerIDs []database.RelayerID,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		messageID, err := utils.HexOrCB58ToID(r.PathValue("id"))
+		if err != nil {
+			logger.Warn("Invalid messageID", zap.String("messageID", r.PathValue("id")))
+			http.Error(w, "invalid messageID: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		record, err := database.GetDeliveryRecord(db, relayerIDs, messageID)
+		if database.IsKeyNotFoundError(err) {
+			http.Error(w, "message not delivered", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			logger.Error("Error getting message delivery", zap.Error(err))
+			http.Error(w, "error getting message delivery: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(logger, w, record)
+	})
+}
+
+func listMessagesAPIHandler(
+	logger logging.Logger,
+	db database.RelayerDatabase,
+	relayerIDs []database.RelayerID,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		limit := defaultListMessagesLimit
+		if limitParam := query.Get("limit"); limitParam != "" {
+			var err error
+			limit, err = strconv.Atoi(limitParam)
+			if err != nil || limit <= 0 || limit > maxListMessagesLimit {
+				http.Error(
+					w,
+					"limit must be an integer between 1 and "+strconv.Itoa(maxListMessagesLimit),
+					http.StatusBadRequest,
+				)
+				return
+			}
+		}
+		filteredRelayerIDs, err := filterRelayerIDs(
+			relayerIDs,
+			query.Get("relayer-id"),
+			query.Get("source-blockchain-id"),
+			query.Get("destination-blockchain-id"),
+		)
+		if err != nil {
+			logger.Warn("Invalid filter", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		records, nextCursor, err := database.ListDeliveryRecords(db, filteredRelayerIDs, query.Get("cursor"), limit)
+		if err != nil {
+			logger.Error("Error listing message deliveries", zap.Error(err))
+			http.Error(w, "error listing message deliveries: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if records == nil {
+			records = []*database.DeliveryRecord{}
+		}
+		writeJSON(logger, w, ListMessagesResponse{
+			Messages:   records,
+			NextCursor: nextCursor,
+		})
+	})
+}
+
+func listDeadLettersAPIHandler(
+	logger logging.Logger,
+	db database.RelayerDatabase,
+	relayerIDs []database.RelayerID,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filteredRelayerIDs, err := filterRelayerIDs(
+			relayerIDs,
+			query.Get("relayer-id"),
+			query.Get("source-blockchain-id"),
+			query.Get("destination-blockchain-id"),
+		)
+		if err != nil {
+			logger.Warn("Invalid filter", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		deadLetters := []DeadLetter{}
+		for _, relayerID := range filteredRelayerIDs {
+			entries, err := database.ListDeadLetters(db, relayerID.ID)
+			if err != nil {
+				logger.Error("Error listing dead letters", zap.Error(err))
+				http.Error(w, "error listing dead letters: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			for _, entry := range entries {
+				deadLetters = append(deadLetters, DeadLetter{
+					RelayerID:  relayerID.ID,
+					RetryEntry: entry,
+				})
+			}
+		}
+		writeJSON(logger, w, ListDeadLettersResponse{DeadLetters: deadLetters})
+	})
+}
+
+func requeueDeadLetterAPIHandler(
+	logger logging.Logger,
+	db database.RelayerDatabase,
+	relayerIDs []database.RelayerID,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		messageID, err := utils.HexOrCB58ToID(r.PathValue("id"))
+		if err != nil {
+			logger.Warn("Invalid messageID", zap.String("messageID", r.PathValue("id")))
+			http.Error(w, "invalid messageID: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		query := r.URL.Query()
+		filteredRelayerIDs, err := filterRelayerIDs(
+			relayerIDs,
+			query.Get("relayer-id"),
+			query.Get("source-blockchain-id"),
+			query.Get("destination-blockchain-id"),
+		)
+		if err != nil {
+			logger.Warn("Invalid filter", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		requeued := []DeadLetter{}
+		for _, relayerID := range filteredRelayerIDs {
+			entries, err := database.ListDeadLetters(db, relayerID.ID)
+			if err != nil {
+				logger.Error("Error listing dead letters", zap.Error(err))
+				http.Error(w, "error listing dead letters: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			for _, entry := range entries {
+				if entry.WarpMessageID != messageID {
+					continue
+				}
+				if err := database.RequeueDeadLetter(db, relayerID.ID, entry); err != nil {
+					logger.Error("Error requeueing dead letter", zap.Error(err))
+					http.Error(w, "error requeueing dead letter: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				logger.Info(
+					"Requeued dead letter",
+					zap.String("warpMessageID", messageID.String()),
+					zap.String("relayerID", relayerID.ID.String()),
+				)
+				requeued = append(requeued, DeadLetter{
+					RelayerID:  relayerID.ID,
+					RetryEntry: entry,
+				})
+			}
+		}
+		if len(requeued) == 0 {
+			http.Error(w, "message not in the dead letters", http.StatusNotFound)
+			return
+		}
+		writeJSON(logger, w, RequeueDeadLetterResponse{Requeued: requeued})
+	})
+}
+
+// Returns the relayer IDs of [relayerIDs] that match each of the filters that are set
+func filterRelayerIDs(
+	relayerIDs []database.RelayerID,
+	relayerIDParam string,
+	sourceBlockchainIDParam string,
+	destinationBlockchainIDParam string,
+) ([]database.RelayerID, error) {
+	var (
+		relayerID                                   common.Hash
+		sourceBlockchainID, destinationBlockchainID ids.ID
+		err                                         error
+	)
+	if relayerIDParam != "" {
+		relayerIDBytes, err := utils.HexOrCB58ToID(relayerIDParam)
+		if err != nil {
+			return nil, errInvalidParam("relayer-id", err)
+		}
+		relayerID = common.Hash(relayerIDBytes)
+	}
+	if sourceBlockchainIDParam != "" {
+		if sourceBlockchainID, err = utils.HexOrCB58ToID(sourceBlockchainIDParam); err != nil {
+			return nil, errInvalidParam("source-blockchain-id", err)
+		}
+	}
+	if destinationBlockchainIDParam != "" {
+		if destinationBlockchainID, err = utils.HexOrCB58ToID(destinationBlockchainIDParam); err != nil {
+			return nil, errInvalidParam("destination-blockchain-id", err)
+		}
+	}
+
+	var filtered []database.RelayerID
+	for _, id := range relayerIDs {
+		if relayerID != (common.Hash{}) && id.ID != relayerID {
+			continue
+		}
+		if sourceBlockchainID != ids.Empty && id.SourceBlockchainID != sourceBlockchainID {
+			continue
+		}
+		if destinationBlockchainID != ids.Empty && id.DestinationBlockchainID != destinationBlockchainID {
+			continue
+		}
+		filtered = append(filtered, id)
+	}
+	return filtered, nil
+}
+
+func errInvalidParam(name string, err error) error {
+	return fmt.Errorf("invalid %s: %w", name, err)
+}
+
+func writeJSON(logger logging.Logger, w http.ResponseWriter, v interface{}) {
+	resp, err := json.Marshal(v)
+	if err != nil {
+		logger.Error("Error marshalling response", zap.Error(err))
+		http.Error(w, "error marshalling response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(resp)
+	if err != nil {
+		logger.Error("Error writing response", zap.Error(err))
+	}
+}
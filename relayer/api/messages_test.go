@@ -0,0 +1,291 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a mux serving the message endpoints registered by HandleMessages
+func newMessagesTestMux(db database.RelayerDatabase, relayerIDs []database.RelayerID) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("GET "+MessagesAPIPath+"/dead-letters", listDeadLettersAPIHandler(logging.NoLog{}, db, relayerIDs))
+	mux.Handle(
+		"POST "+MessagesAPIPath+"/dead-letters/{id}/requeue",
+		requeueDeadLetterAPIHandler(logging.NoLog{}, db, relayerIDs),
+	)
+	mux.Handle("GET "+MessagesAPIPath+"/{id}", getMessageAPIHandler(logging.NoLog{}, db, relayerIDs))
+	mux.Handle("GET "+MessagesAPIPath, listMessagesAPIHandler(logging.NoLog{}, db, relayerIDs))
+	return mux
+}
+
+func get(handler http.Handler, path string) *httptest.ResponseRecorder {
+	return serve(handler, http.MethodGet, path)
+}
+
+func serve(handler http.Handler, method string, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+// Creates relayer IDs from source A to destinations A and B, and from source B to destination A,
+// and records a delivery by each of them in turn, one second apart. Returns the deliveries, oldest first.
+func newTestDeliveryHistory(
+	t *testing.T,
+	deliveries int,
+) (database.RelayerDatabase, []database.RelayerID, []*database.DeliveryRecord) {
+	var (
+		sourceA      = ids.GenerateTestID()
+		sourceB      = ids.GenerateTestID()
+		destinationA = ids.GenerateTestID()
+		destinationB = ids.GenerateTestID()
+	)
+	relayerIDs := []database.RelayerID{
+		database.NewRelayerID(sourceA, destinationA, common.Address{}, common.Address{}),
+		database.NewRelayerID(sourceA, destinationB, common.Address{}, common.Address{}),
+		database.NewRelayerID(sourceB, destinationA, common.Address{}, common.Address{}),
+	}
+	db, err := database.NewJSONFileStorage(logging.NoLog{}, t.TempDir(), relayerIDs)
+	require.NoError(t, err)
+
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := make([]*database.DeliveryRecord, deliveries)
+	for i := range records {
+		relayerID := relayerIDs[i%len(relayerIDs)]
+		records[i] = &database.DeliveryRecord{
+			WarpMessageID:           ids.GenerateTestID(),
+			RelayerID:               relayerID.ID,
+			SourceBlockchainID:      relayerID.SourceBlockchainID,
+			DestinationBlockchainID: relayerID.DestinationBlockchainID,
+			SourceBlockNumber:       uint64(100 + i),
+			DestinationTxHash:       common.BigToHash(common.Big1),
+			DeliveredAt:             startTime.Add(time.Duration(i) * time.Second),
+		}
+		require.NoError(t, database.PutDeliveryRecord(db, records[i], 0))
+	}
+	return db, relayerIDs, records
+}
+
+func TestGetMessage(t *testing.T) {
+	db, relayerIDs, records := newTestDeliveryHistory(t, 3)
+	mux := newMessagesTestMux(db, relayerIDs)
+	messageID := records[1].WarpMessageID
+
+	testCases := []struct {
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{
+			name:           "cb58 ID",
+			id:             messageID.String(),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "hex ID",
+			id:             "0x" + messageID.Hex(),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not delivered",
+			id:             ids.GenerateTestID().String(),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid ID",
+			id:             "invalid",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := get(mux, MessagesAPIPath+"/"+testCase.id)
+			require.Equal(t, testCase.expectedStatus, w.Code, w.Body.String())
+			if testCase.expectedStatus != http.StatusOK {
+				return
+			}
+			var record database.DeliveryRecord
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &record))
+			require.Equal(t, records[1], &record)
+		})
+	}
+}
+
+func TestListMessagesPages(t *testing.T) {
+	db, relayerIDs, records := newTestDeliveryHistory(t, 5)
+	mux := newMessagesTestMux(db, relayerIDs)
+
+	// Pages of two deliveries across all relayer IDs, most recent first, until the cursor is empty
+	var (
+		listed []*database.DeliveryRecord
+		cursor string
+		pages  int
+	)
+	for {
+		query := url.Values{"limit": {"2"}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		w := get(mux, MessagesAPIPath+"?"+query.Encode())
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp ListMessagesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.LessOrEqual(t, len(resp.Messages), 2)
+		listed = append(listed, resp.Messages...)
+		pages++
+		if resp.NextCursor == "" {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+	require.Equal(t, 3, pages)
+	require.Equal(t, []*database.DeliveryRecord{records[4], records[3], records[2], records[1], records[0]}, listed)
+
+	// The default limit returns every delivery in a single page
+	w := get(mux, MessagesAPIPath)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp ListMessagesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, listed, resp.Messages)
+	require.Empty(t, resp.NextCursor)
+
+	// A cursor past the last delivery returns an empty list rather than null
+	w = get(mux, MessagesAPIPath+"?cursor=~")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.JSONEq(t, `{"messages":[],"next-cursor":""}`, w.Body.String())
+}
+
+func TestListMessagesFilters(t *testing.T) {
+	db, relayerIDs, records := newTestDeliveryHistory(t, 6)
+	mux := newMessagesTestMux(db, relayerIDs)
+
+	testCases := []struct {
+		name     string
+		query    url.Values
+		expected []*database.DeliveryRecord
+	}{
+		{
+			name:     "relayer ID",
+			query:    url.Values{"relayer-id": {relayerIDs[1].ID.Hex()}},
+			expected: []*database.DeliveryRecord{records[4], records[1]},
+		},
+		{
+			name:     "source blockchain ID",
+			query:    url.Values{"source-blockchain-id": {relayerIDs[0].SourceBlockchainID.String()}},
+			expected: []*database.DeliveryRecord{records[4], records[3], records[1], records[0]},
+		},
+		{
+			name:     "destination blockchain ID",
+			query:    url.Values{"destination-blockchain-id": {relayerIDs[0].DestinationBlockchainID.String()}},
+			expected: []*database.DeliveryRecord{records[5], records[3], records[2], records[0]},
+		},
+		{
+			name: "source and destination blockchain IDs",
+			query: url.Values{
+				"source-blockchain-id":      {relayerIDs[0].SourceBlockchainID.String()},
+				"destination-blockchain-id": {relayerIDs[0].DestinationBlockchainID.String()},
+			},
+			expected: []*database.DeliveryRecord{records[3], records[0]},
+		},
+		{
+			name:     "no matching relayer IDs",
+			query:    url.Values{"source-blockchain-id": {ids.GenerateTestID().String()}},
+			expected: []*database.DeliveryRecord{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := get(mux, MessagesAPIPath+"?"+testCase.query.Encode())
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var resp ListMessagesResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Equal(t, testCase.expected, resp.Messages)
+			require.Empty(t, resp.NextCursor)
+		})
+	}
+}
+
+func TestListMessagesInvalidParameters(t *testing.T) {
+	db, relayerIDs, _ := newTestDeliveryHistory(t, 1)
+	mux := newMessagesTestMux(db, relayerIDs)
+
+	for _, query := range []url.Values{
+		{"limit": {"0"}},
+		{"limit": {"1001"}},
+		{"limit": {"ten"}},
+		{"relayer-id": {"invalid"}},
+		{"source-blockchain-id": {"0xzz"}},
+		{"destination-blockchain-id": {"invalid"}},
+	} {
+		t.Run(query.Encode(), func(t *testing.T) {
+			w := get(mux, MessagesAPIPath+"?"+query.Encode())
+			require.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestRequeueDeadLetter(t *testing.T) {
+	db, relayerIDs, _ := newTestDeliveryHistory(t, 0)
+	mux := newMessagesTestMux(db, relayerIDs)
+
+	// The same message is a dead letter of two relayer IDs, alongside another message
+	queuedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	messageID := ids.GenerateTestID()
+	entries := []*database.RetryEntry{
+		{WarpMessageID: messageID, SourceBlockNumber: 1, Attempts: 3, LastError: "a", QueuedAt: queuedAt},
+		{WarpMessageID: messageID, SourceBlockNumber: 1, Attempts: 3, LastError: "b", QueuedAt: queuedAt},
+		{WarpMessageID: ids.GenerateTestID(), SourceBlockNumber: 2, Attempts: 3, LastError: "c", QueuedAt: queuedAt},
+	}
+	for i, entry := range entries {
+		require.NoError(t, database.MoveToDeadLetters(db, relayerIDs[i].ID, entry))
+	}
+	w := get(mux, MessagesAPIPath+"/dead-letters")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var listResp ListDeadLettersResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	require.Len(t, listResp.DeadLetters, 3)
+
+	// Only the dead letter of the filtered relayer ID is requeued
+	requeuePath := MessagesAPIPath + "/dead-letters/" + messageID.String() + "/requeue"
+	w = serve(mux, http.MethodPost, requeuePath+"?relayer-id="+relayerIDs[1].ID.Hex())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp RequeueDeadLetterResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, []DeadLetter{{RelayerID: relayerIDs[1].ID, RetryEntry: entries[1]}}, resp.Requeued)
+	queued, err := database.ListRetryEntries(db, relayerIDs[1].ID)
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	require.Zero(t, queued[0].Attempts)
+
+	// Without filters, the remaining dead letter of the message is requeued, and the other message is left
+	w = serve(mux, http.MethodPost, "/messages/dead-letters/0x"+messageID.Hex()+"/requeue")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	resp = RequeueDeadLetterResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, []DeadLetter{{RelayerID: relayerIDs[0].ID, RetryEntry: entries[0]}}, resp.Requeued)
+	w = get(mux, MessagesAPIPath+"/dead-letters")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	listResp = ListDeadLettersResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	require.Equal(t, []DeadLetter{{RelayerID: relayerIDs[2].ID, RetryEntry: entries[2]}}, listResp.DeadLetters)
+
+	// Messages that are no longer dead letters are not found, and invalid parameters are rejected
+	require.Equal(t, http.StatusNotFound, serve(mux, http.MethodPost, requeuePath).Code)
+	require.Equal(t, http.StatusBadRequest, serve(mux, http.MethodPost, "/messages/dead-letters/invalid/requeue").Code)
+	require.Equal(t, http.StatusBadRequest, serve(mux, http.MethodPost, requeuePath+"?relayer-id=invalid").Code)
+	require.Equal(t, http.StatusMethodNotAllowed, serve(mux, http.MethodGet, requeuePath).Code)
+}
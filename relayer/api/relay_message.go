@@ -68,7 +68,7 @@ func relayMessageAPIHandler(logger logging.Logger, messageCoordinator *relayer.M
 			UnsignedMessage: unsignedMessage,
 		}
 
-		txHash, err := messageCoordinator.ProcessWarpMessage(warpMessageInfo)
+		txHash, err := messageCoordinator.ProcessWarpMessage(r.Context(), warpMessageInfo)
 		if err != nil {
 			logger.Error("Error processing message", zap.Error(err))
 			http.Error(w, "error processing message: "+err.Error(), http.StatusInternalServerError)
@@ -116,7 +116,12 @@ func relayAPIHandler(logger logging.Logger, messageCoordinator *relayer.MessageC
 			return
 		}
 
-		txHash, err := messageCoordinator.ProcessMessageID(blockchainID, messageID, new(big.Int).SetUint64(req.BlockNum))
+		txHash, err := messageCoordinator.ProcessMessageID(
+			r.Context(),
+			blockchainID,
+			messageID,
+			new(big.Int).SetUint64(req.BlockNum),
+		)
 		if err != nil {
 			logger.Error("Error processing message", zap.Error(err))
 			http.Error(w, "error processing message: "+err.Error(), http.StatusInternalServerError)
@@ -6,12 +6,16 @@ package relayer
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
 	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	basecfg "github.com/ava-labs/awm-relayer/config"
 	"github.com/ava-labs/awm-relayer/database"
 	"github.com/ava-labs/awm-relayer/messages"
 	"github.com/ava-labs/awm-relayer/peers"
@@ -27,14 +31,6 @@ import (
 	"go.uber.org/zap"
 )
 
-const (
-	// Number of retries to collect signatures from validators
-	maxRelayerQueryAttempts = 5
-	// Maximum amount of time to spend waiting (in addition to network round trip time per attempt)
-	// during relayer signature query routine
-	signatureRequestRetryWaitPeriodMs = 10_000
-)
-
 var (
 	// Errors
 	errFailedToGetAggSig = errors.New("failed to get aggregate signature from node endpoint")
@@ -49,6 +45,27 @@ type CheckpointManager interface {
 	// greater than the current committedHeight, it is instead cached in memory
 	// to potentially be committed later.
 	StageCommittedHeight(height uint64)
+	// FastForward advances the committed height to [height] if it is greater, and returns the committed height.
+	FastForward(height uint64) uint64
+}
+
+// LeaderElector reports whether this instance is the leader of a relayer ID, when instances that share a
+// database elect a leader to relay for each relayer ID
+type LeaderElector interface {
+	IsLeader(relayerID common.Hash) bool
+}
+
+// LeaderElectors combines the leader electors of multiple deployment modes, such as sharding and leader
+// election. This instance is the leader of a relayer ID if it is the leader according to each of them.
+type LeaderElectors []LeaderElector
+
+func (electors LeaderElectors) IsLeader(relayerID common.Hash) bool {
+	for _, elector := range electors {
+		if !elector.IsLeader(relayerID) {
+			return false
+		}
+	}
+	return true
 }
 
 // ApplicationRelayers define a Warp message route from a specific source address on a specific source blockchain
@@ -64,8 +81,19 @@ type ApplicationRelayer struct {
 	relayerID                 database.RelayerID
 	warpQuorum                config.WarpQuorum
 	checkpointManager         CheckpointManager
+	db                        database.RelayerDatabase
 	sourceWarpSignatureClient *rpc.Client // nil if configured to fetch signatures via AppRequest for the source blockchain
 	signatureAggregator       *aggregator.SignatureAggregator
+	retryPolicy               basecfg.RetryPolicy
+	// Backoff of the messages in the retry queue
+	messageRetryInterval    time.Duration
+	messageRetryMaxInterval time.Duration
+	messageRetryMaxAttempts uint64
+	// Time for which deliveries are kept in the delivery history
+	deliveryHistoryRetention time.Duration
+	leaderElector            LeaderElector // nil if leader election is disabled
+	// Whether this instance was the leader of the relayer ID as of the last processed block
+	leading atomic.Bool
 }
 
 func NewApplicationRelayer(
@@ -76,8 +104,10 @@ func NewApplicationRelayer(
 	destinationClient vms.DestinationClient,
 	sourceBlockchain config.SourceBlockchain,
 	checkpointManager CheckpointManager,
+	db database.RelayerDatabase,
 	cfg *config.Config,
 	signatureAggregator *aggregator.SignatureAggregator,
+	leaderElector LeaderElector,
 ) (*ApplicationRelayer, error) {
 	quorum, err := cfg.GetWarpQuorum(relayerID.DestinationBlockchainID)
 	if err != nil {
@@ -130,27 +160,69 @@ func NewApplicationRelayer(
 		signingSubnetID:           signingSubnet,
 		warpQuorum:                quorum,
 		checkpointManager:         checkpointManager,
+		db:                        db,
 		sourceWarpSignatureClient: warpClient,
 		signatureAggregator:       signatureAggregator,
+		retryPolicy:               cfg.SignatureRetryPolicy.WithDefaults(),
+		messageRetryInterval:      cfg.GetMessageRetryInterval(),
+		messageRetryMaxInterval:   cfg.GetMessageRetryMaxInterval(),
+		messageRetryMaxAttempts:   cfg.GetMessageRetryMaxAttempts(),
+		deliveryHistoryRetention:  cfg.GetDeliveryHistoryRetention(),
+		leaderElector:             leaderElector,
 	}
+	// The leader at startup processes blocks from its starting height like any other relayer.
+	// Otherwise, it catches up from the checkpoint of the previous leader once it takes over.
+	ar.leading.Store(ar.isLeader())
 
 	return &ar, nil
 }
 
 // Process [msgs] at height [height] by relaying each message to the destination chain.
-// Checkpoints the height with the checkpoint manager when all messages are relayed.
+// Progress is tracked per message: each message is recorded as processed once it is relayed, and messages that
+// fail to be relayed are queued to be retried, so that a single failing message does not block the checkpoint.
+// Messages that were already processed, such as before a restart, are skipped. Checkpoints the height with the
+// checkpoint manager when all messages are processed.
 // ProcessHeight is expected to be called for every block greater than or equal to the
 // [startingHeight] provided in the constructor.
 func (r *ApplicationRelayer) ProcessHeight(
+	ctx context.Context,
 	height uint64,
 	handlers []messages.MessageHandler,
 	errChan chan error,
 ) {
+	var processed set.Set[ids.ID]
+	if len(handlers) != 0 {
+		var err error
+		processed, err = database.GetProcessedMessages(r.db, r.relayerID.ID, height)
+		if err != nil {
+			r.logger.Error(
+				"Failed to get processed messages",
+				zap.Uint64("height", height),
+				zap.String("relayerID", r.relayerID.ID.String()),
+				zap.Error(err),
+			)
+			errChan <- err
+			return
+		}
+	}
 	var eg errgroup.Group
 	for _, handler := range handlers {
+		messageID := handler.GetUnsignedMessage().ID()
+		if processed.Contains(messageID) {
+			r.logger.Debug(
+				"Message already processed. Skipping.",
+				zap.Uint64("height", height),
+				zap.String("warpMessageID", messageID.String()),
+				zap.String("relayerID", r.relayerID.ID.String()),
+			)
+			continue
+		}
 		eg.Go(func() error {
-			_, err := r.ProcessMessage(handler)
-			return err
+			if _, err := r.ProcessMessage(ctx, handler, height); err != nil {
+				return r.queueRetry(height, messageID, err)
+			}
+			r.markProcessed(height, messageID)
+			return nil
 		})
 	}
 	if err := eg.Wait(); err != nil {
@@ -173,9 +245,134 @@ func (r *ApplicationRelayer) ProcessHeight(
 	)
 }
 
-// Relays a message to the destination chain. Does not checkpoint the height.
+// Records the message [messageID] sent at [height] as processed. Failures are logged rather than returned,
+// since the message is at worst processed again if the height is reprocessed before it is checkpointed.
+func (r *ApplicationRelayer) markProcessed(height uint64, messageID ids.ID) {
+	if err := database.PutProcessedMessage(r.db, r.relayerID.ID, height, messageID); err != nil {
+		r.logger.Warn(
+			"Failed to record processed message",
+			zap.Uint64("height", height),
+			zap.String("warpMessageID", messageID.String()),
+			zap.String("relayerID", r.relayerID.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// Queues the message [messageID] sent at [height], which failed to be relayed with [relayErr], to be retried.
+// Returns an error if the message could not be queued, in which case the height must not be checkpointed.
+func (r *ApplicationRelayer) queueRetry(height uint64, messageID ids.ID, relayErr error) error {
+	now := time.Now().UTC()
+	entry := &database.RetryEntry{
+		WarpMessageID:     messageID,
+		SourceBlockNumber: height,
+		Attempts:          1,
+		LastError:         relayErr.Error(),
+		QueuedAt:          now,
+		NextAttemptAt:     now.Add(r.retryBackoff(1)),
+	}
+	if err := database.QueueRetryEntry(r.db, r.relayerID.ID, entry); err != nil {
+		r.logger.Error(
+			"Failed to queue message for retry",
+			zap.Uint64("height", height),
+			zap.String("warpMessageID", messageID.String()),
+			zap.String("relayerID", r.relayerID.ID.String()),
+			zap.Error(err),
+		)
+		return fmt.Errorf("failed to relay message %s: %w", messageID, relayErr)
+	}
+	r.logger.Warn(
+		"Failed to relay message. Queued for retry.",
+		zap.Uint64("height", height),
+		zap.String("warpMessageID", messageID.String()),
+		zap.String("relayerID", r.relayerID.ID.String()),
+		zap.Error(relayErr),
+	)
+	return nil
+}
+
+// Returns the delay before retrying a message after [attempts] failed attempts to relay it. The delay starts at
+// the message retry interval, and doubles after each failed attempt up to the maximum interval.
+func (r *ApplicationRelayer) retryBackoff(attempts uint64) time.Duration {
+	backoff := r.messageRetryInterval
+	for i := uint64(1); i < attempts && backoff < r.messageRetryMaxInterval; i++ {
+		backoff *= 2
+	}
+	return min(backoff, r.messageRetryMaxInterval)
+}
+
+// Updates the retry queue after retrying the message of [entry], which failed with [relayErr] if it is
+// non-nil. The entry is removed once the message is relayed, and moved to the dead letters once the message
+// has failed to be relayed the maximum number of times. Otherwise, its next attempt is backed off.
+func (r *ApplicationRelayer) completeRetry(entry *database.RetryEntry, relayErr error) {
+	if relayErr == nil {
+		r.logger.Info(
+			"Relayed queued message",
+			zap.String("warpMessageID", entry.WarpMessageID.String()),
+			zap.Uint64("attempts", entry.Attempts+1),
+			zap.String("relayerID", r.relayerID.ID.String()),
+		)
+		if err := database.DeleteRetryEntry(r.db, r.relayerID.ID, entry); err != nil {
+			r.logger.Warn(
+				"Failed to remove message from retry queue",
+				zap.String("warpMessageID", entry.WarpMessageID.String()),
+				zap.String("relayerID", r.relayerID.ID.String()),
+				zap.Error(err),
+			)
+		}
+		return
+	}
+	entry.Attempts++
+	entry.LastError = relayErr.Error()
+	if entry.Attempts >= r.messageRetryMaxAttempts {
+		r.logger.Error(
+			"Failed to relay queued message. Giving up and moving it to the dead letters.",
+			zap.String("warpMessageID", entry.WarpMessageID.String()),
+			zap.Uint64("attempts", entry.Attempts),
+			zap.String("relayerID", r.relayerID.ID.String()),
+			zap.Error(relayErr),
+		)
+		if err := database.MoveToDeadLetters(r.db, r.relayerID.ID, entry); err != nil {
+			r.logger.Warn(
+				"Failed to move message to the dead letters",
+				zap.String("warpMessageID", entry.WarpMessageID.String()),
+				zap.String("relayerID", r.relayerID.ID.String()),
+				zap.Error(err),
+			)
+			return
+		}
+		r.incDeadLetterMessageCount()
+		return
+	}
+	entry.NextAttemptAt = time.Now().UTC().Add(r.retryBackoff(entry.Attempts))
+	r.logger.Warn(
+		"Failed to relay queued message",
+		zap.String("warpMessageID", entry.WarpMessageID.String()),
+		zap.Uint64("attempts", entry.Attempts),
+		zap.Time("nextAttemptAt", entry.NextAttemptAt),
+		zap.String("relayerID", r.relayerID.ID.String()),
+		zap.Error(relayErr),
+	)
+	if err := database.PutRetryEntry(r.db, r.relayerID.ID, entry); err != nil {
+		r.logger.Warn(
+			"Failed to update retry queue",
+			zap.String("warpMessageID", entry.WarpMessageID.String()),
+			zap.String("relayerID", r.relayerID.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// Relays a message sent in block [blockNumber] of the source chain to the destination chain, and records the
+// delivery in the database. Does not checkpoint the height. [blockNumber] is zero if it is not known.
 // returns the transaction hash if the message is successfully relayed.
-func (r *ApplicationRelayer) ProcessMessage(handler messages.MessageHandler) (common.Hash, error) {
+// Signature collection is abandoned if [ctx] is cancelled.
+func (r *ApplicationRelayer) ProcessMessage(
+	ctx context.Context,
+	handler messages.MessageHandler,
+	blockNumber uint64,
+) (common.Hash, error) {
+	startProcessMessageTime := time.Now()
 	r.logger.Debug(
 		"Relaying message",
 		zap.String("sourceBlockchainID", r.sourceBlockchain.BlockchainID),
@@ -202,12 +399,7 @@ func (r *ApplicationRelayer) ProcessMessage(handler messages.MessageHandler) (co
 
 	// sourceWarpSignatureClient is nil iff the source blockchain is configured to fetch signatures via AppRequest
 	if r.sourceWarpSignatureClient == nil {
-		signedMessage, err = r.signatureAggregator.CreateSignedMessage(
-			unsignedMessage,
-			nil,
-			r.signingSubnetID,
-			r.warpQuorum.QuorumNumerator,
-		)
+		signedMessage, err = r.aggregateSignedMessage(ctx, unsignedMessage)
 		r.incFetchSignatureAppRequestCount()
 		if err != nil {
 			r.logger.Error(
@@ -219,7 +411,7 @@ func (r *ApplicationRelayer) ProcessMessage(handler messages.MessageHandler) (co
 		}
 	} else {
 		r.incFetchSignatureRPCCount()
-		signedMessage, err = r.createSignedMessage(unsignedMessage)
+		signedMessage, err = r.createSignedMessage(ctx, unsignedMessage)
 		if err != nil {
 			r.logger.Error(
 				"Failed to create signed warp message via RPC",
@@ -233,7 +425,7 @@ func (r *ApplicationRelayer) ProcessMessage(handler messages.MessageHandler) (co
 	// create signed message latency (ms)
 	r.setCreateSignedMessageLatencyMS(float64(time.Since(startCreateSignedMessageTime).Milliseconds()))
 
-	txHash, err := handler.SendMessage(signedMessage, r.destinationClient)
+	delivery, err := handler.SendMessage(signedMessage, r.destinationClient)
 	if err != nil {
 		r.logger.Error(
 			"Failed to send warp message",
@@ -245,21 +437,115 @@ func (r *ApplicationRelayer) ProcessMessage(handler messages.MessageHandler) (co
 	r.logger.Info(
 		"Finished relaying message to destination chain",
 		zap.String("destinationBlockchainID", r.relayerID.DestinationBlockchainID.String()),
-		zap.String("txHash", txHash.Hex()),
+		zap.String("txHash", delivery.TxHash.Hex()),
 	)
 	r.incSuccessfulRelayMessageCount()
 
-	return txHash, nil
+	r.recordDelivery(unsignedMessage, delivery, blockNumber, startProcessMessageTime)
+	return delivery.TxHash, nil
+}
+
+// Records the delivery of [unsignedMessage] in the database. The message has already been delivered,
+// so failures are logged rather than returned.
+func (r *ApplicationRelayer) recordDelivery(
+	unsignedMessage *avalancheWarp.UnsignedMessage,
+	delivery *messages.Delivery,
+	blockNumber uint64,
+	startTime time.Time,
+) {
+	deliveredAt := time.Now()
+	record := &database.DeliveryRecord{
+		WarpMessageID:           unsignedMessage.ID(),
+		ProtocolMessageID:       delivery.ProtocolMessageID,
+		RelayerID:               r.relayerID.ID,
+		SourceBlockchainID:      r.relayerID.SourceBlockchainID,
+		DestinationBlockchainID: r.relayerID.DestinationBlockchainID,
+		SourceBlockNumber:       blockNumber,
+		DestinationTxHash:       delivery.TxHash,
+		GasUsed:                 delivery.GasUsed,
+		LatencyMS:               deliveredAt.Sub(startTime).Milliseconds(),
+		DeliveredAt:             deliveredAt.UTC(),
+	}
+	if err := database.PutDeliveryRecord(r.db, record, r.deliveryHistoryRetention); err != nil {
+		r.logger.Warn(
+			"Failed to record message delivery",
+			zap.String("warpMessageID", record.WarpMessageID.String()),
+			zap.String("relayerID", r.relayerID.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// Returns whether this instance relays for the relayer ID. Always true if leader election is disabled.
+func (r *ApplicationRelayer) isLeader() bool {
+	return r.leaderElector == nil || r.leaderElector.IsLeader(r.relayerID.ID)
+}
+
+// Updates whether this instance is the leader of the relayer ID as of the block being processed.
+// Returns true if this instance has just become the leader, in which case it must take over from the
+// checkpoint of the previous leader.
+func (r *ApplicationRelayer) updateLeadership() (leader bool, tookOver bool) {
+	if !r.isLeader() {
+		r.leading.Store(false)
+		return false, false
+	}
+	return true, r.leading.CompareAndSwap(false, true)
+}
+
+// Fast forwards the checkpoint to the height stored by the previous leader of the relayer ID, if it is
+// greater, and returns the next height to process.
+func (r *ApplicationRelayer) resumeFromCheckpoint() (uint64, error) {
+	storedHeight, err := database.GetLatestProcessedBlockHeight(r.db, r.relayerID)
+	if err != nil && !database.IsKeyNotFoundError(err) {
+		r.logger.Error(
+			"Failed to get latest processed block height",
+			zap.String("relayerID", r.relayerID.ID.String()),
+			zap.Error(err),
+		)
+		return 0, err
+	}
+	return r.checkpointManager.FastForward(storedHeight) + 1, nil
 }
 
 func (r *ApplicationRelayer) RelayerID() database.RelayerID {
 	return r.relayerID
 }
 
+// aggregateSignedMessage collects signatures for the message from the signing subnet's validators via
+// AppRequest. The validator set is taken at the P-Chain height against which the destination blockchain
+// currently verifies Warp messages, rather than the current P-Chain height, so that the message is signed by
+// the validator set the destination expects while the validator set is changing. If the proposed height
+// is unavailable, such as when the P-Chain API node does not track the destination blockchain, the current
+// validator set is used instead.
+func (r *ApplicationRelayer) aggregateSignedMessage(
+	ctx context.Context,
+	unsignedMessage *avalancheWarp.UnsignedMessage,
+) (*avalancheWarp.Message, error) {
+	pChainHeight, err := r.signatureAggregator.GetProposedHeight(ctx, r.relayerID.DestinationBlockchainID)
+	if err != nil {
+		r.logger.Warn(
+			"Failed to get proposed P-Chain height of destination blockchain. Using the current validator set",
+			zap.String("destinationBlockchainID", r.relayerID.DestinationBlockchainID.String()),
+			zap.Error(err),
+		)
+		pChainHeight = 0
+	}
+	return r.signatureAggregator.CreateSignedMessage(
+		ctx,
+		unsignedMessage,
+		nil,
+		r.signingSubnetID,
+		pChainHeight,
+		r.warpQuorum.QuorumNumerator,
+		&r.retryPolicy,
+	)
+}
+
 // createSignedMessage fetches the signed Warp message from the source chain via RPC.
 // Each VM may implement their own RPC method to construct the aggregate signature, which
 // will need to be accounted for here.
 func (r *ApplicationRelayer) createSignedMessage(
+	ctx context.Context,
 	unsignedMessage *avalancheWarp.UnsignedMessage,
 ) (*avalancheWarp.Message, error) {
 	r.logger.Info("Fetching aggregate signature from the source chain validators via API")
@@ -268,17 +554,19 @@ func (r *ApplicationRelayer) createSignedMessage(
 		signedWarpMessageBytes hexutil.Bytes
 		err                    error
 	)
-	for attempt := 1; attempt <= maxRelayerQueryAttempts; attempt++ {
+	startTime := time.Now()
+	attempt := uint64(1)
+	for ; ; attempt++ {
 		r.logger.Debug(
 			"Relayer collecting signatures from peers.",
-			zap.Int("attempt", attempt),
+			zap.Uint64("attempt", attempt),
 			zap.String("sourceBlockchainID", r.sourceBlockchain.GetBlockchainID().String()),
 			zap.String("destinationBlockchainID", r.relayerID.DestinationBlockchainID.String()),
 			zap.String("signingSubnetID", r.signingSubnetID.String()),
 		)
 
 		err = r.sourceWarpSignatureClient.CallContext(
-			context.Background(),
+			ctx,
 			&signedWarpMessageBytes,
 			"warp_getMessageAggregateSignature",
 			unsignedMessage.ID(),
@@ -298,18 +586,22 @@ func (r *ApplicationRelayer) createSignedMessage(
 		}
 		r.logger.Info(
 			"Failed to get aggregate signature from node endpoint. Retrying.",
-			zap.Int("attempt", attempt),
+			zap.Uint64("attempt", attempt),
 			zap.Error(err),
 		)
-		if attempt != maxRelayerQueryAttempts {
-			// Sleep such that all retries are uniformly spread across totalRelayerQueryPeriodMs
-			// TODO: We may want to consider an exponential back off rather than a uniform sleep period.
-			time.Sleep(time.Duration(signatureRequestRetryWaitPeriodMs/maxRelayerQueryAttempts) * time.Millisecond)
+		delay, retry := r.retryPolicy.NextDelay(attempt, startTime)
+		if !retry {
+			break
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 	}
 	r.logger.Warn(
 		"Failed to get aggregate signature from node endpoint",
-		zap.Int("attempts", maxRelayerQueryAttempts),
+		zap.Uint64("attempts", attempt),
 		zap.String("sourceBlockchainID", r.sourceBlockchain.GetBlockchainID().String()),
 		zap.String("destinationBlockchainID", r.relayerID.DestinationBlockchainID.String()),
 		zap.String("signingSubnetID", r.signingSubnetID.String()),
@@ -361,3 +653,11 @@ func (r *ApplicationRelayer) incFetchSignatureAppRequestCount() {
 			r.sourceBlockchain.GetBlockchainID().String(),
 			r.sourceBlockchain.GetSubnetID().String()).Inc()
 }
+
+func (r *ApplicationRelayer) incDeadLetterMessageCount() {
+	r.metrics.deadLetterMessageCount.
+		WithLabelValues(
+			r.relayerID.DestinationBlockchainID.String(),
+			r.sourceBlockchain.GetBlockchainID().String(),
+			r.sourceBlockchain.GetSubnetID().String()).Inc()
+}
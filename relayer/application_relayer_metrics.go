@@ -19,6 +19,7 @@ type ApplicationRelayerMetrics struct {
 	failedRelayMessageCount       *prometheus.CounterVec
 	fetchSignatureAppRequestCount *prometheus.CounterVec
 	fetchSignatureRPCCount        *prometheus.CounterVec
+	deadLetterMessageCount        *prometheus.CounterVec
 }
 
 func NewApplicationRelayerMetrics(registerer prometheus.Registerer) (*ApplicationRelayerMetrics, error) {
@@ -79,11 +80,24 @@ func NewApplicationRelayerMetrics(registerer prometheus.Registerer) (*Applicatio
 	}
 	registerer.MustRegister(fetchSignatureRPCCount)
 
+	deadLetterMessageCount := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dead_letter_message_count",
+			Help: "Number of messages that are no longer retried after failing to relay too many times",
+		},
+		[]string{"destination_chain_id", "source_chain_id", "source_subnet_id"},
+	)
+	if deadLetterMessageCount == nil {
+		return nil, ErrFailedToCreateApplicationRelayerMetrics
+	}
+	registerer.MustRegister(deadLetterMessageCount)
+
 	return &ApplicationRelayerMetrics{
 		successfulRelayMessageCount:   successfulRelayMessageCount,
 		createSignedMessageLatencyMS:  createSignedMessageLatencyMS,
 		failedRelayMessageCount:       failedRelayMessageCount,
 		fetchSignatureAppRequestCount: fetchSignatureAppRequestCount,
 		fetchSignatureRPCCount:        fetchSignatureRPCCount,
+		deadLetterMessageCount:        deadLetterMessageCount,
 	}, nil
 }
@@ -0,0 +1,405 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/proto/pb/sdk"
+	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	basecfg "github.com/ava-labs/awm-relayer/config"
+	"github.com/ava-labs/awm-relayer/database"
+	mock_database "github.com/ava-labs/awm-relayer/database/mocks"
+	"github.com/ava-labs/awm-relayer/messages"
+	messageMocks "github.com/ava-labs/awm-relayer/messages/mocks"
+	"github.com/ava-labs/awm-relayer/peers"
+	"github.com/ava-labs/awm-relayer/peers/mocks"
+	"github.com/ava-labs/awm-relayer/relayer/config"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/proto"
+)
+
+// Returns a validator set of [count] validators of equal weight, along with their secret keys
+func makeValidatorSet(t *testing.T, count int) (*peers.ConnectedCanonicalValidators, map[ids.NodeID]*bls.SecretKey) {
+	validatorSet := make([]*avalancheWarp.Validator, count)
+	secretKeys := make(map[ids.NodeID]*bls.SecretKey, count)
+	for i := range validatorSet {
+		secretKey, err := bls.NewSecretKey()
+		require.NoError(t, err)
+		publicKey := bls.PublicFromSecretKey(secretKey)
+		nodeID := ids.GenerateTestNodeID()
+		validatorSet[i] = &avalancheWarp.Validator{
+			PublicKey:      publicKey,
+			PublicKeyBytes: bls.PublicKeyToUncompressedBytes(publicKey),
+			Weight:         1,
+			NodeIDs:        []ids.NodeID{nodeID},
+		}
+		secretKeys[nodeID] = secretKey
+	}
+	utils.Sort(validatorSet)
+	nodeValidatorIndexMap := make(map[ids.NodeID]int, count)
+	for i, validator := range validatorSet {
+		nodeValidatorIndexMap[validator.NodeIDs[0]] = i
+	}
+	return &peers.ConnectedCanonicalValidators{
+		ConnectedWeight:       uint64(count),
+		TotalValidatorWeight:  uint64(count),
+		ValidatorSet:          validatorSet,
+		NodeValidatorIndexMap: nodeValidatorIndexMap,
+	}, secretKeys
+}
+
+// Returns whether [signedMessage] is signed by validators of [validatorSet]
+func signedBy(
+	t *testing.T,
+	signedMessage *avalancheWarp.Message,
+	validatorSet *peers.ConnectedCanonicalValidators,
+) bool {
+	signature, ok := signedMessage.Signature.(*avalancheWarp.BitSetSignature)
+	require.True(t, ok)
+	signers, err := avalancheWarp.FilterValidators(set.BitsFromBytes(signature.Signers), validatorSet.ValidatorSet)
+	if err != nil {
+		return false
+	}
+	aggregatePublicKey, err := avalancheWarp.AggregatePublicKeys(signers)
+	require.NoError(t, err)
+	aggregateSignature, err := bls.SignatureFromBytes(signature.Signature[:])
+	require.NoError(t, err)
+	return bls.Verify(aggregatePublicKey, aggregateSignature, signedMessage.UnsignedMessage.Bytes())
+}
+
+func TestAggregateSignedMessageUsesProposedValidatorSet(t *testing.T) {
+	testCases := []struct {
+		name              string
+		proposedHeightErr error
+	}{
+		{
+			name: "proposed height",
+		},
+		{
+			name:              "proposed height unavailable",
+			proposedHeightErr: errors.New("proposervm API not available"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testAggregateSignedMessage(t, testCase.proposedHeightErr)
+		})
+	}
+}
+
+func testAggregateSignedMessage(t *testing.T, proposedHeightErr error) {
+	var (
+		sourceBlockchainID      = ids.GenerateTestID()
+		destinationBlockchainID = ids.GenerateTestID()
+		subnetID                = ids.GenerateTestID()
+		proposedHeight          = uint64(100)
+	)
+	unsignedMessage, err := avalancheWarp.NewUnsignedMessage(
+		constants.UnitTestID,
+		sourceBlockchainID,
+		utils.RandomBytes(32),
+	)
+	require.NoError(t, err)
+
+	// The validator set has changed since the P-Chain height the destination verifies messages against
+	currentValidators, currentSecretKeys := makeValidatorSet(t, 4)
+	proposedValidators, proposedSecretKeys := makeValidatorSet(t, 4)
+
+	mockNetwork := mocks.NewMockAppRequestNetwork(gomock.NewController(t))
+	mockNetwork.EXPECT().GetProposedHeight(gomock.Any(), destinationBlockchainID).Return(
+		proposedHeight,
+		proposedHeightErr,
+	)
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), sourceBlockchainID).Return(subnetID, nil)
+	var secretKeys map[ids.NodeID]*bls.SecretKey
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ ids.ID, pChainHeight uint64) (*peers.ConnectedCanonicalValidators, error) {
+			if pChainHeight == proposedHeight {
+				secretKeys = proposedSecretKeys
+				return proposedValidators, nil
+			}
+			secretKeys = currentSecretKeys
+			return currentValidators, nil
+		},
+	)
+	mockNetwork.EXPECT().RegisterAppRequest(gomock.Any()).AnyTimes()
+
+	// Every validator of the validator set that is connected to signs the message
+	mockNetwork.EXPECT().RegisterRequestID(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(requestID uint32, _ int) chan message.InboundMessage {
+			responses := make(chan message.InboundMessage, len(secretKeys))
+			for nodeID, secretKey := range secretKeys {
+				responseBytes, err := proto.Marshal(&sdk.SignatureResponse{
+					Signature: bls.SignatureToBytes(bls.Sign(secretKey, unsignedMessage.Bytes())),
+				})
+				require.NoError(t, err)
+				responses <- message.InboundAppResponse(sourceBlockchainID, requestID, responseBytes, nodeID)
+			}
+			return responses
+		},
+	)
+	mockNetwork.EXPECT().Send(gomock.Any(), gomock.Any(), subnetID, subnets.NoOpAllower).DoAndReturn(
+		func(_ message.OutboundMessage, nodeIDs set.Set[ids.NodeID], _ ids.ID, _ subnets.Allower) set.Set[ids.NodeID] {
+			return nodeIDs
+		},
+	)
+
+	messageCreator, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		constants.DefaultNetworkCompressionType,
+		constants.DefaultNetworkMaximumInboundTimeout,
+	)
+	require.NoError(t, err)
+	signatureAggregator, err := aggregator.NewSignatureAggregator(
+		mockNetwork,
+		logging.NoLog{},
+		1024,
+		metrics.NewSignatureAggregatorMetrics(prometheus.NewRegistry()),
+		messageCreator,
+		time.Now().Add(-time.Minute),
+		aggregator.QueryAll,
+		nil,
+	)
+	require.NoError(t, err)
+
+	r := &ApplicationRelayer{
+		logger:          logging.NoLog{},
+		signingSubnetID: subnetID,
+		relayerID: database.NewRelayerID(
+			sourceBlockchainID,
+			destinationBlockchainID,
+			common.Address{},
+			common.Address{},
+		),
+		warpQuorum:          config.WarpQuorum{QuorumNumerator: 67, QuorumDenominator: 100},
+		signatureAggregator: signatureAggregator,
+		retryPolicy:         basecfg.RetryPolicy{MaxAttempts: 1},
+	}
+	signedMessage, err := r.aggregateSignedMessage(context.Background(), unsignedMessage)
+	require.NoError(t, err)
+	if proposedHeightErr != nil {
+		// Falls back to the current validator set if the proposed height is unavailable
+		require.True(t, signedBy(t, signedMessage, currentValidators))
+		require.False(t, signedBy(t, signedMessage, proposedValidators))
+	} else {
+		require.True(t, signedBy(t, signedMessage, proposedValidators))
+		require.False(t, signedBy(t, signedMessage, currentValidators))
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	r := &ApplicationRelayer{
+		messageRetryInterval:    time.Minute,
+		messageRetryMaxInterval: 10 * time.Minute,
+	}
+	require.Equal(t, time.Minute, r.retryBackoff(1))
+	require.Equal(t, 2*time.Minute, r.retryBackoff(2))
+	require.Equal(t, 8*time.Minute, r.retryBackoff(4))
+	require.Equal(t, 10*time.Minute, r.retryBackoff(5))
+	require.Equal(t, 10*time.Minute, r.retryBackoff(1000))
+}
+
+func TestCompleteRetryDeadLetters(t *testing.T) {
+	relayerID := database.NewRelayerID(ids.GenerateTestID(), ids.GenerateTestID(), common.Address{}, common.Address{})
+	db, err := database.NewJSONFileStorage(logging.NoLog{}, t.TempDir(), []database.RelayerID{relayerID})
+	require.NoError(t, err)
+	relayerMetrics, err := NewApplicationRelayerMetrics(prometheus.NewRegistry())
+	require.NoError(t, err)
+	r := &ApplicationRelayer{
+		logger:                  logging.NoLog{},
+		metrics:                 relayerMetrics,
+		relayerID:               relayerID,
+		db:                      db,
+		messageRetryInterval:    time.Minute,
+		messageRetryMaxInterval: 10 * time.Minute,
+		messageRetryMaxAttempts: 3,
+	}
+	entry := &database.RetryEntry{
+		WarpMessageID: ids.GenerateTestID(),
+		Attempts:      1,
+		QueuedAt:      time.Now().UTC(),
+	}
+	require.NoError(t, database.PutRetryEntry(db, relayerID.ID, entry))
+
+	// A failed attempt backs off the next one
+	before := time.Now()
+	r.completeRetry(entry, errors.New("failed"))
+	entries, err := database.ListRetryEntries(db, relayerID.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, uint64(2), entries[0].Attempts)
+	require.Equal(t, "failed", entries[0].LastError)
+	require.False(t, entries[0].NextAttemptAt.Before(before.Add(2*time.Minute)))
+
+	// Once the maximum number of attempts is reached, the message is moved to the dead letters
+	r.completeRetry(entries[0], errors.New("failed again"))
+	entries, err = database.ListRetryEntries(db, relayerID.ID)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	deadLetters, err := database.ListDeadLetters(db, relayerID.ID)
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+	require.Equal(t, entry.WarpMessageID, deadLetters[0].WarpMessageID)
+	require.Equal(t, uint64(3), deadLetters[0].Attempts)
+	require.Equal(t, "failed again", deadLetters[0].LastError)
+}
+
+// Returns a handler of a new message sent by [sourceBlockchainID]
+func newTestMessageHandler(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	sourceBlockchainID ids.ID,
+) *messageMocks.MockMessageHandler {
+	unsignedMessage, err := avalancheWarp.NewUnsignedMessage(
+		constants.UnitTestID,
+		sourceBlockchainID,
+		utils.RandomBytes(32),
+	)
+	require.NoError(t, err)
+	handler := messageMocks.NewMockMessageHandler(ctrl)
+	handler.EXPECT().GetUnsignedMessage().Return(unsignedMessage).AnyTimes()
+	return handler
+}
+
+func TestProcessHeight(t *testing.T) {
+	const height = uint64(10)
+	relayErr := errors.New("destination unavailable")
+	testCases := []struct {
+		name string
+		// Whether each message was processed before the height is processed
+		alreadyProcessed []bool
+		// Whether each message that was not already processed fails to be relayed
+		failing []bool
+	}{
+		{
+			name:             "relayed",
+			alreadyProcessed: []bool{false, false},
+			failing:          []bool{false, false},
+		},
+		{
+			name:             "failed",
+			alreadyProcessed: []bool{false, false},
+			failing:          []bool{true, false},
+		},
+		{
+			name:             "already processed",
+			alreadyProcessed: []bool{true, false},
+			failing:          []bool{false, false},
+		},
+		{
+			name:             "already processed and failed",
+			alreadyProcessed: []bool{true, false},
+			failing:          []bool{false, true},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			relayerID := newTestRelayerID()
+			appRelayer, checkpointManager, _ := newTestApplicationRelayer(t, relayerID)
+			ctrl := gomock.NewController(t)
+
+			var (
+				handlers        []messages.MessageHandler
+				expectedQueued  []ids.ID
+				expectedMarkers = set.Set[ids.ID]{}
+			)
+			for i := range testCase.alreadyProcessed {
+				handler := newTestMessageHandler(t, ctrl, relayerID.SourceBlockchainID)
+				messageID := handler.GetUnsignedMessage().ID()
+				handlers = append(handlers, handler)
+				expectedMarkers.Add(messageID)
+				if testCase.alreadyProcessed[i] {
+					// Messages processed before a restart are skipped without being relayed again
+					require.NoError(t, database.PutProcessedMessage(appRelayer.db, relayerID.ID, height, messageID))
+					continue
+				}
+				if testCase.failing[i] {
+					handler.EXPECT().ShouldSendMessage(gomock.Any()).Return(false, relayErr)
+					expectedQueued = append(expectedQueued, messageID)
+				} else {
+					handler.EXPECT().ShouldSendMessage(gomock.Any()).Return(false, nil)
+				}
+			}
+
+			errChan := make(chan error, 1)
+			appRelayer.ProcessHeight(context.Background(), height, handlers, errChan)
+			require.Empty(t, errChan)
+
+			// The height is checkpointed even if messages failed, since they are queued to be retried
+			require.Equal(t, []uint64{height}, checkpointManager.getStagedHeights())
+			processed, err := database.GetProcessedMessages(appRelayer.db, relayerID.ID, height)
+			require.NoError(t, err)
+			require.Equal(t, expectedMarkers, processed)
+			queued, err := database.ListRetryEntries(appRelayer.db, relayerID.ID)
+			require.NoError(t, err)
+			require.Len(t, queued, len(expectedQueued))
+			for i, entry := range queued {
+				require.Equal(t, expectedQueued[i], entry.WarpMessageID)
+				require.Equal(t, height, entry.SourceBlockNumber)
+				require.Equal(t, uint64(1), entry.Attempts)
+				require.Equal(t, relayErr.Error(), entry.LastError)
+				require.True(t, entry.NextAttemptAt.After(entry.QueuedAt))
+			}
+		})
+	}
+}
+
+func TestProcessHeightDatabaseErrors(t *testing.T) {
+	const height = uint64(10)
+	dbErr := errors.New("database unavailable")
+	testCases := []struct {
+		name      string
+		setupMock func(db *mock_database.MockRelayerDatabase, handler *messageMocks.MockMessageHandler)
+	}{
+		{
+			name: "getting processed messages",
+			setupMock: func(db *mock_database.MockRelayerDatabase, _ *messageMocks.MockMessageHandler) {
+				db.EXPECT().GetRange(gomock.Any(), database.ProcessedMessagesKey, gomock.Any()).Return(nil, dbErr)
+			},
+		},
+		{
+			name: "queueing a failed message",
+			setupMock: func(db *mock_database.MockRelayerDatabase, handler *messageMocks.MockMessageHandler) {
+				db.EXPECT().GetRange(gomock.Any(), database.ProcessedMessagesKey, gomock.Any()).Return(nil, nil)
+				handler.EXPECT().ShouldSendMessage(gomock.Any()).Return(false, errors.New("failed"))
+				db.EXPECT().WriteBatch(gomock.Any(), gomock.Any()).Return(dbErr)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			relayerID := newTestRelayerID()
+			appRelayer, checkpointManager, _ := newTestApplicationRelayer(t, relayerID)
+			ctrl := gomock.NewController(t)
+			db := mock_database.NewMockRelayerDatabase(ctrl)
+			appRelayer.db = db
+			handler := newTestMessageHandler(t, ctrl, relayerID.SourceBlockchainID)
+			testCase.setupMock(db, handler)
+
+			// The height is not checkpointed, so that it is processed again
+			errChan := make(chan error, 1)
+			appRelayer.ProcessHeight(context.Background(), height, []messages.MessageHandler{handler}, errChan)
+			require.Len(t, errChan, 1)
+			require.Empty(t, checkpointManager.getStagedHeights())
+		})
+	}
+}
@@ -5,7 +5,6 @@ package checkpoint
 
 import (
 	"container/heap"
-	"strconv"
 	"sync"
 
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -64,28 +63,14 @@ func (cm *CheckpointManager) writeToDatabase() {
 	if cm.committedHeight == 0 {
 		return
 	}
-	storedHeight, err := database.GetLatestProcessedBlockHeight(cm.database, cm.relayerID)
-	if err != nil && !database.IsKeyNotFoundError(err) {
-		cm.logger.Error(
-			"Failed to get latest processed block height",
-			zap.Error(err),
-			zap.String("relayerID", cm.relayerID.ID.String()),
-		)
-		return
-	}
-	if storedHeight >= cm.committedHeight {
-		return
-	}
 	cm.logger.Debug(
 		"Writing height",
 		zap.Uint64("height", cm.committedHeight),
 		zap.String("relayerID", cm.relayerID.ID.String()),
 	)
-	err = cm.database.Put(
-		cm.relayerID.ID,
-		database.LatestProcessedBlockKey,
-		[]byte(strconv.FormatUint(cm.committedHeight, 10)),
-	)
+	// The stored height may have been advanced past the committed height by another instance
+	// sharing the database, in which case it is left unchanged.
+	storedHeight, err := database.AdvanceLatestProcessedBlockHeight(cm.database, cm.relayerID, cm.committedHeight)
 	if err != nil {
 		cm.logger.Error(
 			"Failed to write latest processed block height",
@@ -94,6 +79,23 @@ func (cm *CheckpointManager) writeToDatabase() {
 		)
 		return
 	}
+	if storedHeight > cm.committedHeight {
+		cm.logger.Debug(
+			"Stored height is greater than the committed height. Skipping.",
+			zap.Uint64("storedHeight", storedHeight),
+			zap.Uint64("committedHeight", cm.committedHeight),
+			zap.String("relayerID", cm.relayerID.ID.String()),
+		)
+	}
+	// The messages processed at checkpointed heights are no longer needed to resume processing
+	if err := database.DeleteProcessedMessages(cm.database, cm.relayerID.ID, storedHeight); err != nil {
+		cm.logger.Warn(
+			"Failed to delete processed messages",
+			zap.Uint64("height", storedHeight),
+			zap.String("relayerID", cm.relayerID.ID.String()),
+			zap.Error(err),
+		)
+	}
 }
 
 func (cm *CheckpointManager) listenForWriteSignal() {
@@ -142,3 +144,27 @@ func (cm *CheckpointManager) StageCommittedHeight(height uint64) {
 		}
 	}
 }
+
+// FastForward advances the committed height to [height] if it is greater, as when the heights up to [height]
+// have been committed by another instance. Pending heights at or below the new committed height are discarded.
+// Returns the committed height.
+func (cm *CheckpointManager) FastForward(height uint64) uint64 {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	if height > cm.committedHeight {
+		cm.logger.Info(
+			"Fast forwarding committed height",
+			zap.Uint64("height", height),
+			zap.Uint64("committedHeight", cm.committedHeight),
+			zap.String("relayerID", cm.relayerID.ID.String()),
+		)
+		cm.committedHeight = height
+	}
+	for cm.pendingCommits.Len() > 0 && cm.pendingCommits.Peek() <= cm.committedHeight+1 {
+		h := heap.Pop(cm.pendingCommits).(uint64)
+		if h == cm.committedHeight+1 {
+			cm.committedHeight = h
+		}
+	}
+	return cm.committedHeight
+}
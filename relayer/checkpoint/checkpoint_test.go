@@ -4,6 +4,7 @@ import (
 	"container/heap"
 	"testing"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/awm-relayer/database"
 	mock_database "github.com/ava-labs/awm-relayer/database/mocks"
@@ -71,3 +72,92 @@ func TestCommitHeight(t *testing.T) {
 		require.Equal(t, test.expectedMaxHeight, cm.committedHeight, test.name)
 	}
 }
+
+func TestFastForward(t *testing.T) {
+	testCases := []struct {
+		name              string
+		currentMaxHeight  uint64
+		fastForwardHeight uint64
+		pendingHeights    *utils.UInt64Heap
+		expectedMaxHeight uint64
+		expectedPending   int
+	}{
+		{
+			name:              "fast forward height is greater",
+			currentMaxHeight:  10,
+			fastForwardHeight: 20,
+			pendingHeights:    &utils.UInt64Heap{},
+			expectedMaxHeight: 20,
+		},
+		{
+			name:              "fast forward height is lower",
+			currentMaxHeight:  10,
+			fastForwardHeight: 5,
+			pendingHeights:    &utils.UInt64Heap{},
+			expectedMaxHeight: 10,
+		},
+		{
+			name:              "pending heights at or below the fast forward height are discarded",
+			currentMaxHeight:  10,
+			fastForwardHeight: 20,
+			pendingHeights:    &utils.UInt64Heap{12, 20, 25},
+			expectedMaxHeight: 20,
+			expectedPending:   1,
+		},
+		{
+			name:              "pending heights following the fast forward height are committed",
+			currentMaxHeight:  10,
+			fastForwardHeight: 20,
+			pendingHeights:    &utils.UInt64Heap{15, 21, 22, 24},
+			expectedMaxHeight: 22,
+			expectedPending:   1,
+		},
+	}
+	db := mock_database.NewMockRelayerDatabase(gomock.NewController(t))
+	for _, test := range testCases {
+		id := database.RelayerID{
+			ID: common.BytesToHash(crypto.Keccak256([]byte(test.name))),
+		}
+		cm := NewCheckpointManager(logging.NoLog{}, db, nil, id, test.currentMaxHeight)
+		heap.Init(test.pendingHeights)
+		cm.pendingCommits = test.pendingHeights
+		require.Equal(t, test.expectedMaxHeight, cm.FastForward(test.fastForwardHeight), test.name)
+		require.Equal(t, test.expectedMaxHeight, cm.committedHeight, test.name)
+		require.Equal(t, test.expectedPending, cm.pendingCommits.Len(), test.name)
+	}
+}
+
+func TestWriteToDatabase(t *testing.T) {
+	id := database.RelayerID{
+		ID: common.BytesToHash(crypto.Keccak256([]byte("write to database"))),
+	}
+	db := newTestToolDatabase(t, []database.RelayerID{id})
+
+	// Two instances sharing the database commit different heights
+	ahead := NewCheckpointManager(logging.NoLog{}, db, nil, id, 20)
+	behind := NewCheckpointManager(logging.NoLog{}, db, nil, id, 10)
+
+	behind.writeToDatabase()
+	requireHeight(t, db, id, 10)
+	ahead.writeToDatabase()
+	requireHeight(t, db, id, 20)
+
+	// The instance that is behind does not regress the stored height
+	behind.StageCommittedHeight(11)
+	behind.writeToDatabase()
+	requireHeight(t, db, id, 20)
+
+	// The processed messages of checkpointed heights are deleted
+	checkpointed, pending := ids.GenerateTestID(), ids.GenerateTestID()
+	require.NoError(t, database.PutProcessedMessage(db, id.ID, 21, checkpointed))
+	require.NoError(t, database.PutProcessedMessage(db, id.ID, 22, pending))
+	ahead.StageCommittedHeight(21)
+	ahead.writeToDatabase()
+	requireHeight(t, db, id, 21)
+	processed, err := database.GetProcessedMessages(db, id.ID, 21)
+	require.NoError(t, err)
+	require.Empty(t, processed)
+	processed, err = database.GetProcessedMessages(db, id.ID, 22)
+	require.NoError(t, err)
+	require.True(t, processed.Contains(pending))
+}
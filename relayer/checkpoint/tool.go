@@ -0,0 +1,203 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package checkpoint
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//
+// Tooling to inspect and move the checkpoints of relayer IDs, used by the checkpoint subcommand.
+// These should not be used while a relayer is running against the same database.
+//
+
+// Checkpoint is the latest processed block of a relayer ID, in the format that checkpoints are exported
+// and imported in.
+type Checkpoint struct {
+	RelayerID               common.Hash    `json:"relayer-id"`
+	SourceBlockchainID      ids.ID         `json:"source-blockchain-id"`
+	DestinationBlockchainID ids.ID         `json:"destination-blockchain-id"`
+	OriginSenderAddress     common.Address `json:"origin-sender-address"`
+	DestinationAddress      common.Address `json:"destination-address"`
+	// Nil if the relayer ID has no checkpoint
+	LatestProcessedBlock *uint64 `json:"latest-processed-block,omitempty"`
+}
+
+func (c *Checkpoint) relayerID() database.RelayerID {
+	return database.RelayerID{
+		SourceBlockchainID:      c.SourceBlockchainID,
+		DestinationBlockchainID: c.DestinationBlockchainID,
+		OriginSenderAddress:     c.OriginSenderAddress,
+		DestinationAddress:      c.DestinationAddress,
+		ID:                      c.RelayerID,
+	}
+}
+
+// RelayerIDs returns the relayer IDs of [checkpoints]
+func RelayerIDs(checkpoints []Checkpoint) []database.RelayerID {
+	relayerIDs := make([]database.RelayerID, len(checkpoints))
+	for i := range checkpoints {
+		relayerIDs[i] = checkpoints[i].relayerID()
+	}
+	return relayerIDs
+}
+
+// ListCheckpoints returns the checkpoint of each of [relayerIDs], in the same order
+func ListCheckpoints(db database.RelayerDatabase, relayerIDs []database.RelayerID) ([]Checkpoint, error) {
+	checkpoints := make([]Checkpoint, len(relayerIDs))
+	for i, relayerID := range relayerIDs {
+		checkpoints[i] = Checkpoint{
+			RelayerID:               relayerID.ID,
+			SourceBlockchainID:      relayerID.SourceBlockchainID,
+			DestinationBlockchainID: relayerID.DestinationBlockchainID,
+			OriginSenderAddress:     relayerID.OriginSenderAddress,
+			DestinationAddress:      relayerID.DestinationAddress,
+		}
+		height, err := database.GetLatestProcessedBlockHeight(db, relayerID)
+		if database.IsKeyNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get checkpoint of relayer ID %s: %w", relayerID.ID, err)
+		}
+		checkpoints[i].LatestProcessedBlock = &height
+	}
+	return checkpoints, nil
+}
+
+// ImportCheckpoints writes [checkpoints] to [db]. Checkpoints without a latest processed block are skipped,
+// as are relayer IDs that already have a checkpoint, unless [overwrite] is set.
+// Returns the number of checkpoints written.
+func ImportCheckpoints(db database.RelayerDatabase, checkpoints []Checkpoint, overwrite bool) (int, error) {
+	written := 0
+	for _, checkpoint := range checkpoints {
+		if checkpoint.LatestProcessedBlock == nil {
+			continue
+		}
+		ok, err := writeCheckpoint(db, checkpoint.relayerID(), *checkpoint.LatestProcessedBlock, overwrite)
+		if err != nil {
+			return written, err
+		}
+		if ok {
+			written++
+		}
+	}
+	return written, nil
+}
+
+// Migration copies the checkpoint of a relayer ID of a previous configuration to a relayer ID of the
+// current configuration
+type Migration struct {
+	From   common.Hash
+	To     database.RelayerID
+	Height uint64
+}
+
+// PlanMigrations maps each of [newRelayerIDs] that does not have a checkpoint to the checkpoints of
+// [oldRelayerIDs]. A new relayer ID is mapped to the old relayer ID with the lowest checkpoint among those with
+// the same source and destination blockchains, or if there are none, the same source blockchain. The lowest
+// checkpoint is used so that no blocks are skipped, since messages that were already delivered are not
+// delivered again.
+func PlanMigrations(
+	db database.RelayerDatabase,
+	oldRelayerIDs []database.RelayerID,
+	newRelayerIDs []database.RelayerID,
+) ([]Migration, error) {
+	oldCheckpoints, err := ListCheckpoints(db, oldRelayerIDs)
+	if err != nil {
+		return nil, err
+	}
+	newCheckpoints, err := ListCheckpoints(db, newRelayerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for i, newRelayerID := range newRelayerIDs {
+		if newCheckpoints[i].LatestProcessedBlock != nil {
+			continue
+		}
+		var sameRoute, sameSource *Checkpoint
+		for j := range oldCheckpoints {
+			old := &oldCheckpoints[j]
+			if old.LatestProcessedBlock == nil || old.SourceBlockchainID != newRelayerID.SourceBlockchainID {
+				continue
+			}
+			if sameSource == nil || *old.LatestProcessedBlock < *sameSource.LatestProcessedBlock {
+				sameSource = old
+			}
+			if old.DestinationBlockchainID != newRelayerID.DestinationBlockchainID {
+				continue
+			}
+			if sameRoute == nil || *old.LatestProcessedBlock < *sameRoute.LatestProcessedBlock {
+				sameRoute = old
+			}
+		}
+		from := sameRoute
+		if from == nil {
+			from = sameSource
+		}
+		if from == nil {
+			continue
+		}
+		migrations = append(migrations, Migration{
+			From:   from.RelayerID,
+			To:     newRelayerID,
+			Height: *from.LatestProcessedBlock,
+		})
+	}
+	return migrations, nil
+}
+
+// NewMigration maps the relayer ID [to] to the checkpoint of the relayer ID [from]
+func NewMigration(db database.RelayerDatabase, from database.RelayerID, to database.RelayerID) (Migration, error) {
+	height, err := database.GetLatestProcessedBlockHeight(db, from)
+	if err != nil {
+		return Migration{}, fmt.Errorf("failed to get checkpoint of relayer ID %s: %w", from.ID, err)
+	}
+	return Migration{
+		From:   from.ID,
+		To:     to,
+		Height: height,
+	}, nil
+}
+
+// ApplyMigrations writes the checkpoints of [migrations]. Existing checkpoints are overwritten.
+func ApplyMigrations(db database.RelayerDatabase, migrations []Migration) error {
+	for _, migration := range migrations {
+		if _, err := writeCheckpoint(db, migration.To, migration.Height, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes [height] as the checkpoint of [relayerID]. Returns false if [relayerID] already has a checkpoint
+// and [overwrite] is not set.
+func writeCheckpoint(
+	db database.RelayerDatabase,
+	relayerID database.RelayerID,
+	height uint64,
+	overwrite bool,
+) (bool, error) {
+	value := []byte(strconv.FormatUint(height, 10))
+	if !overwrite {
+		// Only write the checkpoint if none is stored, even if one is written concurrently by a running relayer
+		written, err := db.CompareAndSwap(relayerID.ID, database.LatestProcessedBlockKey, nil, value)
+		if err != nil {
+			return false, fmt.Errorf("failed to write checkpoint of relayer ID %s: %w", relayerID.ID, err)
+		}
+		return written, nil
+	}
+	err := db.Put(relayerID.ID, database.LatestProcessedBlockKey, value)
+	if err != nil {
+		return false, fmt.Errorf("failed to write checkpoint of relayer ID %s: %w", relayerID.ID, err)
+	}
+	return true, nil
+}
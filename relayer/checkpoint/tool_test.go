@@ -0,0 +1,117 @@
+package checkpoint
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRelayerID(source ids.ID, destination ids.ID, originSender common.Address) database.RelayerID {
+	return database.NewRelayerID(source, destination, originSender, common.Address{})
+}
+
+func newTestToolDatabase(t *testing.T, relayerIDs []database.RelayerID) database.RelayerDatabase {
+	db, err := database.NewJSONFileStorage(logging.NoLog{}, t.TempDir(), relayerIDs)
+	require.NoError(t, err)
+	return db
+}
+
+func putHeight(t *testing.T, db database.RelayerDatabase, relayerID database.RelayerID, height uint64) {
+	_, err := writeCheckpoint(db, relayerID, height, true)
+	require.NoError(t, err)
+}
+
+func requireHeight(t *testing.T, db database.RelayerDatabase, relayerID database.RelayerID, expected uint64) {
+	height, err := database.GetLatestProcessedBlockHeight(db, relayerID)
+	require.NoError(t, err)
+	require.Equal(t, expected, height)
+}
+
+func TestExportImportCheckpoints(t *testing.T) {
+	source, destination := ids.GenerateTestID(), ids.GenerateTestID()
+	id1 := newTestRelayerID(source, destination, common.HexToAddress("0x1"))
+	id2 := newTestRelayerID(source, destination, common.HexToAddress("0x2"))
+	id3 := newTestRelayerID(source, destination, common.HexToAddress("0x3"))
+	relayerIDs := []database.RelayerID{id1, id2, id3}
+
+	src := newTestToolDatabase(t, relayerIDs)
+	putHeight(t, src, id1, 10)
+	putHeight(t, src, id2, 20)
+
+	checkpoints, err := ListCheckpoints(src, relayerIDs)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 3)
+	require.Equal(t, id1.ID, checkpoints[0].RelayerID)
+	require.Equal(t, uint64(10), *checkpoints[0].LatestProcessedBlock)
+	require.Equal(t, uint64(20), *checkpoints[1].LatestProcessedBlock)
+	require.Nil(t, checkpoints[2].LatestProcessedBlock)
+	require.Equal(t, relayerIDs, RelayerIDs(checkpoints))
+
+	// Existing checkpoints are only replaced when overwriting
+	dst := newTestToolDatabase(t, relayerIDs)
+	putHeight(t, dst, id2, 5)
+	written, err := ImportCheckpoints(dst, checkpoints, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, written)
+	requireHeight(t, dst, id1, 10)
+	requireHeight(t, dst, id2, 5)
+	_, err = database.GetLatestProcessedBlockHeight(dst, id3)
+	require.True(t, database.IsKeyNotFoundError(err))
+
+	written, err = ImportCheckpoints(dst, checkpoints, true)
+	require.NoError(t, err)
+	require.Equal(t, 2, written)
+	requireHeight(t, dst, id2, 20)
+}
+
+func TestPlanMigrations(t *testing.T) {
+	sourceA, sourceB := ids.GenerateTestID(), ids.GenerateTestID()
+	destinationA, destinationB := ids.GenerateTestID(), ids.GenerateTestID()
+
+	oldAA1 := newTestRelayerID(sourceA, destinationA, common.HexToAddress("0x1"))
+	oldAA2 := newTestRelayerID(sourceA, destinationA, common.HexToAddress("0x2"))
+	oldAB := newTestRelayerID(sourceA, destinationB, common.HexToAddress("0x1"))
+	oldBA := newTestRelayerID(sourceB, destinationA, common.HexToAddress("0x1"))
+	oldRelayerIDs := []database.RelayerID{oldAA1, oldAA2, oldAB, oldBA}
+
+	// Same route as oldAA1 and oldAA2
+	newAA := newTestRelayerID(sourceA, destinationA, common.Address{})
+	// Only the source matches the old relayer IDs
+	newAC := newTestRelayerID(sourceA, ids.GenerateTestID(), common.Address{})
+	// Already has a checkpoint
+	newBA := newTestRelayerID(sourceB, destinationA, common.Address{})
+	// No old relayer IDs with the same source
+	newCA := newTestRelayerID(ids.GenerateTestID(), destinationA, common.Address{})
+	newRelayerIDs := []database.RelayerID{newAA, newAC, newBA, newCA}
+
+	db := newTestToolDatabase(t, append(append([]database.RelayerID{}, oldRelayerIDs...), newRelayerIDs...))
+	putHeight(t, db, oldAA1, 30)
+	putHeight(t, db, oldAA2, 20)
+	putHeight(t, db, oldAB, 10)
+	putHeight(t, db, oldBA, 40)
+	putHeight(t, db, newBA, 50)
+
+	migrations, err := PlanMigrations(db, oldRelayerIDs, newRelayerIDs)
+	require.NoError(t, err)
+	require.Equal(t, []Migration{
+		{From: oldAA2.ID, To: newAA, Height: 20},
+		{From: oldAB.ID, To: newAC, Height: 10},
+	}, migrations)
+
+	explicit, err := NewMigration(db, oldBA, newBA)
+	require.NoError(t, err)
+	require.NoError(t, ApplyMigrations(db, append(migrations, explicit)))
+	requireHeight(t, db, newAA, 20)
+	requireHeight(t, db, newAC, 10)
+	requireHeight(t, db, newBA, 40)
+	_, err = database.GetLatestProcessedBlockHeight(db, newCA)
+	require.True(t, database.IsKeyNotFoundError(err))
+
+	// Relayer IDs without a checkpoint cannot be migrated from
+	_, err = NewMigration(db, newCA, newAA)
+	require.Error(t, err)
+}
@@ -39,15 +39,38 @@ const (
 	defaultMetricsPort         = uint16(9090)
 	defaultIntervalSeconds     = uint64(10)
 	defaultSignatureCacheSize  = uint64(1024 * 1024)
+
+	defaultMessageRetryIntervalSeconds    = uint64(60)
+	defaultMessageRetryMaxIntervalSeconds = uint64(60 * 60)
+	defaultMessageRetryMaxAttempts        = uint64(10)
+
+	defaultDeliveryHistoryRetentionSeconds = uint64(7 * 24 * 60 * 60)
 )
 
 var defaultLogLevel = logging.Info.String()
 
+// StorageType identifies the backend used to store the relayer's state
+type StorageType string
+
+const (
+	// Stores the state of each relayer ID in a JSON file in the storage location
+	StorageTypeJSON StorageType = "json"
+	// Stores the state in the Redis server at the Redis URL
+	StorageTypeRedis StorageType = "redis"
+	// Stores the state in the PostgreSQL database at the Postgres URL
+	StorageTypePostgres StorageType = "postgres"
+	// Stores the state in an embedded LevelDB database in the storage location
+	StorageTypeLevelDB StorageType = "leveldb"
+	// Stores the state in an embedded Pebble database in the storage location
+	StorageTypePebble StorageType = "pebble"
+)
+
 const usageText = `
 Usage:
 awm-relayer --config-file path-to-config                Specifies the relayer config file and begin relaying messages.
 awm-relayer --version                                   Display awm-relayer version and exit.
 awm-relayer --help                                      Display awm-relayer usage and exit.
+awm-relayer checkpoint --help                           Display the checkpoint management subcommands and exit.
 `
 
 var errFailedToGetWarpQuorum = errors.New("failed to get warp quorum")
@@ -57,6 +80,7 @@ type Config struct {
 	LogLevel               string                   `mapstructure:"log-level" json:"log-level"`
 	StorageLocation        string                   `mapstructure:"storage-location" json:"storage-location"`
 	RedisURL               string                   `mapstructure:"redis-url" json:"redis-url"`
+	PostgresURL            string                   `mapstructure:"postgres-url" json:"postgres-url"`
 	APIPort                uint16                   `mapstructure:"api-port" json:"api-port"`
 	MetricsPort            uint16                   `mapstructure:"metrics-port" json:"metrics-port"`
 	DBWriteIntervalSeconds uint64                   `mapstructure:"db-write-interval-seconds" json:"db-write-interval-seconds"` //nolint:lll
@@ -68,6 +92,33 @@ type Config struct {
 	DeciderURL             string                   `mapstructure:"decider-url" json:"decider-url"`
 	SignatureCacheSize     uint64                   `mapstructure:"signature-cache-size" json:"signature-cache-size"`
 
+	// Caching of the canonical validator sets fetched from the P-Chain. Disabled if omitted.
+	ValidatorSetCache basecfg.ValidatorSetCacheConfig `mapstructure:"validator-set-cache" json:"validator-set-cache"`
+	// Policy used to retry collecting signatures for a Warp message. Omitted fields use their default values.
+	SignatureRetryPolicy basecfg.RetryPolicy `mapstructure:"signature-retry-policy" json:"signature-retry-policy"`
+	// Strategy used to select which validators to query for signatures. Either "all" or "weighted".
+	SignatureQueryStrategy string `mapstructure:"signature-query-strategy" json:"signature-query-strategy"`
+	// Connection to Redis, used with the Redis URL by the Redis storage type and leader election
+	Redis RedisConfig `mapstructure:"redis" json:"redis"`
+	// Backend used to store the relayer's state. One of "json", "redis", "postgres", "leveldb" or "pebble".
+	// Defaults to "redis" if the Redis URL or Redis addresses are set, and "json" otherwise.
+	StorageType string `mapstructure:"storage-type" json:"storage-type"`
+	// Election of a leader among instances sharing a Redis database. Disabled if omitted.
+	LeaderElection LeaderElectionConfig `mapstructure:"leader-election" json:"leader-election"`
+	// Division of the relayer IDs among multiple instances. Disabled if omitted.
+	Sharding ShardingConfig `mapstructure:"sharding" json:"sharding"`
+	// Interval at which messages that failed to be relayed are retried. Defaults to 60 seconds.
+	MessageRetryIntervalSeconds uint64 `mapstructure:"message-retry-interval-seconds" json:"message-retry-interval-seconds"` //nolint:lll
+	// Upper bound on the delay before retrying a message, which doubles after each failed attempt starting
+	// at the message retry interval. Defaults to an hour.
+	MessageRetryMaxIntervalSeconds uint64 `mapstructure:"message-retry-max-interval-seconds" json:"message-retry-max-interval-seconds"` //nolint:lll
+	// Number of failed attempts to relay a message, including the first, after which it is no longer retried
+	// and is moved to the dead letters. Defaults to 10.
+	MessageRetryMaxAttempts uint64 `mapstructure:"message-retry-max-attempts" json:"message-retry-max-attempts"`
+	// Time for which the deliveries of messages are kept in the delivery history. Older deliveries are pruned
+	// as new ones are recorded. Defaults to 7 days.
+	DeliveryHistoryRetentionSeconds uint64 `mapstructure:"delivery-history-retention-seconds" json:"delivery-history-retention-seconds"` //nolint:lll
+
 	// mapstructure doesn't handle time.Time out of the box so handle it manually
 	EtnaTime time.Time `json:"etna-time"`
 
@@ -110,6 +161,21 @@ func (c *Config) Validate() error {
 	if c.DBWriteIntervalSeconds == 0 || c.DBWriteIntervalSeconds > 600 {
 		return errors.New("db-write-interval-seconds must be between 1 and 600")
 	}
+	if err := c.SignatureRetryPolicy.Validate(); err != nil {
+		return err
+	}
+	if err := c.validateStorage(); err != nil {
+		return err
+	}
+	if err := c.LeaderElection.Validate(); err != nil {
+		return err
+	}
+	if err := c.Sharding.Validate(); err != nil {
+		return err
+	}
+	if c.MessageRetryMaxAttempts == 1 {
+		return errors.New("message-retry-max-attempts must be at least 2")
+	}
 
 	blockchainIDToSubnetID := make(map[ids.ID]ids.ID)
 
@@ -151,6 +217,34 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+func (c *Config) validateStorage() error {
+	storageType := c.GetStorageType()
+	if c.LeaderElection.Enabled && storageType != StorageTypeRedis {
+		return errors.New("leader election requires storage type redis")
+	}
+	// Shard claims are only visible to other instances through a shared database
+	if c.Sharding.Enabled() && storageType != StorageTypeRedis && storageType != StorageTypePostgres {
+		return errors.New("sharding requires storage type redis or postgres")
+	}
+	switch storageType {
+	case StorageTypeJSON, StorageTypeLevelDB, StorageTypePebble:
+	case StorageTypeRedis:
+		if c.RedisURL == "" && !c.Redis.HasAddresses() {
+			return errors.New("redis-url or redis cluster or sentinel addresses must be set for storage type redis")
+		}
+		if err := c.Redis.Validate(); err != nil {
+			return err
+		}
+	case StorageTypePostgres:
+		if c.PostgresURL == "" {
+			return errors.New("postgres-url must be set for storage type postgres")
+		}
+	default:
+		return fmt.Errorf("invalid storage type: %s", c.StorageType)
+	}
+	return nil
+}
+
 func (c *Config) GetSubnetID(blockchainID ids.ID) ids.ID {
 	return c.blockchainIDToSubnetID[blockchainID]
 }
@@ -247,6 +341,54 @@ func (c *Config) GetOverwrittenOptions() []string {
 // Top-level config getters
 //
 
+// GetStorageType returns the configured storage type. If not set, the Redis database is used if the Redis URL
+// or Redis addresses are set, and the JSON file storage otherwise.
+func (c *Config) GetStorageType() StorageType {
+	if c.StorageType != "" {
+		return StorageType(c.StorageType)
+	}
+	if c.RedisURL != "" || c.Redis.HasAddresses() {
+		return StorageTypeRedis
+	}
+	return StorageTypeJSON
+}
+
+// GetMessageRetryInterval returns the interval at which messages that failed to be relayed are retried
+func (c *Config) GetMessageRetryInterval() time.Duration {
+	if c.MessageRetryIntervalSeconds == 0 {
+		return time.Duration(defaultMessageRetryIntervalSeconds) * time.Second
+	}
+	return time.Duration(c.MessageRetryIntervalSeconds) * time.Second
+}
+
+// GetMessageRetryMaxInterval returns the upper bound on the delay before retrying a message, which is at least
+// the message retry interval
+func (c *Config) GetMessageRetryMaxInterval() time.Duration {
+	maxInterval := time.Duration(defaultMessageRetryMaxIntervalSeconds) * time.Second
+	if c.MessageRetryMaxIntervalSeconds != 0 {
+		maxInterval = time.Duration(c.MessageRetryMaxIntervalSeconds) * time.Second
+	}
+	return max(maxInterval, c.GetMessageRetryInterval())
+}
+
+// GetMessageRetryMaxAttempts returns the number of failed attempts after which a message is moved to the
+// dead letters
+func (c *Config) GetMessageRetryMaxAttempts() uint64 {
+	if c.MessageRetryMaxAttempts == 0 {
+		return defaultMessageRetryMaxAttempts
+	}
+	return c.MessageRetryMaxAttempts
+}
+
+// GetDeliveryHistoryRetention returns the time for which the deliveries of messages are kept in the
+// delivery history
+func (c *Config) GetDeliveryHistoryRetention() time.Duration {
+	if c.DeliveryHistoryRetentionSeconds == 0 {
+		return time.Duration(defaultDeliveryHistoryRetentionSeconds) * time.Second
+	}
+	return time.Duration(c.DeliveryHistoryRetentionSeconds) * time.Second
+}
+
 func (c *Config) GetWarpQuorum(blockchainID ids.ID) (WarpQuorum, error) {
 	for _, s := range c.DestinationBlockchains {
 		if blockchainID.String() == s.BlockchainID {
@@ -265,3 +407,7 @@ func (c *Config) GetPChainAPI() *basecfg.APIConfig {
 func (c *Config) GetInfoAPI() *basecfg.APIConfig {
 	return c.InfoAPI
 }
+
+func (c *Config) GetValidatorSetCacheConfig() *basecfg.ValidatorSetCacheConfig {
+	return &c.ValidatorSetCache
+}
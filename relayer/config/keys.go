@@ -10,19 +10,31 @@ const (
 	HelpKey       = "help"
 
 	// Top-level configuration keys
-	LogLevelKey               = "log-level"
-	PChainAPIKey              = "p-chain-api"
-	InfoAPIKey                = "info-api"
-	APIPortKey                = "api-port"
-	MetricsPortKey            = "metrics-port"
-	SourceBlockchainsKey      = "source-blockchains"
-	DestinationBlockchainsKey = "destination-blockchains"
-	AccountPrivateKeyKey      = "account-private-key"
-	StorageLocationKey        = "storage-location"
-	RedisURLKey               = "redis-url"
-	ProcessMissedBlocksKey    = "process-missed-blocks"
-	ManualWarpMessagesKey     = "manual-warp-messages"
-	DBWriteIntervalSecondsKey = "db-write-interval-seconds"
-	SignatureCacheSizeKey     = "signature-cache-size"
-	EtnaTimeKey               = "etna-time"
+	LogLevelKey                = "log-level"
+	PChainAPIKey               = "p-chain-api"
+	InfoAPIKey                 = "info-api"
+	APIPortKey                 = "api-port"
+	MetricsPortKey             = "metrics-port"
+	SourceBlockchainsKey       = "source-blockchains"
+	DestinationBlockchainsKey  = "destination-blockchains"
+	AccountPrivateKeyKey       = "account-private-key"
+	StorageLocationKey         = "storage-location"
+	RedisURLKey                = "redis-url"
+	RedisKey                   = "redis"
+	PostgresURLKey             = "postgres-url"
+	StorageTypeKey             = "storage-type"
+	LeaderElectionKey          = "leader-election"
+	ShardingKey                = "sharding"
+	MessageRetryIntervalKey    = "message-retry-interval-seconds"
+	MessageRetryMaxIntervalKey = "message-retry-max-interval-seconds"
+	MessageRetryMaxAttemptsKey = "message-retry-max-attempts"
+	DeliveryRetentionKey       = "delivery-history-retention-seconds"
+	ProcessMissedBlocksKey     = "process-missed-blocks"
+	ManualWarpMessagesKey      = "manual-warp-messages"
+	DBWriteIntervalSecondsKey  = "db-write-interval-seconds"
+	SignatureCacheSizeKey      = "signature-cache-size"
+	ValidatorSetCacheKey       = "validator-set-cache"
+	SignatureRetryPolicyKey    = "signature-retry-policy"
+	SignatureQueryStrategyKey  = "signature-query-strategy"
+	EtnaTimeKey                = "etna-time"
 )
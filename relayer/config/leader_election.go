@@ -0,0 +1,53 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultLeaderElectionLeaseSeconds = uint64(15)
+)
+
+// Leader election configuration. When enabled, relayer instances that share a Redis database elect a leader
+// for each relayer ID, and only the leader relays messages and writes checkpoints for it. Standby instances
+// take over the relayer IDs of a leader that stops renewing its leases.
+type LeaderElectionConfig struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// Identifies this instance as the holder of a lease. Must be unique across instances.
+	// Defaults to the host name and process ID, followed by a random suffix.
+	InstanceID string `mapstructure:"instance-id" json:"instance-id"`
+	// Duration of a lease, after which a standby instance may take over if the lease is not renewed.
+	// Defaults to 15 seconds.
+	LeaseSeconds uint64 `mapstructure:"lease-seconds" json:"lease-seconds"`
+	// Interval at which leases are renewed, and standby instances attempt to acquire them.
+	// Must be less than the lease duration. Defaults to a third of the lease duration.
+	RenewIntervalSeconds uint64 `mapstructure:"renew-interval-seconds" json:"renew-interval-seconds"`
+}
+
+func (c *LeaderElectionConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.RenewInterval() >= c.LeaseDuration() {
+		return errors.New("leader election renew interval must be less than the lease duration")
+	}
+	return nil
+}
+
+func (c *LeaderElectionConfig) LeaseDuration() time.Duration {
+	if c.LeaseSeconds == 0 {
+		return time.Duration(defaultLeaderElectionLeaseSeconds) * time.Second
+	}
+	return time.Duration(c.LeaseSeconds) * time.Second
+}
+
+func (c *LeaderElectionConfig) RenewInterval() time.Duration {
+	if c.RenewIntervalSeconds == 0 {
+		return c.LeaseDuration() / 3
+	}
+	return time.Duration(c.RenewIntervalSeconds) * time.Second
+}
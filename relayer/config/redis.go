@@ -0,0 +1,110 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Redis connection configuration, used by the Redis storage type and leader election. By default, the relayer
+// connects to the single Redis server at the Redis URL. Setting the cluster or sentinel addresses instead
+// connects to a Redis Cluster, or to the master monitored by Redis Sentinel, following failovers.
+type RedisConfig struct {
+	// Prepended to every key the relayer stores in Redis, so that multiple deployments can share a Redis
+	// deployment without overwriting each other's state
+	KeyPrefix string `mapstructure:"key-prefix" json:"key-prefix"`
+	// Addresses (host:port) of Redis Cluster nodes, used to discover the rest of the cluster
+	ClusterAddresses []string `mapstructure:"cluster-addresses" json:"cluster-addresses"`
+	// Addresses (host:port) of the Sentinels monitoring the master named MasterName
+	SentinelAddresses []string `mapstructure:"sentinel-addresses" json:"sentinel-addresses"`
+	MasterName        string   `mapstructure:"master-name" json:"master-name"`
+	// Password of the Sentinels, if they require one
+	SentinelPassword string `mapstructure:"sentinel-password" json:"sentinel-password"`
+	// Credentials and database index used with cluster or sentinel addresses. The Redis URL includes them
+	// otherwise. Redis Cluster only supports database 0.
+	Username string `mapstructure:"username" json:"username"`
+	Password string `mapstructure:"password" json:"password"`
+	DB       int    `mapstructure:"db" json:"db"`
+	// TLS options for the connections to Redis. TLS is also enabled by a Redis URL with the rediss scheme.
+	TLS RedisTLSConfig `mapstructure:"tls" json:"tls"`
+}
+
+// TLS options for the connections to Redis
+type RedisTLSConfig struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// PEM file of the certificate authorities used to verify the server. Defaults to the system's.
+	CAFile string `mapstructure:"ca-file" json:"ca-file"`
+	// PEM files of the client certificate and key, if the server requires client authentication
+	CertFile string `mapstructure:"cert-file" json:"cert-file"`
+	KeyFile  string `mapstructure:"key-file" json:"key-file"`
+	// Name used to verify the server's certificate. Defaults to the host the connection is made to.
+	ServerName         string `mapstructure:"server-name" json:"server-name"`
+	InsecureSkipVerify bool   `mapstructure:"insecure-skip-verify" json:"insecure-skip-verify"`
+}
+
+func (c *RedisConfig) Validate() error {
+	if len(c.ClusterAddresses) != 0 && len(c.SentinelAddresses) != 0 {
+		return errors.New("redis cluster-addresses and sentinel-addresses cannot both be set")
+	}
+	if (len(c.SentinelAddresses) != 0) != (c.MasterName != "") {
+		return errors.New("redis sentinel-addresses and master-name must be set together")
+	}
+	if len(c.ClusterAddresses) != 0 && c.DB != 0 {
+		return errors.New("redis cluster only supports db 0")
+	}
+	return c.TLS.Validate()
+}
+
+// HasAddresses returns whether the relayer connects to Redis through the cluster or sentinel addresses,
+// rather than the Redis URL
+func (c *RedisConfig) HasAddresses() bool {
+	return len(c.ClusterAddresses) != 0 || len(c.SentinelAddresses) != 0
+}
+
+func (c *RedisTLSConfig) Validate() error {
+	if !c.Enabled {
+		if c.CAFile != "" || c.CertFile != "" || c.KeyFile != "" || c.ServerName != "" || c.InsecureSkipVerify {
+			return errors.New("redis tls options are set, but tls is not enabled")
+		}
+		return nil
+	}
+	if (c.CertFile != "") != (c.KeyFile != "") {
+		return errors.New("redis tls cert-file and key-file must be set together")
+	}
+	return nil
+}
+
+// GetTLSConfig loads the configured certificates. Returns nil if TLS is not enabled.
+func (c *RedisTLSConfig) GetTLSConfig() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec
+	}
+	if c.CAFile != "" {
+		caPEM, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redis tls ca-file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in redis tls ca-file %s", c.CAFile)
+		}
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load redis tls client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
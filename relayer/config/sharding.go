@@ -0,0 +1,47 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultShardingRebalanceIntervalSeconds = uint64(10)
+)
+
+// Sharding configuration. When enabled, the relayer IDs are divided among the configured number of instances,
+// each of which relays for the relayer IDs assigned to its shard index. All instances must share a database
+// and use the same configuration apart from the shard index.
+type ShardingConfig struct {
+	// Number of shards the relayer IDs are divided among. Sharding is disabled if zero.
+	ShardCount uint64 `mapstructure:"shard-count" json:"shard-count"`
+	// Index of the shard of this instance, less than the shard count
+	ShardIndex uint64 `mapstructure:"shard-index" json:"shard-index"`
+	// Incremented each time the shard count changes, so that instances of a previous configuration cannot
+	// reclaim relayer IDs claimed under a newer one
+	Generation uint64 `mapstructure:"generation" json:"generation"`
+	// Interval at which the instance checks whether another instance has claimed its relayer IDs after a
+	// change to the shard count. Defaults to 10 seconds.
+	RebalanceIntervalSeconds uint64 `mapstructure:"rebalance-interval-seconds" json:"rebalance-interval-seconds"`
+}
+
+func (c *ShardingConfig) Enabled() bool {
+	return c.ShardCount != 0
+}
+
+func (c *ShardingConfig) Validate() error {
+	if c.Enabled() && c.ShardIndex >= c.ShardCount {
+		return fmt.Errorf("shard index %d must be less than the shard count %d", c.ShardIndex, c.ShardCount)
+	}
+	return nil
+}
+
+func (c *ShardingConfig) RebalanceInterval() time.Duration {
+	if c.RebalanceIntervalSeconds == 0 {
+		return time.Duration(defaultShardingRebalanceIntervalSeconds) * time.Second
+	}
+	return time.Duration(c.RebalanceIntervalSeconds) * time.Second
+}
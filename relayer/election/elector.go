@@ -0,0 +1,178 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package election
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ava-labs/awm-relayer/relayer/config"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// Locker grants time-limited leases on keys to their owners
+type Locker interface {
+	// Acquire grants the lease on [key] to [owner] for [ttl] if no other owner holds it, and renews it if
+	// [owner] already holds it. Returns whether [owner] holds the lease.
+	Acquire(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error)
+	// Release gives up the lease on [key] if it is held by [owner]
+	Release(ctx context.Context, key string, owner string) error
+}
+
+// Elector campaigns for the leadership of a set of relayer IDs, so that only one of the instances sharing a
+// database relays for each relayer ID at a time. The leader of a relayer ID holds its lease, and renews it
+// periodically. If the leader stops renewing its lease, another instance acquires it once it expires.
+type Elector struct {
+	logger        logging.Logger
+	locker        Locker
+	instanceID    string
+	leaseDuration time.Duration
+	renewInterval time.Duration
+	relayerIDs    []database.RelayerID
+	// Time in Unix nanoseconds at which the lease held on each relayer ID expires. Zero if the lease is not held.
+	expiries map[common.Hash]*atomic.Int64
+	clock    func() time.Time
+}
+
+func NewElector(
+	logger logging.Logger,
+	locker Locker,
+	cfg *config.LeaderElectionConfig,
+	relayerIDs []database.RelayerID,
+) (*Elector, error) {
+	instanceID := cfg.InstanceID
+	if instanceID == "" {
+		var err error
+		instanceID, err = defaultInstanceID()
+		if err != nil {
+			return nil, err
+		}
+	}
+	expiries := make(map[common.Hash]*atomic.Int64, len(relayerIDs))
+	for _, relayerID := range relayerIDs {
+		expiries[relayerID.ID] = atomic.NewInt64(0)
+	}
+	logger.Info(
+		"Creating leader elector",
+		zap.String("instanceID", instanceID),
+		zap.Duration("leaseDuration", cfg.LeaseDuration()),
+	)
+	return &Elector{
+		logger:        logger,
+		locker:        locker,
+		instanceID:    instanceID,
+		leaseDuration: cfg.LeaseDuration(),
+		renewInterval: cfg.RenewInterval(),
+		relayerIDs:    relayerIDs,
+		expiries:      expiries,
+		clock:         time.Now,
+	}, nil
+}
+
+// IsLeader returns whether this instance holds the lease on [relayerID]. The lease is considered expired
+// once its duration has elapsed since it was last requested, which is no later than it expires in the
+// locker, so that a leader that fails to renew its lease stops relaying before another instance takes over.
+func (e *Elector) IsLeader(relayerID common.Hash) bool {
+	expiry, ok := e.expiries[relayerID]
+	if !ok {
+		return false
+	}
+	return e.clock().UnixNano() < expiry.Load()
+}
+
+// Run campaigns for the leadership of each relayer ID every renew interval until [ctx] is cancelled,
+// then releases the leases it holds.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.renewInterval)
+	defer ticker.Stop()
+	for {
+		e.Campaign(ctx)
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Campaign acquires or renews the lease on each relayer ID
+func (e *Elector) Campaign(ctx context.Context) {
+	for _, relayerID := range e.relayerIDs {
+		wasLeader := e.IsLeader(relayerID.ID)
+		requestedAt := e.clock()
+		acquired, err := e.locker.Acquire(ctx, leaseKey(relayerID.ID), e.instanceID, e.leaseDuration)
+		if err != nil {
+			// The lease may still be held, so it is left to expire unless it is renewed later
+			e.logger.Warn(
+				"Failed to acquire lease",
+				zap.String("relayerID", relayerID.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		if !acquired {
+			e.expiries[relayerID.ID].Store(0)
+			if wasLeader {
+				e.logger.Warn(
+					"Lost leadership of relayer ID",
+					zap.String("relayerID", relayerID.ID.String()),
+				)
+			}
+			continue
+		}
+		e.expiries[relayerID.ID].Store(requestedAt.Add(e.leaseDuration).UnixNano())
+		if !wasLeader {
+			e.logger.Info(
+				"Acquired leadership of relayer ID",
+				zap.String("relayerID", relayerID.ID.String()),
+				zap.String("instanceID", e.instanceID),
+			)
+		}
+	}
+}
+
+// Releases the leases held by this instance so that standby instances can take over without waiting
+// for them to expire
+func (e *Elector) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), e.renewInterval)
+	defer cancel()
+	for _, relayerID := range e.relayerIDs {
+		if !e.IsLeader(relayerID.ID) {
+			continue
+		}
+		e.expiries[relayerID.ID].Store(0)
+		if err := e.locker.Release(ctx, leaseKey(relayerID.ID), e.instanceID); err != nil {
+			e.logger.Warn(
+				"Failed to release lease",
+				zap.String("relayerID", relayerID.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func leaseKey(relayerID common.Hash) string {
+	return relayerID.Hex() + "-leader"
+}
+
+func defaultInstanceID() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("failed to get host name: %w", err)
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix)), nil
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package election
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ava-labs/awm-relayer/relayer/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// In-memory Locker whose leases expire according to a shared test clock
+type testLocker struct {
+	lock    sync.Mutex
+	now     *time.Time
+	leases  map[string]testLease
+	failing bool
+}
+
+type testLease struct {
+	owner  string
+	expiry time.Time
+}
+
+func (l *testLocker) Acquire(_ context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.failing {
+		return false, errors.New("locker unavailable")
+	}
+	lease, ok := l.leases[key]
+	if ok && lease.owner != owner && l.now.Before(lease.expiry) {
+		return false, nil
+	}
+	l.leases[key] = testLease{owner: owner, expiry: l.now.Add(ttl)}
+	return true, nil
+}
+
+func (l *testLocker) Release(_ context.Context, key string, owner string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if lease, ok := l.leases[key]; ok && lease.owner == owner {
+		delete(l.leases, key)
+	}
+	return nil
+}
+
+func newTestElector(
+	t *testing.T,
+	locker Locker,
+	now *time.Time,
+	instanceID string,
+	relayerIDs []database.RelayerID,
+) *Elector {
+	cfg := &config.LeaderElectionConfig{
+		Enabled:    true,
+		InstanceID: instanceID,
+	}
+	elector, err := NewElector(logging.NoLog{}, locker, cfg, relayerIDs)
+	require.NoError(t, err)
+	elector.clock = func() time.Time { return *now }
+	return elector
+}
+
+func TestElector(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	locker := &testLocker{now: &now, leases: make(map[string]testLease)}
+	relayerIDs := []database.RelayerID{
+		database.NewRelayerID(ids.GenerateTestID(), ids.GenerateTestID(), common.Address{}, common.Address{}),
+		database.NewRelayerID(ids.GenerateTestID(), ids.GenerateTestID(), common.Address{}, common.Address{}),
+	}
+	a := newTestElector(t, locker, &now, "a", relayerIDs)
+	b := newTestElector(t, locker, &now, "b", relayerIDs)
+	ctx := context.Background()
+
+	requireLeader := func(leader *Elector, standby *Elector) {
+		for _, relayerID := range relayerIDs {
+			require.True(t, leader.IsLeader(relayerID.ID))
+			require.False(t, standby.IsLeader(relayerID.ID))
+		}
+	}
+
+	// The first instance to campaign becomes the leader
+	a.Campaign(ctx)
+	b.Campaign(ctx)
+	requireLeader(a, b)
+	require.False(t, a.IsLeader(common.Hash{}))
+
+	// The leader keeps its leases by renewing them
+	now = now.Add(a.renewInterval)
+	a.Campaign(ctx)
+	b.Campaign(ctx)
+	now = now.Add(a.leaseDuration - time.Second)
+	b.Campaign(ctx)
+	requireLeader(a, b)
+
+	// The leader stops being the leader once its leases expire without being renewed,
+	// even if it cannot reach the locker
+	locker.failing = true
+	a.Campaign(ctx)
+	now = now.Add(time.Second)
+	for _, relayerID := range relayerIDs {
+		require.False(t, a.IsLeader(relayerID.ID))
+	}
+
+	// The standby takes over once the leases expire
+	locker.failing = false
+	b.Campaign(ctx)
+	a.Campaign(ctx)
+	requireLeader(b, a)
+
+	// Leases are released when the elector stops, so the standby takes over immediately
+	runCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	b.Run(runCtx)
+	a.Campaign(ctx)
+	requireLeader(a, b)
+}
+
+func TestRedisLocker(t *testing.T) {
+	redisURL := os.Getenv("AWM_RELAYER_TEST_REDIS_URL")
+	if redisURL == "" {
+		t.Skip("AWM_RELAYER_TEST_REDIS_URL not set")
+	}
+	locker, err := NewRedisLocker(logging.NoLog{}, redisURL, &config.RedisConfig{KeyPrefix: "test/"})
+	require.NoError(t, err)
+	defer locker.Close()
+
+	ctx := context.Background()
+	key := leaseKey(common.Hash(ids.GenerateTestID()))
+	ttl := 500 * time.Millisecond
+
+	held, err := locker.Acquire(ctx, key, "a", ttl)
+	require.NoError(t, err)
+	require.True(t, held)
+	held, err = locker.Acquire(ctx, key, "b", ttl)
+	require.NoError(t, err)
+	require.False(t, held)
+	// Renewing extends the lease
+	held, err = locker.Acquire(ctx, key, "a", ttl)
+	require.NoError(t, err)
+	require.True(t, held)
+
+	// Only the owner can release the lease
+	require.NoError(t, locker.Release(ctx, key, "b"))
+	held, err = locker.Acquire(ctx, key, "b", ttl)
+	require.NoError(t, err)
+	require.False(t, held)
+	require.NoError(t, locker.Release(ctx, key, "a"))
+	held, err = locker.Acquire(ctx, key, "b", ttl)
+	require.NoError(t, err)
+	require.True(t, held)
+
+	// The lease expires if it is not renewed
+	time.Sleep(2 * ttl)
+	held, err = locker.Acquire(ctx, key, "a", ttl)
+	require.NoError(t, err)
+	require.True(t, held)
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package election
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ava-labs/awm-relayer/relayer/config"
+	"github.com/redis/go-redis/v9"
+)
+
+var _ Locker = &RedisLocker{}
+
+var (
+	// Sets the key to the owner with the given TTL in milliseconds if it is not set, or extends its TTL if it
+	// is already set to the owner. Returns 1 if the owner holds the lease.
+	acquireLeaseScript = redis.NewScript(`
+local owner = redis.call("GET", KEYS[1])
+if owner == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+if owner then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+	// Deletes the key if it is set to the owner
+	releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+)
+
+// RedisLocker implements Locker using keys of a Redis server that expire with the lease. Keys are prefixed with
+// the configured Redis key prefix, like the keys of the Redis database.
+type RedisLocker struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+func NewRedisLocker(logger logging.Logger, redisURL string, cfg *config.RedisConfig) (*RedisLocker, error) {
+	client, err := database.NewRedisClient(logger, redisURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisLocker{
+		client:    client,
+		keyPrefix: cfg.KeyPrefix,
+	}, nil
+}
+
+func (l *RedisLocker) Acquire(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	keys := []string{l.keyPrefix + key}
+	held, err := acquireLeaseScript.Run(ctx, l.client, keys, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return held == 1, nil
+}
+
+func (l *RedisLocker) Release(ctx context.Context, key string, owner string) error {
+	return releaseLeaseScript.Run(ctx, l.client, []string{l.keyPrefix + key}, owner).Err()
+}
+
+// Ping checks that the Redis server can be reached
+func (l *RedisLocker) Ping(ctx context.Context) error {
+	return l.client.Ping(ctx).Err()
+}
+
+// Close closes the connection to the Redis server
+func (l *RedisLocker) Close() error {
+	return l.client.Close()
+}
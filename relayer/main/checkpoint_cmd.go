@@ -0,0 +1,322 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ava-labs/awm-relayer/relayer/checkpoint"
+	"github.com/ava-labs/awm-relayer/relayer/config"
+	"github.com/ava-labs/awm-relayer/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/pflag"
+)
+
+const checkpointCommand = "checkpoint"
+
+const checkpointUsageText = `
+Usage:
+awm-relayer checkpoint list --config-file path-to-config
+    List the relayer IDs of the config and their latest processed blocks.
+awm-relayer checkpoint export --config-file path-to-config [--output path-to-file]
+    Export the checkpoints of the relayer IDs of the config as JSON, to stdout if no output file is specified.
+awm-relayer checkpoint import --config-file path-to-config --input path-to-file [--overwrite]
+    Import exported checkpoints into the database of the config. Relayer IDs that already have
+    a checkpoint are skipped unless --overwrite is set.
+awm-relayer checkpoint migrate --config-file path-to-config --old-config-file path-to-old-config
+    [--map old-relayer-id=new-relayer-id ...] [--dry-run]
+    Copy the checkpoints of the relayer IDs of the old config to the relayer IDs of the config that
+    do not have a checkpoint. Each relayer ID is mapped to the lowest checkpoint of the old relayer IDs
+    with the same source and destination blockchains, or else the same source blockchain. Mappings passed
+    with --map take precedence.
+
+The database is selected by the storage options of the config, which may be overridden by environment
+variables such as STORAGE_TYPE to move checkpoints between storage types. The relayer should not be running
+against the same database.
+`
+
+const (
+	outputKey        = "output"
+	inputKey         = "input"
+	overwriteKey     = "overwrite"
+	oldConfigFileKey = "old-config-file"
+	mapKey           = "map"
+	dryRunKey        = "dry-run"
+)
+
+// Runs the checkpoint subcommand with [args], the arguments following the subcommand name
+func runCheckpointCommand(args []string) error {
+	if len(args) == 0 || args[0] == "--"+config.HelpKey {
+		fmt.Print(checkpointUsageText)
+		return nil
+	}
+	switch args[0] {
+	case "list", "export", "import", "migrate":
+	default:
+		fmt.Print(checkpointUsageText)
+		return fmt.Errorf("unknown checkpoint command %s", args[0])
+	}
+	fs := pflag.NewFlagSet("awm-relayer checkpoint", pflag.ContinueOnError)
+	fs.String(config.ConfigFileKey, "", "Specifies the relayer config file")
+	fs.String(outputKey, "", "File to export checkpoints to")
+	fs.String(inputKey, "", "File to import checkpoints from")
+	fs.Bool(overwriteKey, false, "Overwrite existing checkpoints on import")
+	fs.String(oldConfigFileKey, "", "Specifies the previous relayer config file to migrate checkpoints from")
+	fs.StringArray(mapKey, nil, "Maps an old relayer ID to a new relayer ID, as old=new")
+	fs.Bool(dryRunKey, false, "Print the migrations without writing them")
+	if err := fs.Parse(args[1:]); err != nil {
+		fmt.Print(checkpointUsageText)
+		return fmt.Errorf("couldn't parse flags: %w", err)
+	}
+
+	cfg, err := loadConfig(fs.Lookup(config.ConfigFileKey).Value.String())
+	if err != nil {
+		return err
+	}
+	logLevel, err := logging.ToLevel(cfg.LogLevel)
+	if err != nil {
+		return fmt.Errorf("error with log level: %w", err)
+	}
+	// Log to stderr so that exported checkpoints can be written to stdout
+	logger := logging.NewLogger(
+		"awm-relayer",
+		logging.NewWrappedCore(
+			logLevel,
+			os.Stderr,
+			logging.JSON.ConsoleEncoder(),
+		),
+	)
+
+	switch args[0] {
+	case "list":
+		return listCheckpoints(logger, cfg)
+	case "export":
+		output, _ := fs.GetString(outputKey)
+		return exportCheckpoints(logger, cfg, output)
+	case "import":
+		input, _ := fs.GetString(inputKey)
+		overwrite, _ := fs.GetBool(overwriteKey)
+		return importCheckpoints(logger, cfg, input, overwrite)
+	case "migrate":
+		oldConfigFile, _ := fs.GetString(oldConfigFileKey)
+		mappings, _ := fs.GetStringArray(mapKey)
+		dryRun, _ := fs.GetBool(dryRunKey)
+		return migrateCheckpoints(logger, cfg, oldConfigFile, mappings, dryRun)
+	}
+	return nil
+}
+
+// Loads and validates the relayer config at [configFile], applying environment variable overrides
+func loadConfig(configFile string) (*config.Config, error) {
+	if configFile == "" {
+		return nil, fmt.Errorf("%s not set", config.ConfigFileKey)
+	}
+	fs := config.BuildFlagSet()
+	if err := fs.Set(config.ConfigFileKey, configFile); err != nil {
+		return nil, err
+	}
+	v, err := config.BuildViper(fs)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't configure flags: %w", err)
+	}
+	cfg, err := config.NewConfig(v)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't build config %s: %w", configFile, err)
+	}
+	return &cfg, nil
+}
+
+// Runs [f] with the database of [cfg], opened to store the state of [relayerIDs]
+func withDatabase(
+	logger logging.Logger,
+	cfg *config.Config,
+	relayerIDs []database.RelayerID,
+	f func(db database.RelayerDatabase) error,
+) error {
+	db, err := database.NewDatabaseWithRelayerIDs(logger, cfg, relayerIDs)
+	if err != nil {
+		return fmt.Errorf("failed to create database: %w", err)
+	}
+	if closer, ok := db.(io.Closer); ok {
+		defer closer.Close()
+	}
+	return f(db)
+}
+
+func listCheckpoints(logger logging.Logger, cfg *config.Config) error {
+	relayerIDs := database.GetConfigRelayerIDs(cfg)
+	return withDatabase(logger, cfg, relayerIDs, func(db database.RelayerDatabase) error {
+		checkpoints, err := checkpoint.ListCheckpoints(db, relayerIDs)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RELAYER ID\tSOURCE BLOCKCHAIN\tDESTINATION BLOCKCHAIN\tORIGIN SENDER\tDESTINATION\tLATEST BLOCK")
+		for _, c := range checkpoints {
+			height := "-"
+			if c.LatestProcessedBlock != nil {
+				height = strconv.FormatUint(*c.LatestProcessedBlock, 10)
+			}
+			fmt.Fprintf(
+				w,
+				"%s\t%s\t%s\t%s\t%s\t%s\n",
+				c.RelayerID,
+				c.SourceBlockchainID,
+				c.DestinationBlockchainID,
+				c.OriginSenderAddress,
+				c.DestinationAddress,
+				height,
+			)
+		}
+		return w.Flush()
+	})
+}
+
+func exportCheckpoints(logger logging.Logger, cfg *config.Config, output string) error {
+	relayerIDs := database.GetConfigRelayerIDs(cfg)
+	return withDatabase(logger, cfg, relayerIDs, func(db database.RelayerDatabase) error {
+		checkpoints, err := checkpoint.ListCheckpoints(db, relayerIDs)
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(checkpoints, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		if output == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		return os.WriteFile(output, data, 0o644)
+	})
+}
+
+func importCheckpoints(logger logging.Logger, cfg *config.Config, input string, overwrite bool) error {
+	if input == "" {
+		return fmt.Errorf("%s not set", inputKey)
+	}
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	var checkpoints []checkpoint.Checkpoint
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return fmt.Errorf("failed to unmarshal checkpoints: %w", err)
+	}
+	// The imported relayer IDs may not be those of the config
+	relayerIDs := checkpoint.RelayerIDs(checkpoints)
+	return withDatabase(logger, cfg, relayerIDs, func(db database.RelayerDatabase) error {
+		written, err := checkpoint.ImportCheckpoints(db, checkpoints, overwrite)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d of %d checkpoints\n", written, len(checkpoints))
+		return nil
+	})
+}
+
+func migrateCheckpoints(
+	logger logging.Logger,
+	cfg *config.Config,
+	oldConfigFile string,
+	mappings []string,
+	dryRun bool,
+) error {
+	if oldConfigFile == "" {
+		return fmt.Errorf("%s not set", oldConfigFileKey)
+	}
+	oldCfg, err := loadConfig(oldConfigFile)
+	if err != nil {
+		return err
+	}
+	oldRelayerIDs := database.GetConfigRelayerIDs(oldCfg)
+	newRelayerIDs := database.GetConfigRelayerIDs(cfg)
+	explicit, err := parseMappings(mappings, oldRelayerIDs, newRelayerIDs)
+	if err != nil {
+		return err
+	}
+
+	relayerIDs := append(append([]database.RelayerID{}, oldRelayerIDs...), newRelayerIDs...)
+	return withDatabase(logger, cfg, relayerIDs, func(db database.RelayerDatabase) error {
+		migrations, err := checkpoint.PlanMigrations(db, oldRelayerIDs, newRelayerIDs)
+		if err != nil {
+			return err
+		}
+		// Explicit mappings replace the planned migration of the same relayer ID
+		for _, mapping := range explicit {
+			migration, err := checkpoint.NewMigration(db, mapping[0], mapping[1])
+			if err != nil {
+				return err
+			}
+			replaced := false
+			for i := range migrations {
+				if migrations[i].To.ID == migration.To.ID {
+					migrations[i] = migration
+					replaced = true
+				}
+			}
+			if !replaced {
+				migrations = append(migrations, migration)
+			}
+		}
+
+		for _, migration := range migrations {
+			fmt.Printf("%s -> %s at block %d\n", migration.From, migration.To.ID, migration.Height)
+		}
+		if dryRun {
+			return nil
+		}
+		if err := checkpoint.ApplyMigrations(db, migrations); err != nil {
+			return err
+		}
+		fmt.Printf("Migrated %d checkpoints\n", len(migrations))
+		return nil
+	})
+}
+
+// Parses the old=new relayer ID pairs of [mappings], which must be relayer IDs of [oldRelayerIDs] and
+// [newRelayerIDs] respectively
+func parseMappings(
+	mappings []string,
+	oldRelayerIDs []database.RelayerID,
+	newRelayerIDs []database.RelayerID,
+) ([][2]database.RelayerID, error) {
+	pairs := make([][2]database.RelayerID, len(mappings))
+	for i, mapping := range mappings {
+		oldID, newID, ok := strings.Cut(mapping, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mapping %s, expected old-relayer-id=new-relayer-id", mapping)
+		}
+		var err error
+		if pairs[i][0], err = findRelayerID(oldRelayerIDs, oldID); err != nil {
+			return nil, fmt.Errorf("invalid mapping %s: %w", mapping, err)
+		}
+		if pairs[i][1], err = findRelayerID(newRelayerIDs, newID); err != nil {
+			return nil, fmt.Errorf("invalid mapping %s: %w", mapping, err)
+		}
+	}
+	return pairs, nil
+}
+
+func findRelayerID(relayerIDs []database.RelayerID, s string) (database.RelayerID, error) {
+	id, err := utils.HexOrCB58ToID(s)
+	if err != nil {
+		return database.RelayerID{}, err
+	}
+	for _, relayerID := range relayerIDs {
+		if relayerID.ID == common.Hash(id) {
+			return relayerID, nil
+		}
+	}
+	return database.RelayerID{}, fmt.Errorf("relayer ID %s not in config", s)
+}
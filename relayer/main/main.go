@@ -27,6 +27,8 @@ import (
 	"github.com/ava-labs/awm-relayer/relayer/api"
 	"github.com/ava-labs/awm-relayer/relayer/checkpoint"
 	"github.com/ava-labs/awm-relayer/relayer/config"
+	"github.com/ava-labs/awm-relayer/relayer/election"
+	"github.com/ava-labs/awm-relayer/relayer/sharding"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
 	sigAggMetrics "github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
 	"github.com/ava-labs/awm-relayer/utils"
@@ -45,6 +47,14 @@ import (
 var version = "v0.0.0-dev"
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == checkpointCommand {
+		if err := runCheckpointCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	fs := config.BuildFlagSet()
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		config.DisplayUsageText()
@@ -194,6 +204,49 @@ func main() {
 		panic(err)
 	}
 
+	// Services whose connectivity is reported by the health check
+	dependencies := make(map[string]api.Pinger)
+	if pinger, ok := db.(api.Pinger); ok {
+		dependencies["database"] = pinger
+	}
+
+	// Initialize sharding and leader election, if enabled. Relayer IDs are claimed and the first campaign
+	// completes before the application relayers are created, so that the instance relaying for each
+	// relayer ID at startup processes missed blocks as usual.
+	relayerIDs := database.GetConfigRelayerIDs(&cfg)
+	var leaderElectors relayer.LeaderElectors
+	if cfg.Sharding.Enabled() {
+		shard := sharding.NewShard(logger, db, &cfg.Sharding, relayerIDs)
+		if err := shard.Claim(); err != nil {
+			logger.Fatal("Failed to claim shard relayer IDs", zap.Error(err))
+			panic(err)
+		}
+		go shard.Run(context.Background())
+		leaderElectors = append(leaderElectors, shard)
+		// Only campaign for the leadership of the relayer IDs of this shard
+		relayerIDs = shard.AssignedRelayerIDs(relayerIDs)
+	}
+	if cfg.LeaderElection.Enabled {
+		locker, err := election.NewRedisLocker(logger, cfg.RedisURL, &cfg.Redis)
+		if err != nil {
+			logger.Fatal("Failed to create leader election locker", zap.Error(err))
+			panic(err)
+		}
+		dependencies["leader-election"] = locker
+		elector, err := election.NewElector(logger, locker, &cfg.LeaderElection, relayerIDs)
+		if err != nil {
+			logger.Fatal("Failed to create leader elector", zap.Error(err))
+			panic(err)
+		}
+		elector.Campaign(context.Background())
+		go elector.Run(context.Background())
+		leaderElectors = append(leaderElectors, elector)
+	}
+	var leaderElector relayer.LeaderElector
+	if len(leaderElectors) != 0 {
+		leaderElector = leaderElectors
+	}
+
 	// Initialize the global write ticker
 	ticker := utils.NewTicker(cfg.DBWriteIntervalSeconds)
 	go ticker.Run()
@@ -219,6 +272,11 @@ func main() {
 		panic(err)
 	}
 
+	queryStrategy, err := aggregator.ParseQueryStrategy(cfg.SignatureQueryStrategy)
+	if err != nil {
+		logger.Fatal("Invalid signature query strategy", zap.Error(err))
+		panic(err)
+	}
 	signatureAggregator, err := aggregator.NewSignatureAggregator(
 		network,
 		logger,
@@ -228,6 +286,8 @@ func main() {
 		),
 		messageCreator,
 		cfg.EtnaTime,
+		queryStrategy,
+		&cfg.SignatureRetryPolicy,
 	)
 	if err != nil {
 		logger.Fatal("Failed to create signature aggregator", zap.Error(err))
@@ -245,6 +305,7 @@ func main() {
 		sourceClients,
 		destinationClients,
 		signatureAggregator,
+		leaderElector,
 	)
 	if err != nil {
 		logger.Fatal("Failed to create application relayers", zap.Error(err))
@@ -258,17 +319,22 @@ func main() {
 	)
 
 	// Each Listener goroutine will have an atomic bool that it can set to false to indicate an unrecoverable error
-	api.HandleHealthCheck(logger, relayerHealth)
+	api.HandleHealthCheck(logger, relayerHealth, dependencies)
 	api.HandleRelay(logger, messageCoordinator)
 	api.HandleRelayMessage(logger, messageCoordinator)
+	api.HandleMessages(logger, db, database.GetConfigRelayerIDs(&cfg))
 
 	// start the health check server
 	go func() {
 		log.Fatalln(http.ListenAndServe(fmt.Sprintf(":%d", cfg.APIPort), nil))
 	}()
 
-	// Create listeners for each of the subnets configured as a source
 	errGroup, ctx := errgroup.WithContext(context.Background())
+
+	// Retry the messages that failed to be relayed in the background, so that they do not block checkpoints
+	go messageCoordinator.RetryQueuedMessages(ctx, cfg.GetMessageRetryInterval())
+
+	// Create listeners for each of the subnets configured as a source
 	for _, s := range cfg.SourceBlockchains {
 		sourceBlockchain := s
 
@@ -373,6 +439,7 @@ func createApplicationRelayers(
 	sourceClients map[ids.ID]ethclient.Client,
 	destinationClients map[ids.ID]vms.DestinationClient,
 	signatureAggregator *aggregator.SignatureAggregator,
+	leaderElector relayer.LeaderElector,
 ) (map[common.Hash]*relayer.ApplicationRelayer, map[ids.ID]uint64, error) {
 	applicationRelayers := make(map[common.Hash]*relayer.ApplicationRelayer)
 	minHeights := make(map[ids.ID]uint64)
@@ -396,6 +463,7 @@ func createApplicationRelayers(
 			currentHeight,
 			destinationClients,
 			signatureAggregator,
+			leaderElector,
 		)
 		if err != nil {
 			logger.Error(
@@ -432,6 +500,7 @@ func createApplicationRelayersForSourceChain(
 	currentHeight uint64,
 	destinationClients map[ids.ID]vms.DestinationClient,
 	signatureAggregator *aggregator.SignatureAggregator,
+	leaderElector relayer.LeaderElector,
 ) (map[common.Hash]*relayer.ApplicationRelayer, uint64, error) {
 	// Create the ApplicationRelayers
 	logger.Info(
@@ -494,8 +563,10 @@ func createApplicationRelayersForSourceChain(
 			destinationClients[relayerID.DestinationBlockchainID],
 			sourceBlockchain,
 			checkpointManager,
+			db,
 			cfg,
 			signatureAggregator,
+			leaderElector,
 		)
 		if err != nil {
 			logger.Error(
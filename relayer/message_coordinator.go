@@ -8,12 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/awm-relayer/database"
 	"github.com/ava-labs/awm-relayer/messages"
 	relayerTypes "github.com/ava-labs/awm-relayer/types"
+	"github.com/ava-labs/awm-relayer/utils"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/ethclient"
 	"github.com/ava-labs/subnet-evm/interfaces"
@@ -22,6 +24,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// errNotLeader is returned when relaying a message of a relayer ID that another instance is the leader of
+var errNotLeader = errors.New("not the leader of the relayer ID")
+
 // MessageCoordinator contains all the logic required to process messages in the relayer.
 // Other components such as the listeners or the API should pass messages to the MessageCoordinator
 // so that it can parse the message(s) and pass them the the proper ApplicationRelayer.
@@ -176,7 +181,10 @@ func (mc *MessageCoordinator) getApplicationRelayer(
 	return nil
 }
 
-func (mc *MessageCoordinator) ProcessWarpMessage(warpMessage *relayerTypes.WarpMessageInfo) (common.Hash, error) {
+func (mc *MessageCoordinator) ProcessWarpMessage(
+	ctx context.Context,
+	warpMessage *relayerTypes.WarpMessageInfo,
+) (common.Hash, error) {
 	appRelayer, handler, err := mc.getAppRelayerMessageHandler(warpMessage)
 	if err != nil {
 		mc.logger.Error(
@@ -190,11 +198,19 @@ func (mc *MessageCoordinator) ProcessWarpMessage(warpMessage *relayerTypes.WarpM
 		mc.logger.Error("Application relayer not found")
 		return common.Hash{}, errors.New("application relayer not found")
 	}
+	if !appRelayer.isLeader() {
+		mc.logger.Warn(
+			"Not the leader of the relayer ID",
+			zap.String("relayerID", appRelayer.relayerID.ID.String()),
+		)
+		return common.Hash{}, errNotLeader
+	}
 
-	return appRelayer.ProcessMessage(handler)
+	return appRelayer.ProcessMessage(ctx, handler, warpMessage.BlockNumber)
 }
 
 func (mc *MessageCoordinator) ProcessMessageID(
+	ctx context.Context,
 	blockchainID ids.ID,
 	messageID ids.ID,
 	blockNum *big.Int,
@@ -208,7 +224,7 @@ func (mc *MessageCoordinator) ProcessMessageID(
 		return common.Hash{}, fmt.Errorf("source client not set for blockchain: %s", blockchainID.String())
 	}
 
-	warpMessage, err := FetchWarpMessage(ethClient, messageID, blockNum)
+	warpMessage, err := FetchWarpMessage(ctx, ethClient, messageID, blockNum)
 	if err != nil {
 		mc.logger.Error(
 			"Failed to fetch warp from blockchain",
@@ -218,11 +234,12 @@ func (mc *MessageCoordinator) ProcessMessageID(
 		return common.Hash{}, fmt.Errorf("could not fetch warp message from ID: %w", err)
 	}
 
-	return mc.ProcessWarpMessage(warpMessage)
+	return mc.ProcessWarpMessage(ctx, warpMessage)
 }
 
 // Meant to be ran asynchronously. Errors should be sent to errChan.
 func (mc *MessageCoordinator) ProcessBlock(
+	ctx context.Context,
 	blockHeader *types.Header,
 	blockchainID ids.ID,
 	ethClient ethclient.Client,
@@ -237,6 +254,133 @@ func (mc *MessageCoordinator) ProcessBlock(
 	}
 
 	// Register each message in the block with the appropriate application relayer
+	messageHandlers := mc.getMessageHandlers(block)
+	// Initiate message relay of all registered messages
+	for _, appRelayer := range mc.applicationRelayers {
+		if appRelayer.sourceBlockchain.GetBlockchainID() != blockchainID {
+			continue
+		}
+		// Dispatch all messages in the block to the appropriate application relayer.
+		// An empty slice is still a valid argument to ProcessHeight; in this case the height is immediately committed.
+		handlers := messageHandlers[appRelayer.relayerID.ID]
+
+		// Only the leader of the relayer ID relays its messages
+		leader, tookOver := appRelayer.updateLeadership()
+		if !leader {
+			continue
+		}
+		if tookOver {
+			go mc.takeOver(ctx, appRelayer, block.BlockNumber, handlers, ethClient, errChan)
+			continue
+		}
+		go appRelayer.ProcessHeight(ctx, block.BlockNumber, handlers, errChan)
+	}
+}
+
+// RetryQueuedMessages retries relaying the messages queued after failing to be relayed every [interval],
+// until [ctx] is cancelled. Only the queues of the relayer IDs this instance is the leader of are retried.
+func (mc *MessageCoordinator) RetryQueuedMessages(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, appRelayer := range mc.applicationRelayers {
+				if appRelayer.isLeader() {
+					mc.retryQueuedMessages(ctx, appRelayer)
+				}
+			}
+		}
+	}
+}
+
+// Retries relaying each message in the retry queue of [appRelayer] that is due to be retried. The messages are
+// fetched from the source blockchain again, and relayed like messages relayed through the API.
+func (mc *MessageCoordinator) retryQueuedMessages(ctx context.Context, appRelayer *ApplicationRelayer) {
+	entries, err := database.ListRetryEntries(appRelayer.db, appRelayer.relayerID.ID)
+	if err != nil {
+		mc.logger.Error(
+			"Failed to list retry queue",
+			zap.String("relayerID", appRelayer.relayerID.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	now := time.Now()
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		if now.Before(entry.NextAttemptAt) {
+			continue
+		}
+		_, err := mc.ProcessMessageID(
+			ctx,
+			appRelayer.relayerID.SourceBlockchainID,
+			entry.WarpMessageID,
+			new(big.Int).SetUint64(entry.SourceBlockNumber),
+		)
+		if errors.Is(err, errNotLeader) {
+			// Another instance has taken over the relayer ID, and retries its queue
+			return
+		}
+		appRelayer.completeRetry(entry, err)
+	}
+}
+
+// Resumes relaying for [appRelayer] once this instance becomes the leader of its relayer ID while processing
+// block [height]. The blocks since the last checkpoint of the previous leader are processed in order before
+// [height]. Later blocks are processed concurrently as usual.
+func (mc *MessageCoordinator) takeOver(
+	ctx context.Context,
+	appRelayer *ApplicationRelayer,
+	height uint64,
+	handlers []messages.MessageHandler,
+	ethClient ethclient.Client,
+	errChan chan error,
+) {
+	fromHeight, err := appRelayer.resumeFromCheckpoint()
+	if err != nil {
+		errChan <- err
+		return
+	}
+	mc.logger.Info(
+		"Taking over relayer ID",
+		zap.String("relayerID", appRelayer.relayerID.ID.String()),
+		zap.Uint64("fromHeight", fromHeight),
+		zap.Uint64("height", height),
+	)
+	for catchUpHeight := fromHeight; catchUpHeight < height; catchUpHeight++ {
+		headerCtx, cancel := context.WithTimeout(ctx, utils.DefaultRPCRetryTimeout)
+		blockHeader, err := ethClient.HeaderByNumber(headerCtx, new(big.Int).SetUint64(catchUpHeight))
+		cancel()
+		if err != nil {
+			mc.logger.Error(
+				"Failed to get block header",
+				zap.Uint64("height", catchUpHeight),
+				zap.Error(err),
+			)
+			errChan <- err
+			return
+		}
+		block, err := relayerTypes.NewWarpBlockInfo(blockHeader, ethClient)
+		if err != nil {
+			mc.logger.Error("Failed to create Warp block info", zap.Error(err))
+			errChan <- err
+			return
+		}
+		appRelayer.ProcessHeight(ctx, catchUpHeight, mc.getMessageHandlers(block)[appRelayer.relayerID.ID], errChan)
+	}
+	appRelayer.ProcessHeight(ctx, height, handlers, errChan)
+}
+
+// Returns the handlers of the messages in [block], grouped by the ID of the application relayer that
+// relays them
+func (mc *MessageCoordinator) getMessageHandlers(
+	block *relayerTypes.WarpBlockInfo,
+) map[common.Hash][]messages.MessageHandler {
 	messageHandlers := make(map[common.Hash][]messages.MessageHandler)
 	for _, warpLogInfo := range block.Messages {
 		appRelayer, handler, err := mc.getAppRelayerMessageHandler(warpLogInfo)
@@ -255,25 +399,16 @@ func (mc *MessageCoordinator) ProcessBlock(
 		}
 		messageHandlers[appRelayer.relayerID.ID] = append(messageHandlers[appRelayer.relayerID.ID], handler)
 	}
-	// Initiate message relay of all registered messages
-	for _, appRelayer := range mc.applicationRelayers {
-		if appRelayer.sourceBlockchain.GetBlockchainID() != blockchainID {
-			continue
-		}
-		// Dispatch all messages in the block to the appropriate application relayer.
-		// An empty slice is still a valid argument to ProcessHeight; in this case the height is immediately committed.
-		handlers := messageHandlers[appRelayer.relayerID.ID]
-
-		go appRelayer.ProcessHeight(block.BlockNumber, handlers, errChan)
-	}
+	return messageHandlers
 }
 
 func FetchWarpMessage(
+	ctx context.Context,
 	ethClient ethclient.Client,
 	warpID ids.ID,
 	blockNum *big.Int,
 ) (*relayerTypes.WarpMessageInfo, error) {
-	logs, err := ethClient.FilterLogs(context.Background(), interfaces.FilterQuery{
+	logs, err := ethClient.FilterLogs(ctx, interfaces.FilterQuery{
 		Topics:    [][]common.Hash{{relayerTypes.WarpPrecompileLogFilter}, nil, {common.Hash(warpID)}},
 		Addresses: []common.Address{warp.ContractAddress},
 		FromBlock: blockNum,
@@ -0,0 +1,262 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	basecfg "github.com/ava-labs/awm-relayer/config"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ava-labs/awm-relayer/messages"
+	messageMocks "github.com/ava-labs/awm-relayer/messages/mocks"
+	"github.com/ava-labs/awm-relayer/relayer/config"
+	relayerTypes "github.com/ava-labs/awm-relayer/types"
+	evmMocks "github.com/ava-labs/awm-relayer/vms/evm/mocks"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// testCheckpointManager records the heights staged by ProcessHeight, in order
+type testCheckpointManager struct {
+	lock            sync.Mutex
+	committedHeight uint64
+	stagedHeights   []uint64
+}
+
+func (m *testCheckpointManager) Run() {}
+
+func (m *testCheckpointManager) StageCommittedHeight(height uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.stagedHeights = append(m.stagedHeights, height)
+}
+
+func (m *testCheckpointManager) FastForward(height uint64) uint64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.committedHeight = max(m.committedHeight, height)
+	return m.committedHeight
+}
+
+func (m *testCheckpointManager) getStagedHeights() []uint64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return slices.Clone(m.stagedHeights)
+}
+
+type testLeaderElector struct {
+	leader atomic.Bool
+}
+
+func (e *testLeaderElector) IsLeader(common.Hash) bool {
+	return e.leader.Load()
+}
+
+// Returns an application relayer of the relayer ID, storing its state in a fresh database, along with its
+// checkpoint manager and leader elector
+func newTestApplicationRelayer(
+	t *testing.T,
+	relayerID database.RelayerID,
+) (*ApplicationRelayer, *testCheckpointManager, *testLeaderElector) {
+	destinationBlockchainIDs := set.Of(relayerID.DestinationBlockchainID.String())
+	sourceBlockchain := config.SourceBlockchain{
+		SubnetID:     ids.GenerateTestID().String(),
+		BlockchainID: relayerID.SourceBlockchainID.String(),
+		VM:           config.EVM.String(),
+		RPCEndpoint:  basecfg.APIConfig{BaseURL: "http://localhost:9650"},
+		WSEndpoint:   basecfg.APIConfig{BaseURL: "ws://localhost:9650"},
+	}
+	require.NoError(t, sourceBlockchain.Validate(&destinationBlockchainIDs))
+
+	db, err := database.NewJSONFileStorage(logging.NoLog{}, t.TempDir(), []database.RelayerID{relayerID})
+	require.NoError(t, err)
+	relayerMetrics, err := NewApplicationRelayerMetrics(prometheus.NewRegistry())
+	require.NoError(t, err)
+	checkpointManager := &testCheckpointManager{}
+	leaderElector := &testLeaderElector{}
+	return &ApplicationRelayer{
+		logger:                  logging.NoLog{},
+		metrics:                 relayerMetrics,
+		sourceBlockchain:        sourceBlockchain,
+		relayerID:               relayerID,
+		checkpointManager:       checkpointManager,
+		db:                      db,
+		messageRetryInterval:    time.Minute,
+		messageRetryMaxInterval: 10 * time.Minute,
+		messageRetryMaxAttempts: 3,
+		leaderElector:           leaderElector,
+	}, checkpointManager, leaderElector
+}
+
+func newTestRelayerID() database.RelayerID {
+	return database.NewRelayerID(
+		ids.GenerateTestID(),
+		ids.GenerateTestID(),
+		database.AllAllowedAddress,
+		database.AllAllowedAddress,
+	)
+}
+
+// Returns a header of a block without Warp messages
+func newTestHeader(height uint64) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(height)}
+}
+
+func TestProcessBlockSkipsNonLeader(t *testing.T) {
+	relayerID := newTestRelayerID()
+	appRelayer, checkpointManager, leaderElector := newTestApplicationRelayer(t, relayerID)
+	checkpointManager.committedHeight = 9
+	appRelayer.leading.Store(true)
+	mc := NewMessageCoordinator(
+		logging.NoLog{},
+		nil,
+		map[common.Hash]*ApplicationRelayer{relayerID.ID: appRelayer},
+		nil,
+	)
+	errChan := make(chan error, 1)
+	ctx := context.Background()
+
+	// Another instance has become the leader, so the block is not processed
+	mc.ProcessBlock(ctx, newTestHeader(10), relayerID.SourceBlockchainID, nil, errChan)
+	require.Never(t, func() bool {
+		return len(checkpointManager.getStagedHeights()) != 0
+	}, 100*time.Millisecond, 10*time.Millisecond)
+	require.False(t, appRelayer.leading.Load())
+
+	// Once leadership is regained, blocks are processed from the checkpoint of the other leader
+	_, err := database.AdvanceLatestProcessedBlockHeight(appRelayer.db, relayerID, 10)
+	require.NoError(t, err)
+	leaderElector.leader.Store(true)
+	mc.ProcessBlock(ctx, newTestHeader(11), relayerID.SourceBlockchainID, nil, errChan)
+	require.Eventually(t, func() bool {
+		return slices.Equal([]uint64{11}, checkpointManager.getStagedHeights())
+	}, time.Second, 10*time.Millisecond)
+	require.Empty(t, errChan)
+}
+
+func TestProcessBlockTakeOverCatchesUp(t *testing.T) {
+	relayerID := newTestRelayerID()
+	appRelayer, checkpointManager, leaderElector := newTestApplicationRelayer(t, relayerID)
+	leaderElector.leader.Store(true)
+	// The previous leader checkpointed a greater height than this instance
+	checkpointManager.committedHeight = 2
+	_, err := database.AdvanceLatestProcessedBlockHeight(appRelayer.db, relayerID, 5)
+	require.NoError(t, err)
+
+	// The heights missed since the checkpoint are fetched in order, each with a deadline
+	ethClient := evmMocks.NewMockClient(gomock.NewController(t))
+	var calls []any
+	for height := uint64(6); height < 9; height++ {
+		calls = append(calls, ethClient.EXPECT().HeaderByNumber(gomock.Any(), new(big.Int).SetUint64(height)).DoAndReturn(
+			func(ctx context.Context, number *big.Int) (*types.Header, error) {
+				_, ok := ctx.Deadline()
+				require.True(t, ok)
+				return newTestHeader(number.Uint64()), nil
+			},
+		))
+	}
+	gomock.InOrder(calls...)
+
+	mc := NewMessageCoordinator(
+		logging.NoLog{},
+		nil,
+		map[common.Hash]*ApplicationRelayer{relayerID.ID: appRelayer},
+		nil,
+	)
+	errChan := make(chan error, 1)
+	mc.ProcessBlock(context.Background(), newTestHeader(9), relayerID.SourceBlockchainID, ethClient, errChan)
+
+	// The missed heights are replayed in order before the height being processed
+	require.Eventually(t, func() bool {
+		return slices.Equal([]uint64{6, 7, 8, 9}, checkpointManager.getStagedHeights())
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, uint64(5), checkpointManager.committedHeight)
+	require.Empty(t, errChan)
+}
+
+// messageHandlerFactoryStub creates the same message handler for every message
+type messageHandlerFactoryStub struct {
+	handler messages.MessageHandler
+}
+
+func (f *messageHandlerFactoryStub) NewMessageHandler(*avalancheWarp.UnsignedMessage) (messages.MessageHandler, error) {
+	return f.handler, nil
+}
+
+func TestRetryQueuedMessagesStopsWhenNotLeader(t *testing.T) {
+	relayerID := newTestRelayerID()
+	appRelayer, _, _ := newTestApplicationRelayer(t, relayerID)
+	protocolAddress := common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567")
+	unsignedMessage, err := avalancheWarp.NewUnsignedMessage(
+		constants.UnitTestID,
+		relayerID.SourceBlockchainID,
+		[]byte("payload"),
+	)
+	require.NoError(t, err)
+
+	// Two messages are due to be retried
+	entries := []*database.RetryEntry{
+		{WarpMessageID: unsignedMessage.ID(), SourceBlockNumber: 1, Attempts: 1},
+		{WarpMessageID: ids.GenerateTestID(), SourceBlockNumber: 2, Attempts: 1},
+	}
+	for _, entry := range entries {
+		require.NoError(t, database.PutRetryEntry(appRelayer.db, relayerID.ID, entry))
+	}
+
+	// Only the first message is fetched before finding that another instance is the leader
+	ctrl := gomock.NewController(t)
+	ethClient := evmMocks.NewMockClient(ctrl)
+	ethClient.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).Return([]types.Log{{
+		Topics: []common.Hash{
+			relayerTypes.WarpPrecompileLogFilter,
+			common.BytesToHash(protocolAddress[:]),
+			common.Hash(unsignedMessage.ID()),
+		},
+		Data:        unsignedMessage.Bytes(),
+		BlockNumber: 1,
+	}}, nil).Times(1)
+	handler := messageMocks.NewMockMessageHandler(ctrl)
+	handler.EXPECT().GetMessageRoutingInfo().Return(
+		relayerID.SourceBlockchainID,
+		database.AllAllowedAddress,
+		relayerID.DestinationBlockchainID,
+		database.AllAllowedAddress,
+		nil,
+	)
+
+	mc := NewMessageCoordinator(
+		logging.NoLog{},
+		map[ids.ID]map[common.Address]messages.MessageHandlerFactory{
+			relayerID.SourceBlockchainID: {
+				protocolAddress: &messageHandlerFactoryStub{handler: handler},
+			},
+		},
+		map[common.Hash]*ApplicationRelayer{relayerID.ID: appRelayer},
+		map[ids.ID]ethclient.Client{relayerID.SourceBlockchainID: ethClient},
+	)
+	mc.retryQueuedMessages(context.Background(), appRelayer)
+
+	// Neither message counts as a failed attempt
+	queued, err := database.ListRetryEntries(appRelayer.db, relayerID.ID)
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+	for _, entry := range queued {
+		require.Equal(t, uint64(1), entry.Attempts)
+	}
+}
@@ -4,6 +4,7 @@
 package relayer
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 
@@ -58,7 +59,7 @@ func connectToNonPrimaryNetworkPeers(
 	sourceBlockchain *config.SourceBlockchain,
 ) error {
 	subnetID := sourceBlockchain.GetSubnetID()
-	connectedValidators, err := network.ConnectToCanonicalValidators(subnetID)
+	connectedValidators, err := network.ConnectToCanonicalValidators(context.Background(), subnetID, 0)
 	if err != nil {
 		logger.Error(
 			"Failed to connect to canonical validators",
@@ -94,7 +95,7 @@ func connectToPrimaryNetworkPeers(
 	for _, destination := range sourceBlockchain.SupportedDestinations {
 		blockchainID := destination.GetBlockchainID()
 		subnetID := cfg.GetSubnetID(blockchainID)
-		connectedValidators, err := network.ConnectToCanonicalValidators(subnetID)
+		connectedValidators, err := network.ConnectToCanonicalValidators(context.Background(), subnetID, 0)
 		if err != nil {
 			logger.Error(
 				"Failed to connect to canonical validators",
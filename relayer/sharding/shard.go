@@ -0,0 +1,239 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sharding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ava-labs/awm-relayer/relayer/config"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// Claim records the shard that owns a relayer ID in the shared database
+type Claim struct {
+	ShardIndex uint64    `json:"shard-index"`
+	ShardCount uint64    `json:"shard-count"`
+	Generation uint64    `json:"generation"`
+	ClaimedAt  time.Time `json:"claimed-at"`
+}
+
+// Shard relays for the relayer IDs assigned to its index. When the shard count changes, relayer IDs are
+// handed over through the shared database: each instance claims the relayer IDs assigned to it on startup,
+// and stops relaying for relayer IDs that have since been claimed by another shard. The new owner of a
+// relayer ID resumes from the checkpoint written by the previous owner. Claims are ordered by the configured
+// generation, so that an instance of a previous configuration cannot take back a relayer ID claimed under a
+// newer one. Instances with the same shard index, count and generation are the same shard, such as the leader
+// and standby instances of a shard when leader election is enabled.
+type Shard struct {
+	logger            logging.Logger
+	db                database.RelayerDatabase
+	claim             Claim
+	rebalanceInterval time.Duration
+	// Whether the shard owns each relayer ID assigned to it. Relayer IDs that are not assigned to the shard
+	// are not present.
+	owned map[common.Hash]*atomic.Bool
+}
+
+func NewShard(
+	logger logging.Logger,
+	db database.RelayerDatabase,
+	cfg *config.ShardingConfig,
+	relayerIDs []database.RelayerID,
+) *Shard {
+	owned := make(map[common.Hash]*atomic.Bool)
+	for _, relayerID := range relayerIDs {
+		if AssignedShard(relayerID.ID, cfg.ShardCount) == cfg.ShardIndex {
+			owned[relayerID.ID] = atomic.NewBool(false)
+		}
+	}
+	logger.Info(
+		"Creating shard",
+		zap.Uint64("shardIndex", cfg.ShardIndex),
+		zap.Uint64("shardCount", cfg.ShardCount),
+		zap.Uint64("generation", cfg.Generation),
+		zap.Int("assignedRelayerIDs", len(owned)),
+		zap.Int("totalRelayerIDs", len(relayerIDs)),
+	)
+	return &Shard{
+		logger: logger,
+		db:     db,
+		claim: Claim{
+			ShardIndex: cfg.ShardIndex,
+			ShardCount: cfg.ShardCount,
+			Generation: cfg.Generation,
+		},
+		rebalanceInterval: cfg.RebalanceInterval(),
+		owned:             owned,
+	}
+}
+
+// AssignedShard returns the index of the shard that [relayerID] is assigned to among [shardCount] shards.
+// Relayer IDs are assigned by rendezvous hashing, a form of consistent hashing in which each relayer ID is
+// assigned to the shard with the highest hash of the relayer ID and shard index. When a shard is added, only
+// the relayer IDs assigned to the new shard move, and when a shard is removed, only its relayer IDs move.
+func AssignedShard(relayerID common.Hash, shardCount uint64) uint64 {
+	var (
+		assigned  uint64
+		maxWeight uint64
+	)
+	buf := make([]byte, common.HashLength+8)
+	copy(buf, relayerID[:])
+	for index := uint64(0); index < shardCount; index++ {
+		binary.BigEndian.PutUint64(buf[common.HashLength:], index)
+		hash := sha256.Sum256(buf)
+		weight := binary.BigEndian.Uint64(hash[:8])
+		if index == 0 || weight > maxWeight {
+			assigned, maxWeight = index, weight
+		}
+	}
+	return assigned
+}
+
+// IsLeader returns whether the shard relays for [relayerID], that is, whether [relayerID] is assigned to the
+// shard and has not since been claimed by another shard
+func (s *Shard) IsLeader(relayerID common.Hash) bool {
+	owned, ok := s.owned[relayerID]
+	return ok && owned.Load()
+}
+
+// AssignedRelayerIDs returns the relayer IDs of [relayerIDs] that are assigned to the shard
+func (s *Shard) AssignedRelayerIDs(relayerIDs []database.RelayerID) []database.RelayerID {
+	var assigned []database.RelayerID
+	for _, relayerID := range relayerIDs {
+		if _, ok := s.owned[relayerID.ID]; ok {
+			assigned = append(assigned, relayerID)
+		}
+	}
+	return assigned
+}
+
+// Claim records the shard as the owner of each relayer ID assigned to it in the database, unless the relayer ID
+// has been claimed by a shard of a newer generation, in which case the shard does not relay for it. Instances
+// of the shards that previously owned the relayer IDs stop relaying for them once they observe the claim.
+func (s *Shard) Claim() error {
+	s.claim.ClaimedAt = time.Now().UTC()
+	value, err := json.Marshal(s.claim)
+	if err != nil {
+		return err
+	}
+	for relayerID, owned := range s.owned {
+		claimed, err := s.claimRelayerID(relayerID, value)
+		if err != nil {
+			s.logger.Error(
+				"Failed to claim relayer ID",
+				zap.String("relayerID", relayerID.String()),
+				zap.Error(err),
+			)
+			return fmt.Errorf("failed to claim relayer ID %s: %w", relayerID, err)
+		}
+		owned.Store(claimed)
+	}
+	return nil
+}
+
+// claimRelayerID atomically replaces the claim on [relayerID] with [value], unless the existing claim is of a
+// newer generation. Returns whether the relayer ID was claimed.
+func (s *Shard) claimRelayerID(relayerID common.Hash, value []byte) (bool, error) {
+	for {
+		existing, err := s.db.Get(relayerID, database.ShardClaimKey)
+		switch {
+		case database.IsKeyNotFoundError(err):
+			existing = nil
+		case err != nil:
+			return false, err
+		default:
+			var claim Claim
+			if err := json.Unmarshal(existing, &claim); err != nil {
+				return false, fmt.Errorf("failed to unmarshal shard claim: %w", err)
+			}
+			if claim.Generation > s.claim.Generation {
+				s.logger.Warn(
+					"Relayer ID claimed by a shard of a newer generation. Not relaying for the relayer ID.",
+					zap.String("relayerID", relayerID.String()),
+					zap.Uint64("generation", s.claim.Generation),
+					zap.Uint64("claimGeneration", claim.Generation),
+					zap.Uint64("claimShardIndex", claim.ShardIndex),
+					zap.Uint64("claimShardCount", claim.ShardCount),
+				)
+				return false, nil
+			}
+		}
+		// Retry if another instance claimed the relayer ID in the meantime
+		swapped, err := s.db.CompareAndSwap(relayerID, database.ShardClaimKey, existing, value)
+		if err != nil {
+			return false, err
+		}
+		if swapped {
+			return true, nil
+		}
+	}
+}
+
+// Run checks for relayer IDs claimed by other instances every rebalance interval until [ctx] is cancelled
+func (s *Shard) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.rebalanceInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Rebalance()
+		}
+	}
+}
+
+// Rebalance stops relaying for the relayer IDs that have been claimed by another shard
+func (s *Shard) Rebalance() {
+	for relayerID, owned := range s.owned {
+		if !owned.Load() {
+			continue
+		}
+		value, err := s.db.Get(relayerID, database.ShardClaimKey)
+		if database.IsKeyNotFoundError(err) {
+			// No other shard has claimed the relayer ID
+			continue
+		}
+		if err != nil {
+			s.logger.Warn(
+				"Failed to get shard claim",
+				zap.String("relayerID", relayerID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		var claim Claim
+		if err := json.Unmarshal(value, &claim); err != nil {
+			s.logger.Warn(
+				"Failed to unmarshal shard claim",
+				zap.String("relayerID", relayerID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		if claim.ShardIndex == s.claim.ShardIndex &&
+			claim.ShardCount == s.claim.ShardCount &&
+			claim.Generation == s.claim.Generation {
+			continue
+		}
+		owned.Store(false)
+		s.logger.Info(
+			"Relayer ID claimed by another shard. Stopping relaying for the relayer ID.",
+			zap.String("relayerID", relayerID.String()),
+			zap.Uint64("claimShardIndex", claim.ShardIndex),
+			zap.Uint64("claimShardCount", claim.ShardCount),
+			zap.Uint64("claimGeneration", claim.Generation),
+			zap.Time("claimedAt", claim.ClaimedAt),
+		)
+	}
+}
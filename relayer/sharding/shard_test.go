@@ -0,0 +1,142 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sharding
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/database"
+	"github.com/ava-labs/awm-relayer/relayer/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func generateRelayerIDs(n int) []database.RelayerID {
+	relayerIDs := make([]database.RelayerID, n)
+	for i := range relayerIDs {
+		relayerIDs[i] = database.NewRelayerID(ids.GenerateTestID(), ids.GenerateTestID(), common.Address{}, common.Address{})
+	}
+	return relayerIDs
+}
+
+func TestAssignedShard(t *testing.T) {
+	relayerIDs := generateRelayerIDs(1000)
+	shardSizes := make([]int, 4)
+	for _, relayerID := range relayerIDs {
+		shard := AssignedShard(relayerID.ID, 4)
+		require.Less(t, shard, uint64(4))
+		require.Equal(t, shard, AssignedShard(relayerID.ID, 4))
+		shardSizes[shard]++
+
+		// Adding a shard only moves relayer IDs to the new shard
+		resizedShard := AssignedShard(relayerID.ID, 5)
+		require.True(t, resizedShard == shard || resizedShard == 4)
+
+		require.Equal(t, uint64(0), AssignedShard(relayerID.ID, 1))
+	}
+	// The relayer IDs are spread roughly evenly across the shards
+	for _, size := range shardSizes {
+		require.Greater(t, size, 150)
+		require.Less(t, size, 350)
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	relayerIDs := generateRelayerIDs(50)
+	db, err := database.NewJSONFileStorage(logging.NoLog{}, t.TempDir(), relayerIDs)
+	require.NoError(t, err)
+
+	newShard := func(index uint64, count uint64) *Shard {
+		cfg := &config.ShardingConfig{ShardIndex: index, ShardCount: count}
+		return NewShard(logging.NoLog{}, db, cfg, relayerIDs)
+	}
+
+	// Two shards divide the relayer IDs between them
+	shards := []*Shard{newShard(0, 2), newShard(1, 2)}
+	for _, shard := range shards {
+		require.NoError(t, shard.Claim())
+	}
+	for _, relayerID := range relayerIDs {
+		require.NotEqual(t, shards[0].IsLeader(relayerID.ID), shards[1].IsLeader(relayerID.ID))
+	}
+	require.Len(
+		t,
+		relayerIDs,
+		len(shards[0].AssignedRelayerIDs(relayerIDs))+len(shards[1].AssignedRelayerIDs(relayerIDs)),
+	)
+
+	// Another instance of the same shard does not take over its relayer IDs
+	standby := newShard(0, 2)
+	require.NoError(t, standby.Claim())
+	shards[0].Rebalance()
+	for _, relayerID := range shards[0].AssignedRelayerIDs(relayerIDs) {
+		require.True(t, shards[0].IsLeader(relayerID.ID))
+	}
+
+	// A third shard is added. The existing shards stop relaying for the relayer IDs assigned to it
+	// once it claims them, and keep relaying for the rest.
+	added := newShard(2, 3)
+	require.NoError(t, added.Claim())
+	require.NotEmpty(t, added.AssignedRelayerIDs(relayerIDs))
+	for _, shard := range shards {
+		shard.Rebalance()
+	}
+	for _, relayerID := range relayerIDs {
+		leaders := 0
+		for _, shard := range append(shards, added) {
+			if shard.IsLeader(relayerID.ID) {
+				leaders++
+			}
+		}
+		require.Equal(t, 1, leaders)
+		require.Equal(t, AssignedShard(relayerID.ID, 3) == 2, added.IsLeader(relayerID.ID))
+	}
+}
+
+func TestClaimGeneration(t *testing.T) {
+	relayerIDs := generateRelayerIDs(50)
+	db, err := database.NewJSONFileStorage(logging.NoLog{}, t.TempDir(), relayerIDs)
+	require.NoError(t, err)
+
+	newShard := func(index uint64, count uint64, generation uint64) *Shard {
+		cfg := &config.ShardingConfig{ShardIndex: index, ShardCount: count, Generation: generation}
+		return NewShard(logging.NoLog{}, db, cfg, relayerIDs)
+	}
+
+	// The relayer IDs are divided between two shards, and then between three with a new generation
+	var shards []*Shard
+	for index := uint64(0); index < 2; index++ {
+		shard := newShard(index, 2, 0)
+		require.NoError(t, shard.Claim())
+	}
+	for index := uint64(0); index < 3; index++ {
+		shard := newShard(index, 3, 1)
+		require.NoError(t, shard.Claim())
+		shards = append(shards, shard)
+	}
+
+	// An instance of the previous generation restarts, and does not take back the relayer IDs claimed under
+	// the newer generation
+	stale := newShard(0, 2, 0)
+	require.NoError(t, stale.Claim())
+	for _, shard := range shards {
+		shard.Rebalance()
+	}
+	for _, relayerID := range relayerIDs {
+		require.False(t, stale.IsLeader(relayerID.ID))
+		assigned := AssignedShard(relayerID.ID, 3)
+		for index, shard := range shards {
+			require.Equal(t, uint64(index) == assigned, shard.IsLeader(relayerID.ID))
+		}
+		value, err := db.Get(relayerID.ID, database.ShardClaimKey)
+		require.NoError(t, err)
+		var claim Claim
+		require.NoError(t, json.Unmarshal(value, &claim))
+		require.Equal(t, uint64(1), claim.Generation)
+		require.Equal(t, assigned, claim.ShardIndex)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/awm-relayer/peers"
+)
+
+// AggregationError is returned by CreateSignedMessage when a threshold of stake could not be connected to,
+// or a threshold of signatures could not be collected. It describes the state of the aggregation at the
+// time of failure, to help diagnose validator-side problems.
+type AggregationError struct {
+	// Either errNotEnoughConnectedStake or errNotEnoughSignatures
+	Err              error
+	ConnectedWeight  uint64
+	TotalWeight      uint64
+	SignedWeight     uint64
+	QuorumPercentage uint64
+	// Minimum signed weight required to reach the quorum
+	RequiredWeight uint64
+	// Validator nodes whose most recent signature request timed out
+	TimedOutNodes []ids.NodeID
+	// Validator nodes whose most recent response was not a valid signature
+	InvalidSignatureNodes []ids.NodeID
+	// Validator nodes that could not be connected to, or sent a signature request
+	UnconnectedNodes []ids.NodeID
+	// The message signed by the signatures that were collected, with a combined weight of SignedWeight.
+	// Nil if no signatures were collected.
+	PartialMessage *avalancheWarp.Message
+}
+
+func (e *AggregationError) Error() string {
+	return fmt.Sprintf(
+		"%s: signed weight %d, connected weight %d, required weight %d of total weight %d",
+		e.Err,
+		e.SignedWeight,
+		e.ConnectedWeight,
+		e.RequiredWeight,
+		e.TotalWeight,
+	)
+}
+
+func (e *AggregationError) Unwrap() error {
+	return e.Err
+}
+
+// newAggregationError builds the diagnostics for a failed aggregation. [outcomes] holds the most recent
+// outcome of each queried node, and may be nil if no nodes were queried.
+func newAggregationError(
+	err error,
+	connectedValidators *peers.ConnectedCanonicalValidators,
+	accumulatedSignatureWeight *big.Int,
+	quorumPercentage uint64,
+	outcomes map[ids.NodeID]queryOutcome,
+) *AggregationError {
+	aggErr := &AggregationError{
+		Err:              err,
+		ConnectedWeight:  connectedValidators.ConnectedWeight,
+		TotalWeight:      connectedValidators.TotalValidatorWeight,
+		SignedWeight:     accumulatedSignatureWeight.Uint64(),
+		QuorumPercentage: quorumPercentage,
+		RequiredWeight:   quorumWeight(connectedValidators.TotalValidatorWeight, quorumPercentage),
+	}
+	for _, validator := range connectedValidators.ValidatorSet {
+		for _, nodeID := range validator.NodeIDs {
+			switch outcomes[nodeID] {
+			case outcomeTimeout:
+				aggErr.TimedOutNodes = append(aggErr.TimedOutNodes, nodeID)
+			case outcomeInvalidSignature:
+				aggErr.InvalidSignatureNodes = append(aggErr.InvalidSignatureNodes, nodeID)
+			case outcomeSendFailure:
+				aggErr.UnconnectedNodes = append(aggErr.UnconnectedNodes, nodeID)
+			case outcomeSuccess, outcomeEmptySignature:
+			default:
+				if connectedValidators.ConnectedNodes != nil && !connectedValidators.ConnectedNodes.Contains(nodeID) {
+					aggErr.UnconnectedNodes = append(aggErr.UnconnectedNodes, nodeID)
+				}
+			}
+		}
+	}
+	return aggErr
+}
+
+// quorumWeight returns the minimum weight that satisfies utils.CheckStakeWeightPercentageExceedsThreshold
+func quorumWeight(totalWeight uint64, quorumPercentage uint64) uint64 {
+	required := new(big.Int).SetUint64(totalWeight)
+	required.Mul(required, new(big.Int).SetUint64(quorumPercentage))
+	// Round up, so that the required weight is sufficient to reach the quorum
+	required.Add(required, big.NewInt(99))
+	required.Div(required, big.NewInt(100))
+	return required.Uint64()
+}
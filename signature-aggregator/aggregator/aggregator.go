@@ -5,6 +5,7 @@ package aggregator
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -25,6 +26,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	basecfg "github.com/ava-labs/awm-relayer/config"
 	"github.com/ava-labs/awm-relayer/peers"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator/cache"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
@@ -37,14 +39,6 @@ import (
 
 type blsSignatureBuf [bls.SignatureLen]byte
 
-const (
-	// Number of retries to collect signatures from validators
-	maxRelayerQueryAttempts = 5
-	// Maximum amount of time to spend waiting (in addition to network round trip time per attempt)
-	// during relayer signature query routine
-	signatureRequestRetryWaitPeriodMs = 10_000
-)
-
 var (
 	codec       = msg.Codec
 	corethCodec = corethMsg.Codec
@@ -65,6 +59,13 @@ type SignatureAggregator struct {
 	metrics                 *metrics.SignatureAggregatorMetrics
 	cache                   *cache.Cache
 	etnaTime                time.Time
+	queryStrategy           QueryStrategy
+	validatorStats          *validatorStats
+	// Bounds the retry policy of every aggregation, and is used if none is given
+	retryPolicyLimit *basecfg.RetryPolicy
+	// Signature aggregations in progress, protected by flightsLock
+	flights     map[flightKey]*flight
+	flightsLock sync.Mutex
 }
 
 func NewSignatureAggregator(
@@ -74,6 +75,8 @@ func NewSignatureAggregator(
 	metrics *metrics.SignatureAggregatorMetrics,
 	messageCreator message.Creator,
 	etnaTime time.Time,
+	queryStrategy QueryStrategy,
+	retryPolicyLimit *basecfg.RetryPolicy,
 ) (*SignatureAggregator, error) {
 	cache, err := cache.NewCache(signatureCacheSize, logger)
 	if err != nil {
@@ -91,39 +94,136 @@ func NewSignatureAggregator(
 		currentRequestID:        atomic.Uint32{},
 		cache:                   cache,
 		etnaTime:                etnaTime,
+		queryStrategy:           queryStrategy,
+		validatorStats:          newValidatorStats(metrics),
+		retryPolicyLimit:        retryPolicyLimit,
+		flights:                 make(map[flightKey]*flight),
 	}
 	sa.currentRequestID.Store(rand.Uint32())
 	return &sa, nil
 }
 
+// CreateSignedMessage collects signatures for the unsigned message from the validators of the signing subnet,
+// retrying according to [retryPolicy] until [quorumPercentage] of the stake has signed. [retryPolicy] is clamped
+// to the aggregator's retry policy limit, which is used instead if [retryPolicy] is nil. Returns early with the
+// context's error if [ctx] is cancelled.
+//
+// The signing subnet's canonical validator set at [pChainHeight] is used, which should match the P-Chain height
+// against which the destination chain verifies the message. If [pChainHeight] is zero, the current canonical
+// validator set is used.
+//
+// If [ctx] was returned by WithBestEffort, the connected validators are queried even if they hold less than
+// [quorumPercentage] of the stake.
+//
+// Concurrent calls for the same message, signing subnet and quorum percentage share a single aggregation,
+// using the justification and retry policy of the first call. The shared aggregation is cancelled only once
+// every caller's context is cancelled.
 func (s *SignatureAggregator) CreateSignedMessage(
+	ctx context.Context,
 	unsignedMessage *avalancheWarp.UnsignedMessage,
 	justification []byte,
 	inputSigningSubnet ids.ID,
+	pChainHeight uint64,
 	quorumPercentage uint64,
+	retryPolicy *basecfg.RetryPolicy,
 ) (*avalancheWarp.Message, error) {
-	var signingSubnet ids.ID
-	var err error
-	// If signingSubnet is not set we default to the subnet of the source blockchain
-	sourceSubnet, err := s.getSubnetID(unsignedMessage.SourceChainID)
+	sourceSubnet, signingSubnet, err := s.getSigningSubnet(ctx, unsignedMessage.SourceChainID, inputSigningSubnet)
 	if err != nil {
-		return nil, fmt.Errorf(
+		return nil, err
+	}
+	bestEffort := isBestEffort(ctx)
+	key := flightKey{
+		messageID:        unsignedMessage.ID(),
+		signingSubnet:    signingSubnet,
+		pChainHeight:     pChainHeight,
+		quorumPercentage: quorumPercentage,
+		bestEffort:       bestEffort,
+	}
+	return s.coalesce(ctx, key, func(ctx context.Context) (*avalancheWarp.Message, error) {
+		var (
+			connectedValidators *peers.ConnectedCanonicalValidators
+			err                 error
+		)
+		if bestEffort {
+			connectedValidators, err = s.connectToCanonicalValidators(ctx, signingSubnet, pChainHeight)
+		} else {
+			connectedValidators, err = s.connectToQuorum(ctx, signingSubnet, pChainHeight, quorumPercentage)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return s.collectSignatures(
+			ctx,
+			unsignedMessage,
+			justification,
+			sourceSubnet,
+			signingSubnet,
+			connectedValidators,
+			quorumPercentage,
+			s.clampRetryPolicy(retryPolicy),
+		)
+	})
+}
+
+// Returns [retryPolicy] with defaults applied, clamped to the aggregator's retry policy limit if it has one
+func (s *SignatureAggregator) clampRetryPolicy(retryPolicy *basecfg.RetryPolicy) basecfg.RetryPolicy {
+	if s.retryPolicyLimit == nil {
+		return retryPolicy.WithDefaults()
+	}
+	return retryPolicy.ClampTo(s.retryPolicyLimit)
+}
+
+// Returns the subnet of the source blockchain, and the subnet whose validators sign the message.
+// If [inputSigningSubnet] is not set, the message is signed by the source blockchain's subnet.
+func (s *SignatureAggregator) getSigningSubnet(
+	ctx context.Context,
+	sourceBlockchainID ids.ID,
+	inputSigningSubnet ids.ID,
+) (ids.ID, ids.ID, error) {
+	sourceSubnet, err := s.getSubnetID(ctx, sourceBlockchainID)
+	if err != nil {
+		return ids.Empty, ids.Empty, fmt.Errorf(
 			"Source message subnet not found for chainID %s",
-			unsignedMessage.SourceChainID,
+			sourceBlockchainID,
 		)
 	}
 	if inputSigningSubnet == ids.Empty {
-		signingSubnet = sourceSubnet
-	} else {
-		signingSubnet = inputSigningSubnet
+		return sourceSubnet, sourceSubnet, nil
+	}
+	return sourceSubnet, inputSigningSubnet, nil
+}
+
+// Connects to the canonical validators of [signingSubnet] at [pChainHeight], and verifies that a [quorumPercentage]
+// of the stake weight is connected.
+func (s *SignatureAggregator) connectToQuorum(
+	ctx context.Context,
+	signingSubnet ids.ID,
+	pChainHeight uint64,
+	quorumPercentage uint64,
+) (*peers.ConnectedCanonicalValidators, error) {
+	connectedValidators, err := s.connectToCanonicalValidators(ctx, signingSubnet, pChainHeight)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkConnectedStake(connectedValidators, nil, quorumPercentage); err != nil {
+		return nil, err
 	}
+	return connectedValidators, nil
+}
 
-	connectedValidators, err := s.network.ConnectToCanonicalValidators(signingSubnet)
+// Connects to the canonical validators of [signingSubnet] at [pChainHeight], regardless of the stake weight connected.
+func (s *SignatureAggregator) connectToCanonicalValidators(
+	ctx context.Context,
+	signingSubnet ids.ID,
+	pChainHeight uint64,
+) (*peers.ConnectedCanonicalValidators, error) {
+	connectedValidators, err := s.network.ConnectToCanonicalValidators(ctx, signingSubnet, pChainHeight)
 	if err != nil {
 		msg := "Failed to connect to canonical validators"
 		s.logger.Error(
 			msg,
-			zap.String("warpMessageID", unsignedMessage.ID().String()),
+			zap.String("signingSubnetID", signingSubnet.String()),
+			zap.Uint64("pChainHeight", pChainHeight),
 			zap.Error(err),
 		)
 		s.metrics.FailuresToGetValidatorSet.Inc()
@@ -135,38 +235,68 @@ func (s *SignatureAggregator) CreateSignedMessage(
 		float64(connectedValidators.ConnectedWeight) /
 			float64(connectedValidators.TotalValidatorWeight) * 100,
 	)
+	return connectedValidators, nil
+}
 
-	if !utils.CheckStakeWeightPercentageExceedsThreshold(
-		big.NewInt(0).SetUint64(connectedValidators.ConnectedWeight),
+// Verifies that a [quorumPercentage] of the stake weight can be collected, either from connected validators,
+// or from the validators in [signatureMap] whose signatures are already known.
+func (s *SignatureAggregator) checkConnectedStake(
+	connectedValidators *peers.ConnectedCanonicalValidators,
+	signatureMap map[int][bls.SignatureLen]byte,
+	quorumPercentage uint64,
+) error {
+	availableWeight := new(big.Int).SetUint64(connectedValidators.ConnectedWeight)
+	for i := range signatureMap {
+		validator := connectedValidators.ValidatorSet[i]
+		connected := false
+		for _, nodeID := range validator.NodeIDs {
+			connected = connected || connectedValidators.ConnectedNodes.Contains(nodeID)
+		}
+		if !connected {
+			availableWeight.Add(availableWeight, new(big.Int).SetUint64(validator.Weight))
+		}
+	}
+	if utils.CheckStakeWeightPercentageExceedsThreshold(
+		availableWeight,
 		connectedValidators.TotalValidatorWeight,
 		quorumPercentage,
 	) {
-		s.logger.Error(
-			"Failed to connect to a threshold of stake",
-			zap.Uint64("connectedWeight", connectedValidators.ConnectedWeight),
-			zap.Uint64("totalValidatorWeight", connectedValidators.TotalValidatorWeight),
-			zap.Uint64("quorumPercentage", quorumPercentage),
-		)
-		s.metrics.FailuresToConnectToSufficientStake.Inc()
-		return nil, errNotEnoughConnectedStake
+		return nil
 	}
+	s.logger.Error(
+		"Failed to connect to a threshold of stake",
+		zap.Uint64("connectedWeight", connectedValidators.ConnectedWeight),
+		zap.Uint64("totalValidatorWeight", connectedValidators.TotalValidatorWeight),
+		zap.Uint64("quorumPercentage", quorumPercentage),
+	)
+	s.metrics.FailuresToConnectToSufficientStake.Inc()
+	return newAggregationError(
+		errNotEnoughConnectedStake,
+		connectedValidators,
+		big.NewInt(0),
+		quorumPercentage,
+		nil,
+	)
+}
 
-	accumulatedSignatureWeight := big.NewInt(0)
+// Collects signatures for the unsigned message from the already connected validators of the signing subnet.
+func (s *SignatureAggregator) collectSignatures(
+	ctx context.Context,
+	unsignedMessage *avalancheWarp.UnsignedMessage,
+	justification []byte,
+	sourceSubnet ids.ID,
+	signingSubnet ids.ID,
+	connectedValidators *peers.ConnectedCanonicalValidators,
+	quorumPercentage uint64,
+	policy basecfg.RetryPolicy,
+) (*avalancheWarp.Message, error) {
+	startTime := time.Now()
+	// The most recent outcome of each queried node, reported if aggregation fails
+	outcomes := make(map[ids.NodeID]queryOutcome)
 
-	signatureMap := make(map[int][bls.SignatureLen]byte)
-	if cachedSignatures, ok := s.cache.Get(unsignedMessage.ID()); ok {
-		for i, validator := range connectedValidators.ValidatorSet {
-			cachedSignature, found := cachedSignatures[cache.PublicKeyBytes(validator.PublicKeyBytes)]
-			if found {
-				signatureMap[i] = cachedSignature
-				accumulatedSignatureWeight.Add(
-					accumulatedSignatureWeight,
-					new(big.Int).SetUint64(validator.Weight),
-				)
-			}
-		}
-		s.metrics.SignatureCacheHits.Add(float64(len(signatureMap)))
-	}
+	signatureMap, accumulatedSignatureWeight := s.loadCachedSignatures(unsignedMessage, connectedValidators)
+	s.metrics.SignatureCacheHits.Add(float64(len(signatureMap)))
+	reportProgress(ctx, 0, accumulatedSignatureWeight, connectedValidators)
 	if signedMsg, err := s.aggregateIfSufficientWeight(
 		unsignedMessage,
 		signatureMap,
@@ -214,11 +344,24 @@ func (s *SignatureAggregator) CreateSignedMessage(
 	}
 
 	// Query the validators with retries. On each retry, query one node per unique BLS pubkey
-	for attempt := 1; attempt <= maxRelayerQueryAttempts; attempt++ {
-		responsesExpected := len(connectedValidators.ValidatorSet) - len(signatureMap)
+	attempt := uint64(1)
+	for ; ; attempt++ {
+		if ctx.Err() != nil {
+			return nil, s.cancelled(ctx, unsignedMessage, attempt)
+		}
+		// Validators that have already provided a signature are never selected, so none of their composite nodes
+		// are queried again.
+		selectedValidators := s.selectValidators(
+			connectedValidators,
+			signatureMap,
+			accumulatedSignatureWeight,
+			quorumPercentage,
+			attempt == policy.MaxAttempts,
+		)
+		responsesExpected := len(selectedValidators)
 		s.logger.Debug(
 			"Aggregator collecting signatures from peers.",
-			zap.Int("attempt", attempt),
+			zap.Uint64("attempt", attempt),
 			zap.String("sourceBlockchainID", unsignedMessage.SourceChainID.String()),
 			zap.String("signingSubnetID", signingSubnet.String()),
 			zap.Int("validatorSetSize", len(connectedValidators.ValidatorSet)),
@@ -226,12 +369,9 @@ func (s *SignatureAggregator) CreateSignedMessage(
 			zap.Int("responsesExpected", responsesExpected),
 		)
 
-		vdrSet := set.NewSet[ids.NodeID](len(connectedValidators.ValidatorSet))
-		for i, vdr := range connectedValidators.ValidatorSet {
-			// If we already have the signature for this validator, do not query any of the composite nodes again
-			if _, ok := signatureMap[i]; ok {
-				continue
-			}
+		vdrSet := set.NewSet[ids.NodeID](len(selectedValidators))
+		for _, i := range selectedValidators {
+			vdr := connectedValidators.ValidatorSet[i]
 
 			// TODO: Track failures and iterate through the validator's node list on subsequent query attempts
 			nodeID := vdr.NodeIDs[0]
@@ -254,6 +394,7 @@ func (s *SignatureAggregator) CreateSignedMessage(
 		}
 		responseChan := s.network.RegisterRequestID(requestID, vdrSet.Len())
 
+		sentAt := time.Now()
 		sentTo := s.network.Send(outMsg, vdrSet, sourceSubnet, subnets.NoOpAllower)
 		s.metrics.AppRequestCount.Inc()
 		s.logger.Debug(
@@ -273,12 +414,28 @@ func (s *SignatureAggregator) CreateSignedMessage(
 				)
 				responsesExpected--
 				s.metrics.FailuresSendingToNode.Inc()
+				s.validatorStats.record(nodeID, outcomeSendFailure, peers.DefaultAppRequestTimeout)
+				outcomes[nodeID] = outcomeSendFailure
 			}
 		}
 
 		responseCount := 0
 		if responsesExpected > 0 {
-			for response := range responseChan {
+			for {
+				var (
+					response message.InboundMessage
+					ok       bool
+				)
+				select {
+				case response, ok = <-responseChan:
+				case <-ctx.Done():
+					// Outstanding responses still need to be handled once they arrive
+					go drainResponses(responseChan)
+					return nil, s.cancelled(ctx, unsignedMessage, attempt)
+				}
+				if !ok {
+					break
+				}
 				s.logger.Debug(
 					"Processing response from node",
 					zap.String("nodeID", response.NodeID().String()),
@@ -288,12 +445,14 @@ func (s *SignatureAggregator) CreateSignedMessage(
 				signedMsg, relevant, err := s.handleResponse(
 					response,
 					sentTo,
+					sentAt,
 					requestID,
 					connectedValidators,
 					unsignedMessage,
 					signatureMap,
 					accumulatedSignatureWeight,
 					quorumPercentage,
+					outcomes,
 				)
 				if err != nil {
 					// don't increase node failures metric here, because we did
@@ -305,6 +464,7 @@ func (s *SignatureAggregator) CreateSignedMessage(
 				}
 				if relevant {
 					responseCount++
+					reportProgress(ctx, attempt, accumulatedSignatureWeight, connectedValidators)
 				}
 				// If we have sufficient signatures, return here.
 				if signedMsg != nil {
@@ -322,23 +482,110 @@ func (s *SignatureAggregator) CreateSignedMessage(
 				}
 			}
 		}
-		if attempt != maxRelayerQueryAttempts {
-			// Sleep such that all retries are uniformly spread across totalRelayerQueryPeriodMs
-			// TODO: We may want to consider an exponential back off rather than a uniform sleep period.
-			time.Sleep(time.Duration(signatureRequestRetryWaitPeriodMs/maxRelayerQueryAttempts) * time.Millisecond)
+		delay, retry := policy.NextDelay(attempt, startTime)
+		if !retry {
+			break
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, s.cancelled(ctx, unsignedMessage, attempt)
 		}
 	}
 	s.logger.Warn(
 		"Failed to collect a threshold of signatures",
-		zap.Int("attempts", maxRelayerQueryAttempts),
+		zap.Uint64("attempts", attempt),
 		zap.String("warpMessageID", unsignedMessage.ID().String()),
 		zap.Uint64("accumulatedWeight", accumulatedSignatureWeight.Uint64()),
 		zap.String("sourceBlockchainID", unsignedMessage.SourceChainID.String()),
 	)
-	return nil, errNotEnoughSignatures
+	aggErr := newAggregationError(
+		errNotEnoughSignatures,
+		connectedValidators,
+		accumulatedSignatureWeight,
+		quorumPercentage,
+		outcomes,
+	)
+	// Provide the signatures that were collected, for callers that accept a lower quorum
+	if len(signatureMap) > 0 {
+		partialMessage, err := s.newSignedMessage(unsignedMessage, signatureMap)
+		if err != nil {
+			return nil, err
+		}
+		aggErr.PartialMessage = partialMessage
+	}
+	return nil, aggErr
+}
+
+// Returns the cached signatures for the unsigned message from the validators in [connectedValidators], indexed
+// by their position in the canonical validator set, along with their combined weight.
+func (s *SignatureAggregator) loadCachedSignatures(
+	unsignedMessage *avalancheWarp.UnsignedMessage,
+	connectedValidators *peers.ConnectedCanonicalValidators,
+) (map[int][bls.SignatureLen]byte, *big.Int) {
+	signatureMap := make(map[int][bls.SignatureLen]byte)
+	signatureWeight := big.NewInt(0)
+	cachedSignatures, ok := s.cache.Get(unsignedMessage.ID())
+	if !ok {
+		return signatureMap, signatureWeight
+	}
+	for i, validator := range connectedValidators.ValidatorSet {
+		cachedSignature, found := cachedSignatures[cache.PublicKeyBytes(validator.PublicKeyBytes)]
+		if found {
+			signatureMap[i] = cachedSignature
+			signatureWeight.Add(signatureWeight, new(big.Int).SetUint64(validator.Weight))
+		}
+	}
+	return signatureMap, signatureWeight
+}
+
+// Logs the cancellation of a signature aggregation, and returns the cause wrapped in an error.
+func (s *SignatureAggregator) cancelled(
+	ctx context.Context,
+	unsignedMessage *avalancheWarp.UnsignedMessage,
+	attempt uint64,
+) error {
+	s.logger.Info(
+		"Signature aggregation cancelled",
+		zap.Uint64("attempt", attempt),
+		zap.String("warpMessageID", unsignedMessage.ID().String()),
+		zap.String("sourceBlockchainID", unsignedMessage.SourceChainID.String()),
+		zap.Error(ctx.Err()),
+	)
+	return fmt.Errorf("signature aggregation cancelled: %w", ctx.Err())
 }
 
-func (s *SignatureAggregator) getSubnetID(blockchainID ids.ID) (ids.ID, error) {
+// Calls OnFinishedHandling for each remaining response on [responseChan] once it is no longer being processed.
+// The channel is closed by the network once every expected response or timeout has been received.
+func drainResponses(responseChan chan message.InboundMessage) {
+	for response := range responseChan {
+		response.OnFinishedHandling()
+	}
+}
+
+// GetProposedHeight returns the P-Chain height against which the given destination blockchain currently verifies
+// Warp messages, for use as the P-Chain height of CreateSignedMessage.
+func (s *SignatureAggregator) GetProposedHeight(ctx context.Context, blockchainID ids.ID) (uint64, error) {
+	pChainHeight, err := s.network.GetProposedHeight(ctx, blockchainID)
+	if err != nil {
+		msg := "Failed to get proposed P-Chain height"
+		s.logger.Warn(
+			msg,
+			zap.String("blockchainID", blockchainID.String()),
+			zap.Error(err),
+		)
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	return pChainHeight, nil
+}
+
+// ValidatorReliability returns the observed reliability of each validator node that has been sent a
+// signature request.
+func (s *SignatureAggregator) ValidatorReliability() []NodeReliability {
+	return s.validatorStats.snapshot()
+}
+
+func (s *SignatureAggregator) getSubnetID(ctx context.Context, blockchainID ids.ID) (ids.ID, error) {
 	s.subnetsMapLock.RLock()
 	subnetID, ok := s.subnetIDsByBlockchainID[blockchainID]
 	s.subnetsMapLock.RUnlock()
@@ -346,7 +593,7 @@ func (s *SignatureAggregator) getSubnetID(blockchainID ids.ID) (ids.ID, error) {
 		return subnetID, nil
 	}
 	s.logger.Info("Signing subnet not found, requesting from PChain", zap.String("blockchainID", blockchainID.String()))
-	subnetID, err := s.network.GetSubnetID(blockchainID)
+	subnetID, err := s.network.GetSubnetID(ctx, blockchainID)
 	if err != nil {
 		return ids.ID{}, fmt.Errorf("source blockchain not found for chain ID %s", blockchainID)
 	}
@@ -368,12 +615,14 @@ func (s *SignatureAggregator) setSubnetID(blockchainID ids.ID, subnetID ids.ID)
 func (s *SignatureAggregator) handleResponse(
 	response message.InboundMessage,
 	sentTo set.Set[ids.NodeID],
+	sentAt time.Time,
 	requestID uint32,
 	connectedValidators *peers.ConnectedCanonicalValidators,
 	unsignedMessage *avalancheWarp.UnsignedMessage,
 	signatureMap map[int][bls.SignatureLen]byte,
 	accumulatedSignatureWeight *big.Int,
 	quorumPercentage uint64,
+	outcomes map[ids.NodeID]queryOutcome,
 ) (*avalancheWarp.Message, bool, error) {
 	// Regardless of the response's relevance, call it's finished handler once this function returns
 	defer response.OnFinishedHandling()
@@ -397,12 +646,16 @@ func (s *SignatureAggregator) handleResponse(
 	if response.Op() == message.AppErrorOp {
 		s.logger.Debug("Request timed out")
 		s.metrics.ValidatorTimeouts.Inc()
+		s.validatorStats.record(nodeID, outcomeTimeout, time.Since(sentAt))
+		outcomes[nodeID] = outcomeTimeout
 		return nil, true, nil
 	}
 
 	validator, vdrIndex := connectedValidators.GetValidator(nodeID)
-	signature, valid := s.isValidSignatureResponse(unsignedMessage, response, validator.PublicKey)
-	if valid {
+	signature, outcome := s.isValidSignatureResponse(unsignedMessage, response, validator.PublicKey)
+	if outcome == outcomeSuccess {
+		s.validatorStats.record(nodeID, outcomeSuccess, time.Since(sentAt))
+		outcomes[nodeID] = outcomeSuccess
 		s.logger.Debug(
 			"Got valid signature response",
 			zap.String("nodeID", nodeID.String()),
@@ -425,7 +678,11 @@ func (s *SignatureAggregator) handleResponse(
 			zap.String("warpMessageID", unsignedMessage.ID().String()),
 			zap.String("sourceBlockchainID", unsignedMessage.SourceChainID.String()),
 		)
-		s.metrics.InvalidSignatureResponses.Inc()
+		if outcome == outcomeInvalidSignature {
+			s.metrics.InvalidSignatureResponses.Inc()
+		}
+		s.validatorStats.record(nodeID, outcome, time.Since(sentAt))
+		outcomes[nodeID] = outcome
 		return nil, true, nil
 	}
 
@@ -461,6 +718,15 @@ func (s *SignatureAggregator) aggregateIfSufficientWeight(
 		// Not enough signatures, continue processing messages
 		return nil, nil
 	}
+	return s.newSignedMessage(unsignedMessage, signatureMap)
+}
+
+// Creates a Warp message signed by the aggregate of the signatures in [signatureMap],
+// regardless of their combined weight.
+func (s *SignatureAggregator) newSignedMessage(
+	unsignedMessage *avalancheWarp.UnsignedMessage,
+	signatureMap map[int][bls.SignatureLen]byte,
+) (*avalancheWarp.Message, error) {
 	aggSig, vdrBitSet, err := s.aggregateSignatures(signatureMap)
 	if err != nil {
 		msg := "Failed to aggregate signature."
@@ -494,20 +760,20 @@ func (s *SignatureAggregator) aggregateIfSufficientWeight(
 }
 
 // isValidSignatureResponse tries to generate a signature from the peer.AsyncResponse, then verifies
-// the signature against the node's public key. If we are unable to generate the signature or verify
-// correctly, false will be returned to indicate no valid signature was found in response.
+// the signature against the node's public key. Returns outcomeSuccess along with the signature if it is
+// valid, outcomeEmptySignature if the node has not yet seen the message, and outcomeInvalidSignature otherwise.
 func (s *SignatureAggregator) isValidSignatureResponse(
 	unsignedMessage *avalancheWarp.UnsignedMessage,
 	response message.InboundMessage,
 	pubKey *bls.PublicKey,
-) (blsSignatureBuf, bool) {
+) (blsSignatureBuf, queryOutcome) {
 	// If the handler returned an error response, count the response and continue
 	if response.Op() == message.AppErrorOp {
 		s.logger.Debug(
 			"Relayer async response failed",
 			zap.String("nodeID", response.NodeID().String()),
 		)
-		return blsSignatureBuf{}, false
+		return blsSignatureBuf{}, outcomeInvalidSignature
 	}
 
 	appResponse, ok := response.Message().(*p2p.AppResponse)
@@ -516,7 +782,7 @@ func (s *SignatureAggregator) isValidSignatureResponse(
 			"Relayer async response was not an AppResponse",
 			zap.String("nodeID", response.NodeID().String()),
 		)
-		return blsSignatureBuf{}, false
+		return blsSignatureBuf{}, outcomeInvalidSignature
 	}
 
 	signature, err := s.unmarshalResponse(appResponse.AppBytes)
@@ -534,7 +800,7 @@ func (s *SignatureAggregator) isValidSignatureResponse(
 			"Response contained an empty signature",
 			zap.String("nodeID", response.NodeID().String()),
 		)
-		return blsSignatureBuf{}, false
+		return blsSignatureBuf{}, outcomeEmptySignature
 	}
 
 	if len(signature) != bls.SignatureLen {
@@ -543,7 +809,7 @@ func (s *SignatureAggregator) isValidSignatureResponse(
 			zap.Int("actual", len(signature)),
 			zap.Int("expected", bls.SignatureLen),
 		)
-		return blsSignatureBuf{}, false
+		return blsSignatureBuf{}, outcomeInvalidSignature
 	}
 
 	sig, err := bls.SignatureFromBytes(signature[:])
@@ -551,7 +817,7 @@ func (s *SignatureAggregator) isValidSignatureResponse(
 		s.logger.Debug(
 			"Failed to create signature from response",
 		)
-		return blsSignatureBuf{}, false
+		return blsSignatureBuf{}, outcomeInvalidSignature
 	}
 
 	if !bls.Verify(pubKey, sig, unsignedMessage.Bytes()) {
@@ -559,10 +825,10 @@ func (s *SignatureAggregator) isValidSignatureResponse(
 			"Failed verification for signature",
 			zap.String("pubKey", hex.EncodeToString(bls.PublicKeyToUncompressedBytes(pubKey))),
 		)
-		return blsSignatureBuf{}, false
+		return blsSignatureBuf{}, outcomeInvalidSignature
 	}
 
-	return signature, true
+	return signature, outcomeSuccess
 }
 
 // aggregateSignatures constructs a BLS aggregate signature from the collected validator signatures. Also
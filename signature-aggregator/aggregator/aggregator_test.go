@@ -3,6 +3,7 @@ package aggregator
 import (
 	"bytes"
 	"context"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	basecfg "github.com/ava-labs/awm-relayer/config"
 	"github.com/ava-labs/awm-relayer/peers"
 	"github.com/ava-labs/awm-relayer/peers/mocks"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
@@ -69,6 +71,8 @@ func instantiateAggregator(t *testing.T) (
 		messageCreator,
 		// Setting the etnaTime to a minute ago so that the post-etna code path is used in the test
 		time.Now().Add(-1*time.Minute),
+		QueryAll,
+		nil,
 	)
 	require.NoError(t, err)
 	return aggregator, mockNetwork
@@ -133,8 +137,8 @@ func TestCreateSignedMessageFailsWithNoValidators(t *testing.T) {
 	aggregator, mockNetwork := instantiateAggregator(t)
 	msg, err := warp.NewUnsignedMessage(0, ids.Empty, []byte{})
 	require.NoError(t, err)
-	mockNetwork.EXPECT().GetSubnetID(ids.Empty).Return(ids.Empty, nil)
-	mockNetwork.EXPECT().ConnectToCanonicalValidators(ids.Empty).Return(
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), ids.Empty).Return(ids.Empty, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), ids.Empty, uint64(0)).Return(
 		&peers.ConnectedCanonicalValidators{
 			ConnectedWeight:      0,
 			TotalValidatorWeight: 0,
@@ -142,7 +146,7 @@ func TestCreateSignedMessageFailsWithNoValidators(t *testing.T) {
 		},
 		nil,
 	)
-	_, err = aggregator.CreateSignedMessage(msg, nil, ids.Empty, 80)
+	_, err = aggregator.CreateSignedMessage(context.Background(), msg, nil, ids.Empty, 0, 80, nil)
 	require.ErrorContains(t, err, "no signatures")
 }
 
@@ -150,8 +154,8 @@ func TestCreateSignedMessageFailsWithoutSufficientConnectedStake(t *testing.T) {
 	aggregator, mockNetwork := instantiateAggregator(t)
 	msg, err := warp.NewUnsignedMessage(0, ids.Empty, []byte{})
 	require.NoError(t, err)
-	mockNetwork.EXPECT().GetSubnetID(ids.Empty).Return(ids.Empty, nil)
-	mockNetwork.EXPECT().ConnectToCanonicalValidators(ids.Empty).Return(
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), ids.Empty).Return(ids.Empty, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), ids.Empty, uint64(0)).Return(
 		&peers.ConnectedCanonicalValidators{
 			ConnectedWeight:      0,
 			TotalValidatorWeight: 1,
@@ -159,12 +163,19 @@ func TestCreateSignedMessageFailsWithoutSufficientConnectedStake(t *testing.T) {
 		},
 		nil,
 	)
-	_, err = aggregator.CreateSignedMessage(msg, nil, ids.Empty, 80)
+	_, err = aggregator.CreateSignedMessage(context.Background(), msg, nil, ids.Empty, 0, 80, nil)
 	require.ErrorContains(
 		t,
 		err,
 		"failed to connect to a threshold of stake",
 	)
+
+	var aggErr *AggregationError
+	require.ErrorAs(t, err, &aggErr)
+	require.ErrorIs(t, err, errNotEnoughConnectedStake)
+	require.Zero(t, aggErr.ConnectedWeight)
+	require.Equal(t, uint64(1), aggErr.TotalWeight)
+	require.Equal(t, uint64(1), aggErr.RequiredWeight)
 }
 
 func makeAppRequests(
@@ -197,6 +208,10 @@ func TestCreateSignedMessageRetriesAndFailsWithoutP2PResponses(t *testing.T) {
 	var (
 		connectedValidators, _ = makeConnectedValidators(2)
 		requestID              = aggregator.currentRequestID.Load() + 1
+		retryPolicy            = &basecfg.RetryPolicy{
+			MaxAttempts:    3,
+			InitialDelayMs: 1,
+		}
 	)
 
 	chainID := ids.GenerateTestID()
@@ -205,12 +220,12 @@ func TestCreateSignedMessageRetriesAndFailsWithoutP2PResponses(t *testing.T) {
 	require.NoError(t, err)
 
 	subnetID := ids.GenerateTestID()
-	mockNetwork.EXPECT().GetSubnetID(chainID).Return(
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(
 		subnetID,
 		nil,
 	)
 
-	mockNetwork.EXPECT().ConnectToCanonicalValidators(subnetID).Return(
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(
 		connectedValidators,
 		nil,
 	)
@@ -218,7 +233,7 @@ func TestCreateSignedMessageRetriesAndFailsWithoutP2PResponses(t *testing.T) {
 	appRequests := makeAppRequests(chainID, requestID, connectedValidators)
 	for _, appRequest := range appRequests {
 		mockNetwork.EXPECT().RegisterAppRequest(appRequest).Times(
-			maxRelayerQueryAttempts,
+			int(retryPolicy.MaxAttempts),
 		)
 	}
 
@@ -227,7 +242,7 @@ func TestCreateSignedMessageRetriesAndFailsWithoutP2PResponses(t *testing.T) {
 		len(appRequests),
 	).Return(
 		make(chan message.InboundMessage, len(appRequests)),
-	).Times(maxRelayerQueryAttempts)
+	).Times(int(retryPolicy.MaxAttempts))
 
 	var nodeIDs set.Set[ids.NodeID]
 	for _, appRequest := range appRequests {
@@ -238,14 +253,133 @@ func TestCreateSignedMessageRetriesAndFailsWithoutP2PResponses(t *testing.T) {
 		nodeIDs,
 		subnetID,
 		subnets.NoOpAllower,
-	).Times(maxRelayerQueryAttempts)
+	).Times(int(retryPolicy.MaxAttempts))
 
-	_, err = aggregator.CreateSignedMessage(msg, nil, subnetID, 80)
+	_, err = aggregator.CreateSignedMessage(context.Background(), msg, nil, subnetID, 0, 80, retryPolicy)
 	require.ErrorContains(
 		t,
 		err,
 		"failed to collect a threshold of signatures",
 	)
+
+	// None of the requests were sent, so every node is reported as unconnected
+	var aggErr *AggregationError
+	require.ErrorAs(t, err, &aggErr)
+	require.ErrorIs(t, err, errNotEnoughSignatures)
+	require.Zero(t, aggErr.SignedWeight)
+	require.Equal(t, connectedValidators.TotalValidatorWeight, aggErr.TotalWeight)
+	require.Equal(t, uint64(80), aggErr.QuorumPercentage)
+	require.Empty(t, aggErr.TimedOutNodes)
+	require.Empty(t, aggErr.InvalidSignatureNodes)
+	require.ElementsMatch(t, nodeIDs.List(), aggErr.UnconnectedNodes)
+}
+
+func TestCreateSignedMessageClampsRetryPolicy(t *testing.T) {
+	aggregator, mockNetwork := instantiateAggregator(t)
+	aggregator.retryPolicyLimit = &basecfg.RetryPolicy{
+		MaxAttempts: 2,
+		MaxDelayMs:  1,
+	}
+
+	var (
+		connectedValidators, _ = makeConnectedValidators(2)
+		requestID              = aggregator.currentRequestID.Load() + 1
+		expectedAttempts       = int(aggregator.retryPolicyLimit.MaxAttempts)
+	)
+
+	chainID := ids.GenerateTestID()
+
+	msg, err := warp.NewUnsignedMessage(0, chainID, []byte{})
+	require.NoError(t, err)
+
+	subnetID := ids.GenerateTestID()
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(
+		subnetID,
+		nil,
+	)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(
+		connectedValidators,
+		nil,
+	)
+
+	appRequests := makeAppRequests(chainID, requestID, connectedValidators)
+	for _, appRequest := range appRequests {
+		mockNetwork.EXPECT().RegisterAppRequest(appRequest).Times(expectedAttempts)
+	}
+	mockNetwork.EXPECT().RegisterRequestID(
+		requestID,
+		len(appRequests),
+	).Return(
+		make(chan message.InboundMessage, len(appRequests)),
+	).Times(expectedAttempts)
+	mockNetwork.EXPECT().Send(
+		gomock.Any(),
+		gomock.Any(),
+		subnetID,
+		subnets.NoOpAllower,
+	).Times(expectedAttempts)
+
+	// The requested policy makes more attempts, with longer delays, than the limit allows
+	retryPolicy := &basecfg.RetryPolicy{
+		MaxAttempts:    basecfg.MaxRetryMaxAttempts,
+		InitialDelayMs: basecfg.MaxRetryDelayMs,
+	}
+	_, err = aggregator.CreateSignedMessage(context.Background(), msg, nil, subnetID, 0, 80, retryPolicy)
+	require.ErrorIs(t, err, errNotEnoughSignatures)
+}
+
+func TestCreateSignedMessageStopsWhenContextCancelled(t *testing.T) {
+	aggregator, mockNetwork := instantiateAggregator(t)
+
+	var (
+		connectedValidators, _ = makeConnectedValidators(2)
+		requestID              = aggregator.currentRequestID.Load() + 1
+	)
+
+	chainID := ids.GenerateTestID()
+
+	msg, err := warp.NewUnsignedMessage(0, chainID, []byte{})
+	require.NoError(t, err)
+
+	subnetID := ids.GenerateTestID()
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(
+		subnetID,
+		nil,
+	)
+
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(
+		connectedValidators,
+		nil,
+	)
+
+	appRequests := makeAppRequests(chainID, requestID, connectedValidators)
+	for _, appRequest := range appRequests {
+		mockNetwork.EXPECT().RegisterAppRequest(appRequest).Times(1)
+	}
+
+	// No responses are ever delivered, so the aggregator blocks until the context expires
+	responseChan := make(chan message.InboundMessage, len(appRequests))
+	defer close(responseChan)
+	mockNetwork.EXPECT().RegisterRequestID(
+		requestID,
+		len(appRequests),
+	).Return(responseChan).Times(1)
+
+	var nodeIDs set.Set[ids.NodeID]
+	for _, appRequest := range appRequests {
+		nodeIDs.Add(appRequest.NodeID)
+	}
+	mockNetwork.EXPECT().Send(
+		gomock.Any(),
+		nodeIDs,
+		subnetID,
+		subnets.NoOpAllower,
+	).Return(nodeIDs).Times(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = aggregator.CreateSignedMessage(ctx, msg, nil, subnetID, 0, 80, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
 }
 
 func TestCreateSignedMessageSucceeds(t *testing.T) {
@@ -267,12 +401,12 @@ func TestCreateSignedMessageSucceeds(t *testing.T) {
 	aggregator, mockNetwork := instantiateAggregator(t)
 
 	subnetID := ids.GenerateTestID()
-	mockNetwork.EXPECT().GetSubnetID(chainID).Return(
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(
 		subnetID,
 		nil,
 	)
 
-	mockNetwork.EXPECT().ConnectToCanonicalValidators(subnetID).Return(
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(
 		connectedValidators,
 		nil,
 	)
@@ -323,14 +457,32 @@ func TestCreateSignedMessageSucceeds(t *testing.T) {
 
 	// aggregate the signatures:
 	var quorumPercentage uint64 = 80
+	var progress []Progress
+	ctx := WithProgress(context.Background(), func(p Progress) {
+		progress = append(progress, p)
+	})
 	signedMessage, err := aggregator.CreateSignedMessage(
+		ctx,
 		msg,
 		nil,
 		subnetID,
+		0,
 		quorumPercentage,
+		nil,
 	)
 	require.NoError(t, err)
 
+	// verify the reported progress:
+	require.NotEmpty(t, progress)
+	require.Equal(t, Progress{TotalWeight: connectedValidators.TotalValidatorWeight}, progress[0])
+	finalProgress := progress[len(progress)-1]
+	require.Equal(t, uint64(1), finalProgress.Attempt)
+	require.GreaterOrEqual(
+		t,
+		finalProgress.SignedWeight*100,
+		finalProgress.TotalWeight*quorumPercentage,
+	)
+
 	// verify the aggregated signature:
 	pChainState := newPChainStateStub(
 		chainID,
@@ -350,6 +502,394 @@ func TestCreateSignedMessageSucceeds(t *testing.T) {
 	require.NoError(t, verifyErr)
 }
 
+func TestCreateSignedMessageReturnsPartialSignatureWithoutQuorum(t *testing.T) {
+	chainID := ids.GenerateTestID()
+	networkID := constants.UnitTestID
+	msg, err := warp.NewUnsignedMessage(networkID, chainID, utils.RandomBytes(1234))
+	require.NoError(t, err)
+
+	connectedValidators, validatorSecretKeys := makeConnectedValidators(5)
+	aggregator, mockNetwork := instantiateAggregator(t)
+
+	subnetID := ids.GenerateTestID()
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(subnetID, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(connectedValidators, nil)
+
+	requestID := aggregator.currentRequestID.Load() + 1
+	appRequests := makeAppRequests(chainID, requestID, connectedValidators)
+	for _, appRequest := range appRequests {
+		mockNetwork.EXPECT().RegisterAppRequest(appRequest).Times(1)
+	}
+
+	// Two of the five validators sign the message, and the remainder time out
+	const signerCount = 2
+	var nodeIDs set.Set[ids.NodeID]
+	responseChan := make(chan message.InboundMessage, len(appRequests))
+	for i, appRequest := range appRequests {
+		nodeIDs.Add(appRequest.NodeID)
+		if i >= signerCount {
+			responseChan <- message.InboundAppError(appRequest.NodeID, chainID, requestID, 0, "timed out")
+			continue
+		}
+		validatorSecretKey := validatorSecretKeys[connectedValidators.NodeValidatorIndexMap[appRequest.NodeID]]
+		responseBytes, err := proto.Marshal(
+			&sdk.SignatureResponse{
+				Signature: bls.SignatureToBytes(bls.Sign(validatorSecretKey, msg.Bytes())),
+			},
+		)
+		require.NoError(t, err)
+		responseChan <- message.InboundAppResponse(chainID, requestID, responseBytes, appRequest.NodeID)
+	}
+	mockNetwork.EXPECT().RegisterRequestID(requestID, len(appRequests)).Return(responseChan).Times(1)
+	mockNetwork.EXPECT().Send(gomock.Any(), nodeIDs, subnetID, subnets.NoOpAllower).Times(1).Return(nodeIDs)
+
+	_, err = aggregator.CreateSignedMessage(
+		context.Background(),
+		msg,
+		nil,
+		subnetID,
+		0,
+		80,
+		&basecfg.RetryPolicy{MaxAttempts: 1},
+	)
+	var aggErr *AggregationError
+	require.ErrorAs(t, err, &aggErr)
+	require.ErrorIs(t, err, errNotEnoughSignatures)
+	require.Equal(t, uint64(signerCount), aggErr.SignedWeight)
+	require.Len(t, aggErr.TimedOutNodes, len(appRequests)-signerCount)
+	require.NotNil(t, aggErr.PartialMessage)
+
+	// The partial signature is valid for a quorum matching the signed weight
+	pChainState := newPChainStateStub(chainID, subnetID, 1, connectedValidators)
+	require.NoError(t, aggErr.PartialMessage.Signature.Verify(
+		context.Background(),
+		msg,
+		networkID,
+		pChainState,
+		pChainState.currentHeight,
+		signerCount,
+		uint64(len(appRequests)),
+	))
+}
+
+func TestCreateSignedMessageBestEffortWithoutConnectedQuorum(t *testing.T) {
+	chainID := ids.GenerateTestID()
+	networkID := constants.UnitTestID
+	msg, err := warp.NewUnsignedMessage(networkID, chainID, utils.RandomBytes(1234))
+	require.NoError(t, err)
+
+	// Only two of the five validators are connected, which is short of the quorum
+	const connectedCount = 2
+	connectedValidators, validatorSecretKeys := makeConnectedValidators(5)
+	connectedValidators.ConnectedWeight = connectedCount
+	connectedValidators.ConnectedNodes = set.NewSet[ids.NodeID](connectedCount)
+	for _, validator := range connectedValidators.ValidatorSet[:connectedCount] {
+		connectedValidators.ConnectedNodes.Add(validator.NodeIDs[0])
+	}
+	aggregator, mockNetwork := instantiateAggregator(t)
+
+	subnetID := ids.GenerateTestID()
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(subnetID, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(
+		connectedValidators,
+		nil,
+	).Times(2)
+
+	// Without best-effort, the aggregation fails before querying any validators
+	_, err = aggregator.CreateSignedMessage(context.Background(), msg, nil, subnetID, 0, 80, nil)
+	require.ErrorIs(t, err, errNotEnoughConnectedStake)
+
+	// With best-effort, the connected validators are queried and sign the message
+	mockNetwork.EXPECT().RegisterAppRequest(gomock.Any()).AnyTimes()
+	mockNetwork.EXPECT().RegisterRequestID(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(requestID uint32, _ int) chan message.InboundMessage {
+			responseChan := make(chan message.InboundMessage, connectedCount)
+			for nodeID := range connectedValidators.ConnectedNodes {
+				secretKey := validatorSecretKeys[connectedValidators.NodeValidatorIndexMap[nodeID]]
+				responseBytes, err := proto.Marshal(
+					&sdk.SignatureResponse{
+						Signature: bls.SignatureToBytes(bls.Sign(secretKey, msg.Bytes())),
+					},
+				)
+				require.NoError(t, err)
+				responseChan <- message.InboundAppResponse(chainID, requestID, responseBytes, nodeID)
+			}
+			return responseChan
+		},
+	)
+	mockNetwork.EXPECT().Send(gomock.Any(), gomock.Any(), subnetID, subnets.NoOpAllower).DoAndReturn(
+		func(_ message.OutboundMessage, nodeIDs set.Set[ids.NodeID], _ ids.ID, _ subnets.Allower) set.Set[ids.NodeID] {
+			sentTo := set.NewSet[ids.NodeID](connectedCount)
+			for nodeID := range nodeIDs {
+				if connectedValidators.ConnectedNodes.Contains(nodeID) {
+					sentTo.Add(nodeID)
+				}
+			}
+			return sentTo
+		},
+	)
+	_, err = aggregator.CreateSignedMessage(
+		WithBestEffort(context.Background()),
+		msg,
+		nil,
+		subnetID,
+		0,
+		80,
+		&basecfg.RetryPolicy{MaxAttempts: 1},
+	)
+	var aggErr *AggregationError
+	require.ErrorAs(t, err, &aggErr)
+	require.ErrorIs(t, err, errNotEnoughSignatures)
+	require.Equal(t, uint64(connectedCount), aggErr.SignedWeight)
+	require.NotNil(t, aggErr.PartialMessage)
+
+	pChainState := newPChainStateStub(chainID, subnetID, 1, connectedValidators)
+	require.NoError(t, aggErr.PartialMessage.Signature.Verify(
+		context.Background(),
+		msg,
+		networkID,
+		pChainState,
+		pChainState.currentHeight,
+		connectedCount,
+		uint64(len(connectedValidators.ValidatorSet)),
+	))
+}
+
+func TestQuorumWeight(t *testing.T) {
+	require.Equal(t, uint64(67), quorumWeight(100, 67))
+	require.Equal(t, uint64(68), quorumWeight(101, 67))
+	require.Equal(t, uint64(0), quorumWeight(0, 67))
+}
+
+func TestCreateSignedMessagesConnectsOncePerSigningSubnet(t *testing.T) {
+	aggregator, mockNetwork := instantiateAggregator(t)
+
+	unknownChainID := ids.GenerateTestID()
+	chainID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID()
+	var requests []SignatureRequest
+	for _, sourceChainID := range []ids.ID{unknownChainID, chainID, chainID} {
+		msg, err := warp.NewUnsignedMessage(0, sourceChainID, utils.RandomBytes(32))
+		require.NoError(t, err)
+		requests = append(requests, SignatureRequest{UnsignedMessage: msg})
+	}
+
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), unknownChainID).Return(ids.Empty, errors.New("unknown chain"))
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(subnetID, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(
+		&peers.ConnectedCanonicalValidators{
+			ConnectedWeight:      0,
+			TotalValidatorWeight: 0,
+			ValidatorSet:         []*warp.Validator{},
+		},
+		nil,
+	).Times(1)
+
+	results := aggregator.CreateSignedMessages(context.Background(), requests, ids.Empty, 0, 80, nil)
+	require.Len(t, results, len(requests))
+	require.ErrorContains(t, results[0].Err, "subnet not found")
+	for _, result := range results[1:] {
+		require.Nil(t, result.SignedMessage)
+		require.ErrorContains(t, result.Err, "no signatures")
+	}
+}
+
+func TestCreateSignedMessageCoalescesConcurrentRequests(t *testing.T) {
+	chainID := ids.GenerateTestID()
+	networkID := constants.UnitTestID
+	msg, err := warp.NewUnsignedMessage(networkID, chainID, utils.RandomBytes(1234))
+	require.NoError(t, err)
+
+	connectedValidators, validatorSecretKeys := makeConnectedValidators(5)
+	aggregator, mockNetwork := instantiateAggregator(t)
+
+	subnetID := ids.GenerateTestID()
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(subnetID, nil)
+
+	// Block the aggregation until every caller is waiting on it
+	connecting := make(chan struct{})
+	release := make(chan struct{})
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).DoAndReturn(
+		func(context.Context, ids.ID, uint64) (*peers.ConnectedCanonicalValidators, error) {
+			close(connecting)
+			<-release
+			return connectedValidators, nil
+		},
+	).Times(1)
+
+	requestID := aggregator.currentRequestID.Load() + 1
+	appRequests := makeAppRequests(chainID, requestID, connectedValidators)
+	for _, appRequest := range appRequests {
+		mockNetwork.EXPECT().RegisterAppRequest(appRequest).Times(1)
+	}
+	var nodeIDs set.Set[ids.NodeID]
+	responseChan := make(chan message.InboundMessage, len(appRequests))
+	for _, appRequest := range appRequests {
+		nodeIDs.Add(appRequest.NodeID)
+		validatorSecretKey := validatorSecretKeys[connectedValidators.NodeValidatorIndexMap[appRequest.NodeID]]
+		responseBytes, err := proto.Marshal(
+			&sdk.SignatureResponse{
+				Signature: bls.SignatureToBytes(bls.Sign(validatorSecretKey, msg.Bytes())),
+			},
+		)
+		require.NoError(t, err)
+		responseChan <- message.InboundAppResponse(chainID, requestID, responseBytes, appRequest.NodeID)
+	}
+	close(responseChan)
+	mockNetwork.EXPECT().RegisterRequestID(requestID, len(appRequests)).Return(responseChan).Times(1)
+	mockNetwork.EXPECT().Send(gomock.Any(), nodeIDs, subnetID, subnets.NoOpAllower).Times(1).Return(nodeIDs)
+
+	type result struct {
+		signedMessage *warp.Message
+		err           error
+	}
+	createSignedMessage := func(ctx context.Context) <-chan result {
+		resultChan := make(chan result, 1)
+		go func() {
+			signedMessage, err := aggregator.CreateSignedMessage(ctx, msg, nil, subnetID, 0, 80, nil)
+			resultChan <- result{signedMessage, err}
+		}()
+		return resultChan
+	}
+	waitForCallers := func(callers int) {
+		require.Eventually(t, func() bool {
+			aggregator.flightsLock.Lock()
+			defer aggregator.flightsLock.Unlock()
+			for _, f := range aggregator.flights {
+				return f.callers == callers
+			}
+			return false
+		}, time.Second, time.Millisecond)
+	}
+
+	first := createSignedMessage(context.Background())
+	<-connecting
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancelled := createSignedMessage(cancelledCtx)
+	second := createSignedMessage(context.Background())
+	waitForCallers(3)
+
+	// A caller that stops waiting does not cancel the aggregation for the others
+	cancel()
+	require.ErrorIs(t, (<-cancelled).err, context.Canceled)
+	waitForCallers(2)
+	close(release)
+
+	firstResult := <-first
+	secondResult := <-second
+	require.NoError(t, firstResult.err)
+	require.NoError(t, secondResult.err)
+	require.Equal(t, firstResult.signedMessage.Bytes(), secondResult.signedMessage.Bytes())
+
+	// Once complete, the aggregation is no longer shared
+	aggregator.flightsLock.Lock()
+	defer aggregator.flightsLock.Unlock()
+	require.Empty(t, aggregator.flights)
+}
+
+func TestCreateSignedMessageWithSignaturesSkipsNetworkWithSufficientSignatures(t *testing.T) {
+	chainID := ids.GenerateTestID()
+	networkID := constants.UnitTestID
+	msg, err := warp.NewUnsignedMessage(networkID, chainID, utils.RandomBytes(1234))
+	require.NoError(t, err)
+
+	connectedValidators, validatorSecretKeys := makeConnectedValidators(5)
+	// None of the validators are connected, but their signatures are provided
+	connectedValidators.ConnectedWeight = 0
+	aggregator, mockNetwork := instantiateAggregator(t)
+
+	subnetID := ids.GenerateTestID()
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(subnetID, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(connectedValidators, nil)
+
+	var signatures []ValidatorSignature
+	for i, validator := range connectedValidators.ValidatorSet[:4] {
+		signatures = append(signatures, ValidatorSignature{
+			NodeID:    validator.NodeIDs[0],
+			Signature: bls.SignatureToBytes(bls.Sign(validatorSecretKeys[i], msg.Bytes())),
+		})
+	}
+
+	// No AppRequests are expected, since the provided signatures reach the quorum
+	quorumPercentage := uint64(80)
+	signedMessage, err := aggregator.CreateSignedMessageWithSignatures(
+		context.Background(),
+		msg,
+		nil,
+		signatures,
+		subnetID,
+		0,
+		quorumPercentage,
+		nil,
+	)
+	require.NoError(t, err)
+
+	pChainState := newPChainStateStub(chainID, subnetID, 1, connectedValidators)
+	require.NoError(t, signedMessage.Signature.Verify(
+		context.Background(),
+		msg,
+		networkID,
+		pChainState,
+		pChainState.currentHeight,
+		quorumPercentage,
+		100,
+	))
+
+	// The provided signatures are cached for subsequent requests
+	cachedSignatures, ok := aggregator.cache.Get(msg.ID())
+	require.True(t, ok)
+	require.Len(t, cachedSignatures, len(signatures))
+}
+
+func TestCreateSignedMessageWithSignaturesRejectsInvalidSignatures(t *testing.T) {
+	chainID := ids.GenerateTestID()
+	msg, err := warp.NewUnsignedMessage(constants.UnitTestID, chainID, utils.RandomBytes(1234))
+	require.NoError(t, err)
+
+	connectedValidators, validatorSecretKeys := makeConnectedValidators(2)
+	aggregator, mockNetwork := instantiateAggregator(t)
+
+	subnetID := ids.GenerateTestID()
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(subnetID, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(
+		connectedValidators,
+		nil,
+	).Times(2)
+
+	validSignature := ValidatorSignature{
+		NodeID:    connectedValidators.ValidatorSet[0].NodeIDs[0],
+		Signature: bls.SignatureToBytes(bls.Sign(validatorSecretKeys[0], msg.Bytes())),
+	}
+	for _, invalidSignature := range []ValidatorSignature{
+		// Signed by a different validator's key
+		{
+			NodeID:    connectedValidators.ValidatorSet[1].NodeIDs[0],
+			Signature: validSignature.Signature,
+		},
+		// Not a validator of the signing subnet
+		{
+			NodeID:    ids.GenerateTestNodeID(),
+			Signature: validSignature.Signature,
+		},
+	} {
+		_, err = aggregator.CreateSignedMessageWithSignatures(
+			context.Background(),
+			msg,
+			nil,
+			[]ValidatorSignature{validSignature, invalidSignature},
+			subnetID,
+			0,
+			67,
+			nil,
+		)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	}
+
+	// None of the signatures are cached
+	_, ok := aggregator.cache.Get(msg.ID())
+	require.False(t, ok)
+}
+
 type pChainStateStub struct {
 	subnetIDByChainID            map[ids.ID]ids.ID
 	connectedCanonicalValidators *peers.ConnectedCanonicalValidators
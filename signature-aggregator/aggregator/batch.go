@@ -0,0 +1,126 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	basecfg "github.com/ava-labs/awm-relayer/config"
+	"github.com/ava-labs/awm-relayer/peers"
+	"go.uber.org/zap"
+)
+
+// A single message to be signed as part of a batch
+type SignatureRequest struct {
+	UnsignedMessage *avalancheWarp.UnsignedMessage
+	Justification   []byte
+}
+
+// The outcome of a single message in a batch. Exactly one of SignedMessage and Err is set.
+type SignatureResult struct {
+	SignedMessage *avalancheWarp.Message
+	Err           error
+}
+
+// CreateSignedMessages collects signatures for each of the requested messages, with the same semantics as
+// CreateSignedMessage. The canonical validator set of each signing subnet is fetched and connected to once
+// for the entire batch, after which signatures for all messages are collected concurrently, sharing any
+// aggregations already in progress for the same messages. Returns one result per request, in the same order
+// as [requests].
+func (s *SignatureAggregator) CreateSignedMessages(
+	ctx context.Context,
+	requests []SignatureRequest,
+	inputSigningSubnet ids.ID,
+	pChainHeight uint64,
+	quorumPercentage uint64,
+	retryPolicy *basecfg.RetryPolicy,
+) []SignatureResult {
+	policy := s.clampRetryPolicy(retryPolicy)
+	results := make([]SignatureResult, len(requests))
+
+	// Resolve the signing subnet of each message
+	sourceSubnets := make([]ids.ID, len(requests))
+	signingSubnets := make([]ids.ID, len(requests))
+	for i, request := range requests {
+		sourceSubnet, signingSubnet, err := s.getSigningSubnet(
+			ctx,
+			request.UnsignedMessage.SourceChainID,
+			inputSigningSubnet,
+		)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		sourceSubnets[i] = sourceSubnet
+		signingSubnets[i] = signingSubnet
+	}
+
+	// Connect to the validators of each signing subnet once
+	type connection struct {
+		validators *peers.ConnectedCanonicalValidators
+		err        error
+	}
+	connections := make(map[ids.ID]connection)
+	for i, signingSubnet := range signingSubnets {
+		if results[i].Err != nil {
+			continue
+		}
+		if _, ok := connections[signingSubnet]; !ok {
+			validators, err := s.connectToQuorum(ctx, signingSubnet, pChainHeight, quorumPercentage)
+			connections[signingSubnet] = connection{
+				validators: validators,
+				err:        err,
+			}
+		}
+		if err := connections[signingSubnet].err; err != nil {
+			results[i].Err = err
+		}
+	}
+
+	s.logger.Debug(
+		"Aggregator collecting signatures for batch",
+		zap.Int("batchSize", len(requests)),
+		zap.Int("signingSubnetCount", len(connections)),
+	)
+
+	// Send the AppRequests for all messages together, and collect the signatures concurrently
+	var wg sync.WaitGroup
+	for i, request := range requests {
+		if results[i].Err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := flightKey{
+				messageID:        request.UnsignedMessage.ID(),
+				signingSubnet:    signingSubnets[i],
+				pChainHeight:     pChainHeight,
+				quorumPercentage: quorumPercentage,
+			}
+			signedMessage, err := s.coalesce(ctx, key, func(ctx context.Context) (*avalancheWarp.Message, error) {
+				return s.collectSignatures(
+					ctx,
+					request.UnsignedMessage,
+					request.Justification,
+					sourceSubnets[i],
+					signingSubnets[i],
+					connections[signingSubnets[i]].validators,
+					quorumPercentage,
+					policy,
+				)
+			})
+			results[i] = SignatureResult{
+				SignedMessage: signedMessage,
+				Err:           err,
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
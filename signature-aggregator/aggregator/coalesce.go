@@ -0,0 +1,150 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"go.uber.org/zap"
+)
+
+// Identifies signature aggregations that can be shared between concurrent callers
+type flightKey struct {
+	messageID        ids.ID
+	signingSubnet    ids.ID
+	pChainHeight     uint64
+	quorumPercentage uint64
+	// Best-effort aggregations proceed without a quorum of connected stake, so are not shared with others
+	bestEffort bool
+}
+
+// A signature aggregation that is in progress, shared by every caller requesting the same signatures
+type flight struct {
+	// Closed once the aggregation completes, after which result and err are set
+	done   chan struct{}
+	result *avalancheWarp.Message
+	err    error
+	cancel context.CancelFunc
+	// Number of callers waiting on the aggregation. Protected by SignatureAggregator.flightsLock
+	callers int
+
+	progressLock   sync.Mutex
+	progressFuncs  map[int]ProgressFunc
+	nextProgressID int
+	// The most recently reported progress, delivered to callers that join an aggregation already in progress
+	lastProgress *Progress
+}
+
+func (f *flight) reportProgress(progress Progress) {
+	f.progressLock.Lock()
+	defer f.progressLock.Unlock()
+	f.lastProgress = &progress
+	for _, progressFunc := range f.progressFuncs {
+		progressFunc(progress)
+	}
+}
+
+// Registers the ProgressFunc of [ctx], if any, to receive the aggregation's progress.
+// Returns the ID to pass to unsubscribe.
+func (f *flight) subscribe(ctx context.Context) (int, bool) {
+	progressFunc, ok := ctx.Value(progressKey{}).(ProgressFunc)
+	if !ok {
+		return 0, false
+	}
+	f.progressLock.Lock()
+	defer f.progressLock.Unlock()
+	id := f.nextProgressID
+	f.nextProgressID++
+	f.progressFuncs[id] = progressFunc
+	if f.lastProgress != nil {
+		progressFunc(*f.lastProgress)
+	}
+	return id, true
+}
+
+func (f *flight) unsubscribe(id int) {
+	f.progressLock.Lock()
+	defer f.progressLock.Unlock()
+	delete(f.progressFuncs, id)
+}
+
+// coalesce runs [aggregate] for [key], unless an aggregation for the same key is already in progress, in
+// which case the caller waits for that aggregation's result instead. The aggregation runs with a context
+// that is cancelled once every caller waiting on it has returned, and its progress is reported to each
+// caller's ProgressFunc.
+func (s *SignatureAggregator) coalesce(
+	ctx context.Context,
+	key flightKey,
+	aggregate func(ctx context.Context) (*avalancheWarp.Message, error),
+) (*avalancheWarp.Message, error) {
+	s.flightsLock.Lock()
+	f, inProgress := s.flights[key]
+	if inProgress {
+		s.logger.Debug(
+			"Joining signature aggregation in progress",
+			zap.String("warpMessageID", key.messageID.String()),
+			zap.String("signingSubnetID", key.signingSubnet.String()),
+		)
+		s.metrics.CoalescedAggregations.Inc()
+	} else {
+		f = &flight{
+			done:          make(chan struct{}),
+			progressFuncs: make(map[int]ProgressFunc),
+		}
+		s.flights[key] = f
+	}
+	f.callers++
+	// Subscribe before the aggregation starts, so that the first caller observes all of its progress
+	if id, ok := f.subscribe(ctx); ok {
+		defer f.unsubscribe(id)
+	}
+	if !inProgress {
+		var flightCtx context.Context
+		flightCtx, f.cancel = context.WithCancel(context.Background())
+		go func() {
+			result, err := aggregate(WithProgress(flightCtx, f.reportProgress))
+			s.removeFlight(key, f)
+			f.result, f.err = result, err
+			f.cancel()
+			close(f.done)
+		}()
+	}
+	s.flightsLock.Unlock()
+
+	select {
+	case <-f.done:
+		return f.result, f.err
+	case <-ctx.Done():
+	}
+
+	// Stop the aggregation if no other callers are waiting on it
+	s.flightsLock.Lock()
+	f.callers--
+	if f.callers == 0 {
+		if s.flights[key] == f {
+			delete(s.flights, key)
+		}
+		f.cancel()
+	}
+	s.flightsLock.Unlock()
+	s.logger.Info(
+		"Signature aggregation cancelled",
+		zap.String("warpMessageID", key.messageID.String()),
+		zap.Error(ctx.Err()),
+	)
+	return nil, fmt.Errorf("signature aggregation cancelled: %w", ctx.Err())
+}
+
+// Removes [f] from the in progress aggregations, unless it has already been replaced
+func (s *SignatureAggregator) removeFlight(key flightKey, f *flight) {
+	s.flightsLock.Lock()
+	defer s.flightsLock.Unlock()
+	if s.flights[key] == f {
+		delete(s.flights, key)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ava-labs/awm-relayer/peers"
+)
+
+// Progress describes the signature weight collected so far for a single message
+type Progress struct {
+	// The current query attempt, starting at 1. Zero before any validators have been queried.
+	Attempt uint64
+	// Combined weight of the validators whose signatures have been collected
+	SignedWeight uint64
+	// Total weight of the signing subnet's canonical validator set
+	TotalWeight uint64
+}
+
+// ProgressFunc is invoked whenever the signature weight collected for a message changes.
+// It is called synchronously from the aggregation loop, so it must not block.
+type ProgressFunc func(Progress)
+
+type (
+	progressKey   struct{}
+	bestEffortKey struct{}
+)
+
+// WithProgress returns a copy of [ctx] that causes CreateSignedMessage to report its progress to [f]
+func WithProgress(ctx context.Context, f ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, f)
+}
+
+// WithBestEffort returns a copy of [ctx] that causes CreateSignedMessage and CreateSignedMessageWithSignatures
+// to query the connected validators even if they hold less than the quorum percentage of the stake weight,
+// so that the message signed by the signatures collected is returned in the AggregationError's PartialMessage.
+func WithBestEffort(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bestEffortKey{}, true)
+}
+
+func isBestEffort(ctx context.Context) bool {
+	bestEffort, _ := ctx.Value(bestEffortKey{}).(bool)
+	return bestEffort
+}
+
+func reportProgress(
+	ctx context.Context,
+	attempt uint64,
+	accumulatedSignatureWeight *big.Int,
+	connectedValidators *peers.ConnectedCanonicalValidators,
+) {
+	f, ok := ctx.Value(progressKey{}).(ProgressFunc)
+	if !ok {
+		return
+	}
+	f(Progress{
+		Attempt:      attempt,
+		SignedWeight: accumulatedSignatureWeight.Uint64(),
+		TotalWeight:  connectedValidators.TotalValidatorWeight,
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/awm-relayer/peers"
+	"github.com/ava-labs/awm-relayer/utils"
+)
+
+// QueryStrategy determines which validators are sent a signature request on each attempt.
+type QueryStrategy string
+
+const (
+	// Query every validator that has not yet provided a signature.
+	QueryAll QueryStrategy = "all"
+	// Query the validators with the highest stake weight, success rate and lowest response latency first,
+	// adding more validators only as needed to reach the required stake weight.
+	QueryWeighted QueryStrategy = "weighted"
+)
+
+// ParseQueryStrategy returns the QueryStrategy corresponding to [s]. Defaults to QueryAll if [s] is empty.
+func ParseQueryStrategy(s string) (QueryStrategy, error) {
+	switch QueryStrategy(s) {
+	case "", QueryAll:
+		return QueryAll, nil
+	case QueryWeighted:
+		return QueryWeighted, nil
+	default:
+		return "", fmt.Errorf("invalid query strategy: %s", s)
+	}
+}
+
+// selectValidators returns the indices of the validators in the canonical validator set to query on the given
+// attempt. Validators that have already provided a signature are never selected, and validators that are
+// temporarily excluded after repeated failures are only selected if the quorum cannot be reached without them.
+// With QueryAll, or on the final attempt, every remaining validator is selected. With QueryWeighted, validators
+// are ranked by stake weight and success rate relative to their expected latency, and selected in order until
+// their expected signed weight is sufficient to reach the quorum.
+func (s *SignatureAggregator) selectValidators(
+	connectedValidators *peers.ConnectedCanonicalValidators,
+	signatureMap map[int][bls.SignatureLen]byte,
+	accumulatedSignatureWeight *big.Int,
+	quorumPercentage uint64,
+	finalAttempt bool,
+) []int {
+	var candidates, excluded []int
+	candidateWeight := new(big.Int).Set(accumulatedSignatureWeight)
+	for i, vdr := range connectedValidators.ValidatorSet {
+		if _, ok := signatureMap[i]; ok {
+			continue
+		}
+		if s.validatorStats.isExcluded(vdr.NodeIDs[0]) {
+			excluded = append(excluded, i)
+			continue
+		}
+		candidates = append(candidates, i)
+		candidateWeight.Add(candidateWeight, new(big.Int).SetUint64(vdr.Weight))
+	}
+	if !utils.CheckStakeWeightPercentageExceedsThreshold(
+		candidateWeight,
+		connectedValidators.TotalValidatorWeight,
+		quorumPercentage,
+	) {
+		candidates = append(candidates, excluded...)
+	}
+	if s.queryStrategy != QueryWeighted || finalAttempt {
+		return candidates
+	}
+
+	scores := make(map[int]float64, len(candidates))
+	for _, i := range candidates {
+		vdr := connectedValidators.ValidatorSet[i]
+		nodeID := vdr.NodeIDs[0]
+		scores[i] = float64(vdr.Weight) * s.validatorStats.successRate(nodeID) /
+			s.validatorStats.expectedLatency(nodeID).Seconds()
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return scores[candidates[a]] > scores[candidates[b]]
+	})
+
+	// The expected weight of a validator's signature is discounted by its success rate, so that
+	// enough validators are queried to tolerate the expected number of failures.
+	expectedWeight, _ := new(big.Float).SetInt(accumulatedSignatureWeight).Float64()
+	requiredWeight := float64(connectedValidators.TotalValidatorWeight) * float64(quorumPercentage) / 100
+	for n, i := range candidates {
+		if expectedWeight >= requiredWeight {
+			return candidates[:n]
+		}
+		vdr := connectedValidators.ValidatorSet[i]
+		expectedWeight += float64(vdr.Weight) * s.validatorStats.successRate(vdr.NodeIDs[0])
+	}
+	return candidates
+}
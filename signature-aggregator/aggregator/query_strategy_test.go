@@ -0,0 +1,205 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/awm-relayer/peers"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestValidatorStats() *validatorStats {
+	return newValidatorStats(metrics.NewSignatureAggregatorMetrics(prometheus.NewRegistry()))
+}
+
+func makeWeightedValidators(weights ...uint64) *peers.ConnectedCanonicalValidators {
+	validators := &peers.ConnectedCanonicalValidators{
+		NodeValidatorIndexMap: make(map[ids.NodeID]int),
+	}
+	for i, weight := range weights {
+		nodeID := ids.GenerateTestNodeID()
+		validators.ValidatorSet = append(validators.ValidatorSet, &warp.Validator{
+			Weight:  weight,
+			NodeIDs: []ids.NodeID{nodeID},
+		})
+		validators.NodeValidatorIndexMap[nodeID] = i
+		validators.TotalValidatorWeight += weight
+		validators.ConnectedWeight += weight
+	}
+	return validators
+}
+
+func TestParseQueryStrategy(t *testing.T) {
+	for input, expected := range map[string]QueryStrategy{
+		"":         QueryAll,
+		"all":      QueryAll,
+		"weighted": QueryWeighted,
+	} {
+		strategy, err := ParseQueryStrategy(input)
+		require.NoError(t, err)
+		require.Equal(t, expected, strategy)
+	}
+	_, err := ParseQueryStrategy("fastest")
+	require.Error(t, err)
+}
+
+func TestSelectValidators(t *testing.T) {
+	validators := makeWeightedValidators(40, 30, 20, 10)
+	signedValidator := map[int][bls.SignatureLen]byte{0: {}}
+
+	testCases := []struct {
+		name              string
+		strategy          QueryStrategy
+		signatureMap      map[int][bls.SignatureLen]byte
+		accumulatedWeight uint64
+		finalAttempt      bool
+		expected          []int
+	}{
+		{
+			name:     "all",
+			strategy: QueryAll,
+			expected: []int{0, 1, 2, 3},
+		},
+		{
+			name:              "all skips signed validators",
+			strategy:          QueryAll,
+			signatureMap:      signedValidator,
+			accumulatedWeight: 40,
+			expected:          []int{1, 2, 3},
+		},
+		{
+			name:     "weighted selects heaviest validators until quorum is expected",
+			strategy: QueryWeighted,
+			expected: []int{0, 1, 2},
+		},
+		{
+			name:              "weighted accounts for accumulated weight",
+			strategy:          QueryWeighted,
+			signatureMap:      signedValidator,
+			accumulatedWeight: 40,
+			expected:          []int{1},
+		},
+		{
+			name:         "weighted selects all validators on final attempt",
+			strategy:     QueryWeighted,
+			finalAttempt: true,
+			expected:     []int{0, 1, 2, 3},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			aggregator := &SignatureAggregator{
+				queryStrategy:  testCase.strategy,
+				validatorStats: newTestValidatorStats(),
+			}
+			selected := aggregator.selectValidators(
+				validators,
+				testCase.signatureMap,
+				new(big.Int).SetUint64(testCase.accumulatedWeight),
+				67,
+				testCase.finalAttempt,
+			)
+			require.Equal(t, testCase.expected, selected)
+		})
+	}
+}
+
+func TestSelectValidatorsPrefersFastReliableValidators(t *testing.T) {
+	validators := makeWeightedValidators(40, 30, 20, 10)
+	aggregator := &SignatureAggregator{
+		queryStrategy:  QueryWeighted,
+		validatorStats: newTestValidatorStats(),
+	}
+
+	// The heaviest validator times out without being excluded, and the lightest responds quickly
+	slowNodeID := validators.ValidatorSet[0].NodeIDs[0]
+	fastNodeID := validators.ValidatorSet[3].NodeIDs[0]
+	for i := 0; i < maxConsecutiveFailures-1; i++ {
+		aggregator.validatorStats.record(slowNodeID, outcomeTimeout, peers.DefaultAppRequestTimeout)
+		aggregator.validatorStats.record(fastNodeID, outcomeSuccess, 10*time.Millisecond)
+	}
+	require.False(t, aggregator.validatorStats.isExcluded(slowNodeID))
+
+	selected := aggregator.selectValidators(
+		validators,
+		nil,
+		big.NewInt(0),
+		50,
+		false,
+	)
+	require.Equal(t, []int{3, 1, 2}, selected)
+}
+
+func TestSelectValidatorsExcludesFailingValidators(t *testing.T) {
+	validators := makeWeightedValidators(40, 30, 20, 10)
+	aggregator := &SignatureAggregator{
+		queryStrategy:  QueryAll,
+		validatorStats: newTestValidatorStats(),
+	}
+	failingNodeID := validators.ValidatorSet[0].NodeIDs[0]
+	for i := 0; i < maxConsecutiveFailures; i++ {
+		aggregator.validatorStats.record(failingNodeID, outcomeInvalidSignature, 10*time.Millisecond)
+	}
+	require.True(t, aggregator.validatorStats.isExcluded(failingNodeID))
+
+	// The remaining validators are sufficient to reach the quorum
+	selected := aggregator.selectValidators(validators, nil, big.NewInt(0), 50, false)
+	require.Equal(t, []int{1, 2, 3}, selected)
+
+	// The excluded validator's weight is needed to reach the quorum
+	selected = aggregator.selectValidators(validators, nil, big.NewInt(0), 67, false)
+	require.Equal(t, []int{1, 2, 3, 0}, selected)
+
+	reliability := aggregator.ValidatorReliability()
+	require.Len(t, reliability, 1)
+	require.Equal(t, failingNodeID, reliability[0].NodeID)
+	require.Equal(t, uint64(maxConsecutiveFailures), reliability[0].Requests)
+	require.Equal(t, uint64(maxConsecutiveFailures), reliability[0].InvalidSignatures)
+	require.Zero(t, reliability[0].SuccessRate)
+	require.False(t, reliability[0].ExcludedUntil.IsZero())
+}
+
+func TestValidatorStatsSuccessResetsConsecutiveFailures(t *testing.T) {
+	stats := newTestValidatorStats()
+	nodeID := ids.GenerateTestNodeID()
+	for i := 0; i < 2*maxConsecutiveFailures; i++ {
+		outcome := outcomeTimeout
+		if i%(maxConsecutiveFailures-1) == 0 {
+			outcome = outcomeSuccess
+		}
+		stats.record(nodeID, outcome, time.Millisecond)
+	}
+	require.False(t, stats.isExcluded(nodeID))
+}
+
+func TestValidatorStatsEmptySignaturesDoNotExclude(t *testing.T) {
+	stats := newTestValidatorStats()
+	nodeID := ids.GenerateTestNodeID()
+	for i := 0; i < 2*maxConsecutiveFailures; i++ {
+		stats.record(nodeID, outcomeEmptySignature, time.Millisecond)
+	}
+	require.False(t, stats.isExcluded(nodeID))
+	require.Less(t, stats.successRate(nodeID), priorSuccessRate)
+
+	// Empty signatures do not break a run of failures either
+	for i := 0; i < maxConsecutiveFailures-1; i++ {
+		stats.record(nodeID, outcomeTimeout, time.Millisecond)
+		stats.record(nodeID, outcomeEmptySignature, time.Millisecond)
+	}
+	stats.record(nodeID, outcomeTimeout, time.Millisecond)
+	require.True(t, stats.isExcluded(nodeID))
+
+	reliability := stats.snapshot()
+	require.Len(t, reliability, 1)
+	require.Equal(t, uint64(3*maxConsecutiveFailures-1), reliability[0].EmptySignatures)
+	require.Zero(t, reliability[0].InvalidSignatures)
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	basecfg "github.com/ava-labs/awm-relayer/config"
+	"github.com/ava-labs/awm-relayer/peers"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator/cache"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidSignature is returned by CreateSignedMessageWithSignatures if a provided signature is not a valid
+// signature of the message by a canonical validator of the signing subnet.
+var ErrInvalidSignature = errors.New("invalid validator signature")
+
+// A signature of a Warp message by a validator, obtained outside of the aggregator, for example from the
+// validator's warp_getMessageSignature RPC.
+type ValidatorSignature struct {
+	// Any of the validator's node IDs
+	NodeID    ids.NodeID
+	Signature []byte
+}
+
+// CreateSignedMessageWithSignatures creates a signed message in the same manner as CreateSignedMessage,
+// starting from the provided [signatures]. Each signature is verified against the signing subnet's canonical
+// validator set and added to the signature cache. If the signatures are sufficient to reach [quorumPercentage],
+// the message is returned without querying the network. Otherwise, only the validators whose signatures are
+// still missing are queried. Returns an error wrapping ErrInvalidSignature if any of the signatures are invalid.
+// As with CreateSignedMessage, the connected stake is not checked if [ctx] was returned by WithBestEffort.
+func (s *SignatureAggregator) CreateSignedMessageWithSignatures(
+	ctx context.Context,
+	unsignedMessage *avalancheWarp.UnsignedMessage,
+	justification []byte,
+	signatures []ValidatorSignature,
+	inputSigningSubnet ids.ID,
+	pChainHeight uint64,
+	quorumPercentage uint64,
+	retryPolicy *basecfg.RetryPolicy,
+) (*avalancheWarp.Message, error) {
+	sourceSubnet, signingSubnet, err := s.getSigningSubnet(ctx, unsignedMessage.SourceChainID, inputSigningSubnet)
+	if err != nil {
+		return nil, err
+	}
+	connectedValidators, err := s.connectToCanonicalValidators(ctx, signingSubnet, pChainHeight)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addSignatures(unsignedMessage, connectedValidators, signatures); err != nil {
+		return nil, err
+	}
+
+	signatureMap, signatureWeight := s.loadCachedSignatures(unsignedMessage, connectedValidators)
+	reportProgress(ctx, 0, signatureWeight, connectedValidators)
+	if signedMsg, err := s.aggregateIfSufficientWeight(
+		unsignedMessage,
+		signatureMap,
+		signatureWeight,
+		connectedValidators,
+		quorumPercentage,
+	); err != nil || signedMsg != nil {
+		return signedMsg, err
+	}
+	bestEffort := isBestEffort(ctx)
+	if !bestEffort {
+		if err := s.checkConnectedStake(connectedValidators, signatureMap, quorumPercentage); err != nil {
+			return nil, err
+		}
+	}
+
+	// If an aggregation for the message is already in progress, it is shared without the provided signatures,
+	// which were cached after it started.
+	key := flightKey{
+		messageID:        unsignedMessage.ID(),
+		signingSubnet:    signingSubnet,
+		pChainHeight:     pChainHeight,
+		quorumPercentage: quorumPercentage,
+		bestEffort:       bestEffort,
+	}
+	return s.coalesce(ctx, key, func(ctx context.Context) (*avalancheWarp.Message, error) {
+		return s.collectSignatures(
+			ctx,
+			unsignedMessage,
+			justification,
+			sourceSubnet,
+			signingSubnet,
+			connectedValidators,
+			quorumPercentage,
+			s.clampRetryPolicy(retryPolicy),
+		)
+	})
+}
+
+// Verifies each of [signatures] against the public key of the canonical validator it is attributed to,
+// and adds them to the signature cache. No signatures are cached if any of them are invalid.
+func (s *SignatureAggregator) addSignatures(
+	unsignedMessage *avalancheWarp.UnsignedMessage,
+	connectedValidators *peers.ConnectedCanonicalValidators,
+	signatures []ValidatorSignature,
+) error {
+	validSignatures := make(map[cache.PublicKeyBytes]cache.SignatureBytes, len(signatures))
+	for _, signature := range signatures {
+		if _, ok := connectedValidators.NodeValidatorIndexMap[signature.NodeID]; !ok {
+			s.logger.Warn(
+				"Provided signature is not from a canonical validator",
+				zap.String("nodeID", signature.NodeID.String()),
+				zap.String("warpMessageID", unsignedMessage.ID().String()),
+			)
+			return fmt.Errorf("%w: node %s is not a canonical validator", ErrInvalidSignature, signature.NodeID)
+		}
+		validator, _ := connectedValidators.GetValidator(signature.NodeID)
+		sig, err := bls.SignatureFromBytes(signature.Signature)
+		if err != nil || !bls.Verify(validator.PublicKey, sig, unsignedMessage.Bytes()) {
+			s.logger.Warn(
+				"Failed verification for provided signature",
+				zap.String("nodeID", signature.NodeID.String()),
+				zap.String("warpMessageID", unsignedMessage.ID().String()),
+			)
+			return fmt.Errorf("%w: signature of node %s", ErrInvalidSignature, signature.NodeID)
+		}
+		validSignatures[cache.PublicKeyBytes(validator.PublicKeyBytes)] = cache.SignatureBytes(signature.Signature)
+	}
+	for pubKey, signature := range validSignatures {
+		s.cache.Add(unsignedMessage.ID(), pubKey, signature)
+	}
+	s.logger.Debug(
+		"Added provided signatures",
+		zap.String("warpMessageID", unsignedMessage.ID().String()),
+		zap.Int("signatureCount", len(validSignatures)),
+	)
+	return nil
+}
@@ -0,0 +1,208 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/awm-relayer/peers"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
+)
+
+const (
+	// Smoothing factor of the exponentially weighted moving average of response latencies
+	latencySmoothingFactor = 0.2
+	// Assumed response latency of validators that have not yet been queried
+	defaultExpectedLatency = peers.DefaultAppRequestTimeout / 4
+	// Assumed success rate of validators that have not yet been queried, and the number of
+	// requests' worth of weight given to it relative to observed outcomes
+	priorSuccessRate = 0.9
+	priorRequests    = 10
+	// Number of consecutive failed requests after which a validator node is excluded from queries
+	maxConsecutiveFailures = 5
+	// Duration for which a misbehaving validator node is excluded from queries
+	exclusionPeriod = 5 * time.Minute
+)
+
+// queryOutcome is the result of a signature request sent to a validator node
+type queryOutcome string
+
+const (
+	outcomeSuccess          queryOutcome = "success"
+	outcomeInvalidSignature queryOutcome = "invalid_signature"
+	// The node has not yet seen the message. Lowers the node's success rate, but is not counted as a
+	// consecutive failure, so that validators lagging slightly behind are not excluded.
+	outcomeEmptySignature queryOutcome = "empty_signature"
+	outcomeTimeout        queryOutcome = "timeout"
+	outcomeSendFailure    queryOutcome = "send_failure"
+)
+
+// NodeReliability is a snapshot of the observed reliability of a validator node
+type NodeReliability struct {
+	NodeID            ids.NodeID
+	Requests          uint64
+	Successes         uint64
+	InvalidSignatures uint64
+	EmptySignatures   uint64
+	Timeouts          uint64
+	SendFailures      uint64
+	SuccessRate       float64
+	// Exponentially weighted moving average of the response latency
+	Latency time.Duration
+	// Zero if the node is not currently excluded from queries
+	ExcludedUntil time.Time
+}
+
+// validatorStats tracks the observed response latency and success rate of validator nodes, and temporarily
+// excludes nodes that repeatedly fail to respond with a valid signature.
+type validatorStats struct {
+	lock    sync.RWMutex
+	nodes   map[ids.NodeID]*nodeStats
+	metrics *metrics.SignatureAggregatorMetrics
+}
+
+type nodeStats struct {
+	requests            uint64
+	successes           uint64
+	invalidSignatures   uint64
+	emptySignatures     uint64
+	timeouts            uint64
+	sendFailures        uint64
+	consecutiveFailures uint64
+	// exponentially weighted moving average of the response latency
+	latency       time.Duration
+	excludedUntil time.Time
+}
+
+func newValidatorStats(metrics *metrics.SignatureAggregatorMetrics) *validatorStats {
+	return &validatorStats{
+		nodes:   make(map[ids.NodeID]*nodeStats),
+		metrics: metrics,
+	}
+}
+
+// record updates the statistics of [nodeID] with the outcome and latency of a signature request
+func (v *validatorStats) record(nodeID ids.NodeID, outcome queryOutcome, latency time.Duration) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	stats, ok := v.nodes[nodeID]
+	if !ok {
+		stats = &nodeStats{latency: latency}
+		v.nodes[nodeID] = stats
+	}
+	stats.requests++
+	switch outcome {
+	case outcomeSuccess:
+		stats.successes++
+	case outcomeInvalidSignature:
+		stats.invalidSignatures++
+	case outcomeEmptySignature:
+		stats.emptySignatures++
+	case outcomeTimeout:
+		stats.timeouts++
+	case outcomeSendFailure:
+		stats.sendFailures++
+	}
+	stats.latency = time.Duration(
+		latencySmoothingFactor*float64(latency) + (1-latencySmoothingFactor)*float64(stats.latency),
+	)
+
+	switch outcome {
+	case outcomeSuccess:
+		stats.consecutiveFailures = 0
+	case outcomeEmptySignature:
+		// Neither resets nor extends the node's run of consecutive failures
+	default:
+		stats.consecutiveFailures++
+		// Give the node a fresh set of attempts once its exclusion period has elapsed
+		if stats.consecutiveFailures >= maxConsecutiveFailures {
+			stats.consecutiveFailures = 0
+			stats.excludedUntil = time.Now().Add(exclusionPeriod)
+		}
+	}
+
+	nodeIDLabel := nodeID.String()
+	v.metrics.ValidatorRequestOutcomes.WithLabelValues(nodeIDLabel, string(outcome)).Inc()
+	v.metrics.ValidatorResponseLatencyMS.WithLabelValues(nodeIDLabel).Set(float64(stats.latency.Milliseconds()))
+	excluded := 0.0
+	if time.Now().Before(stats.excludedUntil) {
+		excluded = 1
+	}
+	v.metrics.ValidatorExcluded.WithLabelValues(nodeIDLabel).Set(excluded)
+}
+
+// successRate returns the estimated probability that [nodeID] responds with a valid signature.
+// Observed outcomes are combined with an optimistic prior, so that nodes without any history are
+// assigned a rate of [priorSuccessRate].
+func (v *validatorStats) successRate(nodeID ids.NodeID) float64 {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	return v.nodes[nodeID].successRate()
+}
+
+// expectedLatency returns the moving average of the response latency of [nodeID]
+func (v *validatorStats) expectedLatency(nodeID ids.NodeID) time.Duration {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	stats, ok := v.nodes[nodeID]
+	if !ok || stats.latency <= 0 {
+		return defaultExpectedLatency
+	}
+	return stats.latency
+}
+
+// isExcluded returns true if [nodeID] has repeatedly failed and should not be queried unless its weight is needed
+func (v *validatorStats) isExcluded(nodeID ids.NodeID) bool {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	stats, ok := v.nodes[nodeID]
+	return ok && time.Now().Before(stats.excludedUntil)
+}
+
+// snapshot returns the reliability of every node that has been queried, ordered by node ID
+func (v *validatorStats) snapshot() []NodeReliability {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	now := time.Now()
+	reliability := make([]NodeReliability, 0, len(v.nodes))
+	for nodeID, stats := range v.nodes {
+		r := NodeReliability{
+			NodeID:            nodeID,
+			Requests:          stats.requests,
+			Successes:         stats.successes,
+			InvalidSignatures: stats.invalidSignatures,
+			EmptySignatures:   stats.emptySignatures,
+			Timeouts:          stats.timeouts,
+			SendFailures:      stats.sendFailures,
+			SuccessRate:       float64(stats.successes) / float64(stats.requests),
+			Latency:           stats.latency,
+		}
+		if now.Before(stats.excludedUntil) {
+			r.ExcludedUntil = stats.excludedUntil
+		}
+		reliability = append(reliability, r)
+	}
+	sort.Slice(reliability, func(i, j int) bool {
+		return reliability[i].NodeID.Compare(reliability[j].NodeID) < 0
+	})
+	return reliability
+}
+
+// successRate may be called on nil stats, in which case the prior success rate is returned
+func (s *nodeStats) successRate() float64 {
+	var successes, requests float64
+	if s != nil {
+		successes = float64(s.successes)
+		requests = float64(s.requests)
+	}
+	return (successes + priorSuccessRate*priorRequests) / (requests + priorRequests)
+}
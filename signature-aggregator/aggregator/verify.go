@@ -0,0 +1,140 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+)
+
+var errUnsupportedSignature = errors.New("unsupported signature type")
+
+// VerificationResult describes the outcome of verifying the signature of a signed Warp message
+type VerificationResult struct {
+	// Whether the aggregate signature is a valid signature of the message by the validators in its signer bit set,
+	// regardless of their weight
+	Valid bool
+	// Whether the signers hold at least the requested quorum percentage of the validator set's weight
+	QuorumReached bool
+	// Combined weight of the validators in the signer bit set
+	SignedWeight uint64
+	// Total weight of the signing subnet's canonical validator set
+	TotalWeight uint64
+	// The signing subnet and P-Chain height whose canonical validator set the signature was verified against
+	SigningSubnetID ids.ID
+	PChainHeight    uint64
+	// Set if the signature is not valid
+	Reason error
+}
+
+// Verified returns true if the signature is valid and reaches the requested quorum
+func (r *VerificationResult) Verified() bool {
+	return r.Valid && r.QuorumReached
+}
+
+// VerifySignedMessage verifies the BitSetSignature of [signedMessage] against the canonical validator set
+// of the signing subnet at [pChainHeight], and reports the weight of its signers relative to
+// [quorumPercentage]. If [inputSigningSubnet] is not set, the message is expected to be signed by the subnet
+// of its source blockchain. If [pChainHeight] is zero, the current P-Chain height is used. The message's
+// network ID is not checked.
+//
+// An invalid signature is reported in the returned result. An error is only returned if the validator set
+// could not be fetched.
+func VerifySignedMessage(
+	ctx context.Context,
+	pChainState validators.State,
+	signedMessage *avalancheWarp.Message,
+	inputSigningSubnet ids.ID,
+	pChainHeight uint64,
+	quorumPercentage uint64,
+) (*VerificationResult, error) {
+	signingSubnet := inputSigningSubnet
+	if signingSubnet == ids.Empty {
+		var err error
+		signingSubnet, err = pChainState.GetSubnetID(ctx, signedMessage.SourceChainID)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get subnet of source blockchain %s: %w",
+				signedMessage.SourceChainID,
+				err,
+			)
+		}
+	}
+	if pChainHeight == 0 {
+		var err error
+		pChainHeight, err = pChainState.GetCurrentHeight(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get P-Chain height: %w", err)
+		}
+	}
+	validatorSet, totalWeight, err := avalancheWarp.GetCanonicalValidatorSet(
+		ctx,
+		pChainState,
+		pChainHeight,
+		signingSubnet,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get canonical validator set of subnet %s at P-Chain height %d: %w",
+			signingSubnet,
+			pChainHeight,
+			err,
+		)
+	}
+
+	result := &VerificationResult{
+		TotalWeight:     totalWeight,
+		SigningSubnetID: signingSubnet,
+		PChainHeight:    pChainHeight,
+	}
+	signature, ok := signedMessage.Signature.(*avalancheWarp.BitSetSignature)
+	if !ok {
+		result.Reason = errUnsupportedSignature
+		return result, nil
+	}
+
+	// The bit set must not be zero-padded, matching avalancheWarp.BitSetSignature.Verify
+	signerIndices := set.BitsFromBytes(signature.Signers)
+	if len(signerIndices.Bytes()) != len(signature.Signers) {
+		result.Reason = avalancheWarp.ErrInvalidBitSet
+		return result, nil
+	}
+	signers, err := avalancheWarp.FilterValidators(signerIndices, validatorSet)
+	if err != nil {
+		result.Reason = err
+		return result, nil
+	}
+	// Since the signers are a subset of the validator set, their weight can not overflow
+	result.SignedWeight, _ = avalancheWarp.SumWeight(signers)
+	result.QuorumReached = avalancheWarp.VerifyWeight(
+		result.SignedWeight,
+		totalWeight,
+		quorumPercentage,
+		100,
+	) == nil
+
+	aggregateSignature, err := bls.SignatureFromBytes(signature.Signature[:])
+	if err != nil {
+		result.Reason = fmt.Errorf("%w: %w", avalancheWarp.ErrParseSignature, err)
+		return result, nil
+	}
+	aggregatePublicKey, err := avalancheWarp.AggregatePublicKeys(signers)
+	if err != nil {
+		result.Reason = err
+		return result, nil
+	}
+	if !bls.Verify(aggregatePublicKey, aggregateSignature, signedMessage.UnsignedMessage.Bytes()) {
+		result.Reason = avalancheWarp.ErrInvalidSignature
+		return result, nil
+	}
+	result.Valid = true
+	return result, nil
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignedMessage(t *testing.T) {
+	aggregator, _ := instantiateAggregator(t)
+	chainID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID()
+	msg, err := warp.NewUnsignedMessage(constants.UnitTestID, chainID, utils.RandomBytes(1234))
+	require.NoError(t, err)
+	connectedValidators, validatorSecretKeys := makeConnectedValidators(5)
+	pChainState := newPChainStateStub(chainID, subnetID, 10, connectedValidators)
+
+	// Signs [msg] by the validators at [signerIndices], using [secretKeys] in place of their actual keys
+	signMessage := func(secretKeys []*bls.SecretKey, signerIndices ...int) *warp.Message {
+		signatureMap := make(map[int][bls.SignatureLen]byte)
+		for _, i := range signerIndices {
+			signatureMap[i] = [bls.SignatureLen]byte(bls.SignatureToBytes(bls.Sign(secretKeys[i], msg.Bytes())))
+		}
+		signedMessage, err := aggregator.newSignedMessage(msg, signatureMap)
+		require.NoError(t, err)
+		return signedMessage
+	}
+
+	t.Run("valid with quorum", func(t *testing.T) {
+		result, err := VerifySignedMessage(
+			context.Background(),
+			pChainState,
+			signMessage(validatorSecretKeys, 0, 1, 2, 3),
+			ids.Empty,
+			0,
+			67,
+		)
+		require.NoError(t, err)
+		require.True(t, result.Verified())
+		require.Nil(t, result.Reason)
+		require.Equal(t, uint64(4), result.SignedWeight)
+		require.Equal(t, uint64(5), result.TotalWeight)
+		require.Equal(t, subnetID, result.SigningSubnetID)
+		require.Equal(t, pChainState.currentHeight, result.PChainHeight)
+	})
+
+	t.Run("valid without quorum", func(t *testing.T) {
+		result, err := VerifySignedMessage(
+			context.Background(),
+			pChainState,
+			signMessage(validatorSecretKeys, 0, 1),
+			subnetID,
+			5,
+			67,
+		)
+		require.NoError(t, err)
+		require.True(t, result.Valid)
+		require.False(t, result.QuorumReached)
+		require.False(t, result.Verified())
+		require.Equal(t, uint64(2), result.SignedWeight)
+		require.Equal(t, uint64(5), result.PChainHeight)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		// Validator 0's signature is produced by a different key
+		otherSecretKey, err := bls.NewSecretKey()
+		require.NoError(t, err)
+		secretKeys := append([]*bls.SecretKey{otherSecretKey}, validatorSecretKeys[1:]...)
+
+		result, err := VerifySignedMessage(
+			context.Background(),
+			pChainState,
+			signMessage(secretKeys, 0, 1, 2, 3, 4),
+			ids.Empty,
+			0,
+			67,
+		)
+		require.NoError(t, err)
+		require.False(t, result.Valid)
+		require.True(t, result.QuorumReached)
+		require.ErrorIs(t, result.Reason, warp.ErrInvalidSignature)
+	})
+}
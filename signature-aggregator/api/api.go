@@ -4,14 +4,18 @@
 package api
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	basecfg "github.com/ava-labs/awm-relayer/config"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
 	"github.com/ava-labs/awm-relayer/types"
@@ -36,15 +40,94 @@ type AggregateSignatureRequest struct {
 	// Optional. Integer from 0 to 100 representing the percentage of the quorum that is required to sign the message
 	// defaults to 67 if omitted.
 	QuorumPercentage uint64 `json:"quorum-percentage"`
+	// Optional. P-Chain height of the signing subnet's validator set to collect signatures from. This should match
+	// the P-Chain height against which the destination blockchain verifies the message. Defaults to the P-Chain
+	// height currently used by DestinationBlockchainID if provided, and to the current P-Chain height otherwise.
+	PChainHeight uint64 `json:"p-chain-height"`
+	// Optional hex or cb58 encoded ID of the blockchain the message will be delivered to. Only used if
+	// PChainHeight is omitted.
+	DestinationBlockchainID string `json:"destination-blockchain-id"`
+	// Optional. Policy used to retry signature requests to validators that have not yet responded.
+	// Omitted fields use their default values.
+	RetryPolicy *basecfg.RetryPolicy `json:"retry-policy"`
+	// Optional. If true, and the quorum is not reached once the retry policy is exhausted, the message
+	// signed by the signatures collected so far is returned instead of an error.
+	BestEffort bool `json:"best-effort"`
+	// Optional. Signatures of the message already obtained from validators. Only the validators whose
+	// signatures are still missing are queried.
+	Signatures []ValidatorSignature `json:"signatures"`
+}
+
+// A signature of the message by a validator, obtained outside of the signature aggregator
+type ValidatorSignature struct {
+	// Any of the validator's node IDs, e.g. "NodeID-..."
+	NodeID string `json:"node-id"`
+	// hex-encoded BLS signature, optionally prefixed with "0x".
+	Signature string `json:"signature"`
 }
 
 type AggregateSignatureResponse struct {
 	// hex encoding of the signature
 	SignedMessage string `json:"signed-message"`
+	// Combined weight of the validators that signed the message
+	SignedWeight uint64 `json:"signed-weight"`
+	// Total weight of the signing subnet's validator set
+	TotalWeight uint64 `json:"total-weight"`
+	// False if the message was signed on a best-effort basis without reaching the requested quorum
+	QuorumReached bool `json:"quorum-reached"`
 }
 
 type AggregateSignatureErrorResponse struct {
 	Error string `json:"error"`
+	// Set if the signatures could not be aggregated due to insufficient connected or signed stake weight
+	Details *AggregationErrorDetails `json:"details,omitempty"`
+}
+
+// Describes the state of a failed signature aggregation, to help diagnose validator-side problems
+type AggregationErrorDetails struct {
+	ConnectedWeight       uint64   `json:"connected-weight"`
+	TotalWeight           uint64   `json:"total-weight"`
+	SignedWeight          uint64   `json:"signed-weight"`
+	QuorumPercentage      uint64   `json:"quorum-percentage"`
+	RequiredWeight        uint64   `json:"required-weight"`
+	TimedOutNodes         []string `json:"timed-out-nodes"`
+	InvalidSignatureNodes []string `json:"invalid-signature-nodes"`
+	UnconnectedNodes      []string `json:"unconnected-nodes"`
+}
+
+// Returns the diagnostics of [err] if it is an aggregator.AggregationError, and nil otherwise
+func aggregationErrorDetails(err error) *AggregationErrorDetails {
+	var aggErr *aggregator.AggregationError
+	if !errors.As(err, &aggErr) {
+		return nil
+	}
+	return &AggregationErrorDetails{
+		ConnectedWeight:       aggErr.ConnectedWeight,
+		TotalWeight:           aggErr.TotalWeight,
+		SignedWeight:          aggErr.SignedWeight,
+		QuorumPercentage:      aggErr.QuorumPercentage,
+		RequiredWeight:        aggErr.RequiredWeight,
+		TimedOutNodes:         nodeIDStrings(aggErr.TimedOutNodes),
+		InvalidSignatureNodes: nodeIDStrings(aggErr.InvalidSignatureNodes),
+		UnconnectedNodes:      nodeIDStrings(aggErr.UnconnectedNodes),
+	}
+}
+
+// Returns the message signed by the signatures collected before [err] occurred, if any
+func partialSignedMessage(err error) *avalancheWarp.Message {
+	var aggErr *aggregator.AggregationError
+	if !errors.As(err, &aggErr) {
+		return nil
+	}
+	return aggErr.PartialMessage
+}
+
+func nodeIDStrings(nodeIDs []ids.NodeID) []string {
+	strs := make([]string, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		strs[i] = nodeID.String()
+	}
+	return strs
 }
 
 func HandleAggregateSignaturesByRawMsgRequest(
@@ -68,11 +151,23 @@ func writeJSONError(
 	httpStatusCode int,
 	errorMsg string,
 ) {
-	resp, err := json.Marshal(
+	writeJSONErrorResponse(
+		logger,
+		w,
+		httpStatusCode,
 		AggregateSignatureErrorResponse{
 			Error: errorMsg,
 		},
 	)
+}
+
+func writeJSONErrorResponse(
+	logger logging.Logger,
+	w http.ResponseWriter,
+	httpStatusCode int,
+	errorResponse AggregateSignatureErrorResponse,
+) {
+	resp, err := json.Marshal(errorResponse)
 	if err != nil {
 		msg := "Error marshalling JSON error response"
 		logger.Error(msg, zap.Error(err))
@@ -88,10 +183,271 @@ func writeJSONError(
 	}
 }
 
+// Decodes the hex-encoded message and justification of a signature aggregation request.
+// The returned error is suitable to be returned to the client.
+func decodeMessage(
+	logger logging.Logger,
+	hexMessage string,
+	hexJustification string,
+) (*avalancheWarp.UnsignedMessage, []byte, error) {
+	decodedMessage, err := hex.DecodeString(
+		strings.TrimPrefix(hexMessage, "0x"),
+	)
+	if err != nil {
+		msg := "Could not decode message"
+		logger.Warn(
+			msg,
+			zap.String("msg", hexMessage),
+			zap.Error(err),
+		)
+		return nil, nil, errors.New(msg)
+	}
+	justification, err := hex.DecodeString(
+		utils.SanitizeHexString(hexJustification),
+	)
+	if err != nil {
+		msg := "Could not decode justification"
+		logger.Warn(
+			msg,
+			zap.String("justification", hexJustification),
+			zap.Error(err),
+		)
+		return nil, nil, errors.New(msg)
+	}
+	return unpackMessage(logger, decodedMessage, justification)
+}
+
+// Decodes the validator signatures provided in a signature aggregation request.
+// The returned error is suitable to be returned to the client.
+func decodeSignatures(
+	logger logging.Logger,
+	signatures []ValidatorSignature,
+) ([]aggregator.ValidatorSignature, error) {
+	decoded := make([]aggregator.ValidatorSignature, len(signatures))
+	for i, signature := range signatures {
+		nodeID, err := ids.NodeIDFromString(signature.NodeID)
+		if err != nil {
+			msg := "Could not decode signature node ID"
+			logger.Warn(msg, zap.String("nodeID", signature.NodeID), zap.Error(err))
+			return nil, errors.New(msg)
+		}
+		signatureBytes, err := hex.DecodeString(utils.SanitizeHexString(signature.Signature))
+		if err != nil {
+			msg := "Could not decode signature"
+			logger.Warn(msg, zap.String("signature", signature.Signature), zap.Error(err))
+			return nil, errors.New(msg)
+		}
+		decoded[i] = aggregator.ValidatorSignature{
+			NodeID:    nodeID,
+			Signature: signatureBytes,
+		}
+	}
+	return decoded, nil
+}
+
+// Unpacks the serialized message of a signature aggregation request, and verifies that either the message
+// or justification is provided. The returned error is suitable to be returned to the client.
+func unpackMessage(
+	logger logging.Logger,
+	messageBytes []byte,
+	justification []byte,
+) (*avalancheWarp.UnsignedMessage, []byte, error) {
+	message, err := types.UnpackWarpMessage(messageBytes)
+	if err != nil {
+		msg := "Error unpacking warp message"
+		logger.Warn(msg, zap.Error(err))
+		return nil, nil, errors.New(msg)
+	}
+	if utils.IsEmptyOrZeroes(message.Bytes()) && utils.IsEmptyOrZeroes(justification) {
+		return nil, nil, errors.New("Must provide either message or justification")
+	}
+	return message, justification, nil
+}
+
+// Validates the signing subnet ID, quorum percentage, and retry policy of a signature aggregation request,
+// applying defaults where omitted. The returned error is suitable to be returned to the client.
+func parseSigningParameters(
+	logger logging.Logger,
+	signingSubnet string,
+	requestedQuorumPercentage uint64,
+	retryPolicy *basecfg.RetryPolicy,
+) (ids.ID, uint64, error) {
+	quorumPercentage, err := validateSigningParameters(logger, requestedQuorumPercentage, retryPolicy)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	var signingSubnetID ids.ID
+	if signingSubnet != "" {
+		var err error
+		signingSubnetID, err = utils.HexOrCB58ToID(
+			signingSubnet,
+		)
+		if err != nil {
+			msg := "Error parsing signing subnet ID"
+			logger.Warn(
+				msg,
+				zap.Error(err),
+				zap.String("input", signingSubnet),
+			)
+			return ids.Empty, 0, errors.New(msg)
+		}
+	}
+	return signingSubnetID, quorumPercentage, nil
+}
+
+// Parses the destination blockchain ID of a signature aggregation request, if provided.
+// The returned error is suitable to be returned to the client.
+func parseDestinationBlockchainID(logger logging.Logger, destinationBlockchainID string) (ids.ID, error) {
+	if destinationBlockchainID == "" {
+		return ids.Empty, nil
+	}
+	blockchainID, err := utils.HexOrCB58ToID(destinationBlockchainID)
+	if err != nil {
+		msg := "Error parsing destination blockchain ID"
+		logger.Warn(
+			msg,
+			zap.Error(err),
+			zap.String("input", destinationBlockchainID),
+		)
+		return ids.Empty, errors.New(msg)
+	}
+	return blockchainID, nil
+}
+
+// Returns the P-Chain height at which to aggregate signatures: [pChainHeight] if set, and otherwise the P-Chain
+// height currently used by [destinationBlockchainID] if set. Returns zero to use the current P-Chain height.
+// The returned error is suitable to be returned to the client.
+func resolvePChainHeight(
+	ctx context.Context,
+	signatureAggregator *aggregator.SignatureAggregator,
+	pChainHeight uint64,
+	destinationBlockchainID ids.ID,
+) (uint64, error) {
+	if pChainHeight != 0 || destinationBlockchainID == ids.Empty {
+		return pChainHeight, nil
+	}
+	proposedHeight, err := signatureAggregator.GetProposedHeight(ctx, destinationBlockchainID)
+	if err != nil {
+		return 0, errors.New("Failed to get P-Chain height of destination blockchain")
+	}
+	return proposedHeight, nil
+}
+
+// Validates the quorum percentage and retry policy of a signature aggregation request, and returns the
+// quorum percentage to use. The returned error is suitable to be returned to the client.
+func validateSigningParameters(
+	logger logging.Logger,
+	requestedQuorumPercentage uint64,
+	retryPolicy *basecfg.RetryPolicy,
+) (uint64, error) {
+	quorumPercentage := requestedQuorumPercentage
+	if quorumPercentage == 0 {
+		quorumPercentage = DefaultQuorumPercentage
+	} else if quorumPercentage > 100 {
+		msg := "Invalid quorum number"
+		logger.Warn(msg, zap.Uint64("quorum-num", requestedQuorumPercentage))
+		return 0, errors.New(msg)
+	}
+	if retryPolicy != nil {
+		if err := retryPolicy.Validate(); err != nil {
+			msg := "Invalid retry policy"
+			logger.Warn(msg, zap.Error(err))
+			return 0, errors.New(msg)
+		}
+	}
+	return quorumPercentage, nil
+}
+
+// Creates a signed message, starting from the provided [signatures] if any.
+func createSignedMessage(
+	ctx context.Context,
+	signatureAggregator *aggregator.SignatureAggregator,
+	message *avalancheWarp.UnsignedMessage,
+	justification []byte,
+	signatures []aggregator.ValidatorSignature,
+	signingSubnetID ids.ID,
+	pChainHeight uint64,
+	quorumPercentage uint64,
+	retryPolicy *basecfg.RetryPolicy,
+) (*avalancheWarp.Message, error) {
+	if len(signatures) == 0 {
+		return signatureAggregator.CreateSignedMessage(
+			ctx,
+			message,
+			justification,
+			signingSubnetID,
+			pChainHeight,
+			quorumPercentage,
+			retryPolicy,
+		)
+	}
+	return signatureAggregator.CreateSignedMessageWithSignatures(
+		ctx,
+		message,
+		justification,
+		signatures,
+		signingSubnetID,
+		pChainHeight,
+		quorumPercentage,
+		retryPolicy,
+	)
+}
+
+// Creates a signed message, reporting the signature weight achieved. If [bestEffort] is set, the message
+// signed by the signatures collected so far is returned if the quorum is not reached, in which case the
+// returned bool is false.
+func aggregateSignatures(
+	ctx context.Context,
+	logger logging.Logger,
+	signatureAggregator *aggregator.SignatureAggregator,
+	message *avalancheWarp.UnsignedMessage,
+	justification []byte,
+	signatures []aggregator.ValidatorSignature,
+	signingSubnetID ids.ID,
+	pChainHeight uint64,
+	quorumPercentage uint64,
+	retryPolicy *basecfg.RetryPolicy,
+	bestEffort bool,
+) (*avalancheWarp.Message, aggregator.Progress, bool, error) {
+	var progress aggregator.Progress
+	ctx = aggregator.WithProgress(ctx, func(p aggregator.Progress) {
+		progress = p
+	})
+	if bestEffort {
+		ctx = aggregator.WithBestEffort(ctx)
+	}
+	signedMessage, err := createSignedMessage(
+		ctx,
+		signatureAggregator,
+		message,
+		justification,
+		signatures,
+		signingSubnetID,
+		pChainHeight,
+		quorumPercentage,
+		retryPolicy,
+	)
+	if err == nil {
+		return signedMessage, progress, true, nil
+	}
+	if bestEffort {
+		if partialMessage := partialSignedMessage(err); partialMessage != nil {
+			logger.Info(
+				"Returning best-effort signed message",
+				zap.String("warpMessageID", message.ID().String()),
+				zap.Uint64("signedWeight", progress.SignedWeight),
+				zap.Uint64("totalWeight", progress.TotalWeight),
+			)
+			return partialMessage, progress, false, nil
+		}
+	}
+	return nil, progress, false, err
+}
+
 func signatureAggregationAPIHandler(
 	logger logging.Logger,
 	metrics *metrics.SignatureAggregatorMetrics,
-	aggregator *aggregator.SignatureAggregator,
+	signatureAggregator *aggregator.SignatureAggregator,
 ) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		metrics.AggregateSignaturesRequestCount.Inc()
@@ -105,88 +461,71 @@ func signatureAggregationAPIHandler(
 			writeJSONError(logger, w, http.StatusBadRequest, msg)
 			return
 		}
-		var decodedMessage []byte
-		decodedMessage, err = hex.DecodeString(
-			strings.TrimPrefix(req.Message, "0x"),
-		)
+		message, justification, err := decodeMessage(logger, req.Message, req.Justification)
 		if err != nil {
-			msg := "Could not decode message"
-			logger.Warn(
-				msg,
-				zap.String("msg", req.Message),
-				zap.Error(err),
-			)
-			writeJSONError(logger, w, http.StatusBadRequest, msg)
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
 			return
 		}
-		message, err := types.UnpackWarpMessage(decodedMessage)
+		signingSubnetID, quorumPercentage, err := parseSigningParameters(
+			logger,
+			req.SigningSubnetID,
+			req.QuorumPercentage,
+			req.RetryPolicy,
+		)
 		if err != nil {
-			msg := "Error unpacking warp message"
-			logger.Warn(msg, zap.Error(err))
-			writeJSONError(logger, w, http.StatusBadRequest, msg)
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
 			return
 		}
-
-		justification, err := hex.DecodeString(
-			utils.SanitizeHexString(req.Justification),
-		)
+		signatures, err := decodeSignatures(logger, req.Signatures)
 		if err != nil {
-			msg := "Could not decode justification"
-			logger.Warn(
-				msg,
-				zap.String("justification", req.Justification),
-				zap.Error(err),
-			)
-			writeJSONError(logger, w, http.StatusBadRequest, msg)
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
 			return
 		}
-
-		if utils.IsEmptyOrZeroes(message.Bytes()) && utils.IsEmptyOrZeroes(justification) {
-			writeJSONError(
-				logger,
-				w,
-				http.StatusBadRequest,
-				"Must provide either message or justification",
-			)
+		destinationBlockchainID, err := parseDestinationBlockchainID(logger, req.DestinationBlockchainID)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
 			return
 		}
-
-		quorumPercentage := req.QuorumPercentage
-		if quorumPercentage == 0 {
-			quorumPercentage = DefaultQuorumPercentage
-		} else if req.QuorumPercentage > 100 {
-			msg := "Invalid quorum number"
-			logger.Warn(msg, zap.Uint64("quorum-num", req.QuorumPercentage))
-			writeJSONError(logger, w, http.StatusBadRequest, msg)
+		pChainHeight, err := resolvePChainHeight(
+			r.Context(),
+			signatureAggregator,
+			req.PChainHeight,
+			destinationBlockchainID,
+		)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusInternalServerError, err.Error())
 			return
 		}
-		var signingSubnetID ids.ID
-		if req.SigningSubnetID != "" {
-			signingSubnetID, err = utils.HexOrCB58ToID(
-				req.SigningSubnetID,
-			)
-			if err != nil {
-				msg := "Error parsing signing subnet ID"
-				logger.Warn(
-					msg,
-					zap.Error(err),
-					zap.String("input", req.SigningSubnetID),
-				)
-				writeJSONError(logger, w, http.StatusBadRequest, msg)
-				return
-			}
-		}
-
-		signedMessage, err := aggregator.CreateSignedMessage(
+
+		signedMessage, progress, quorumReached, err := aggregateSignatures(
+			r.Context(),
+			logger,
+			signatureAggregator,
 			message,
 			justification,
+			signatures,
 			signingSubnetID,
+			pChainHeight,
 			quorumPercentage,
+			req.RetryPolicy,
+			req.BestEffort,
 		)
+		if errors.Is(err, aggregator.ErrInvalidSignature) {
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			msg := "Failed to aggregate signatures"
 			logger.Warn(msg, zap.Error(err))
-			writeJSONError(logger, w, http.StatusInternalServerError, msg)
+			writeJSONErrorResponse(
+				logger,
+				w,
+				http.StatusInternalServerError,
+				AggregateSignatureErrorResponse{
+					Error:   msg,
+					Details: aggregationErrorDetails(err),
+				},
+			)
 			return
 		}
 		resp, err := json.Marshal(
@@ -194,6 +533,9 @@ func signatureAggregationAPIHandler(
 				SignedMessage: hex.EncodeToString(
 					signedMessage.Bytes(),
 				),
+				SignedWeight:  progress.SignedWeight,
+				TotalWeight:   progress.TotalWeight,
+				QuorumReached: quorumReached,
 			},
 		)
 
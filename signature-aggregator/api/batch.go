@@ -0,0 +1,251 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	basecfg "github.com/ava-labs/awm-relayer/config"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/auth"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	BatchAPIPath = APIPath + "/batch"
+	// Maximum number of messages that may be included in a single batch request
+	MaxBatchSize = 100
+)
+
+// A single message to be signed as part of a batch request
+type BatchMessage struct {
+	// Required: either Message or Justification must be provided.
+	// hex-encoded message, optionally prefixed with "0x".
+	Message string `json:"message"`
+	// hex-encoded justification, optionally prefixed with "0x".
+	Justification string `json:"justification"`
+}
+
+// Defines a request interface for signature aggregation for a batch of raw unsigned messages.
+// The signing subnet, quorum percentage, P-Chain height, and retry policy apply to every message in the batch.
+type AggregateSignaturesBatchRequest struct {
+	// Required: between 1 and MaxBatchSize messages.
+	Messages []BatchMessage `json:"messages"`
+	// Optional hex or cb58 encoded signing subnet ID. If omitted will default to the subnetID of each message's
+	// source blockchain
+	SigningSubnetID string `json:"signing-subnet-id"`
+	// Optional. Integer from 0 to 100 representing the percentage of the quorum that is required to sign the message
+	// defaults to 67 if omitted.
+	QuorumPercentage uint64 `json:"quorum-percentage"`
+	// Optional. P-Chain height of the signing subnet's validator set to collect signatures from. This should match
+	// the P-Chain height against which the destination blockchain verifies the message. Defaults to the P-Chain
+	// height currently used by DestinationBlockchainID if provided, and to the current P-Chain height otherwise.
+	PChainHeight uint64 `json:"p-chain-height"`
+	// Optional hex or cb58 encoded ID of the blockchain the message will be delivered to. Only used if
+	// PChainHeight is omitted.
+	DestinationBlockchainID string `json:"destination-blockchain-id"`
+	// Optional. Policy used to retry signature requests to validators that have not yet responded.
+	// Omitted fields use their default values.
+	RetryPolicy *basecfg.RetryPolicy `json:"retry-policy"`
+}
+
+// The result of a single message in a batch. Exactly one of SignedMessage and Error is set.
+type BatchResult struct {
+	// hex encoding of the signed message
+	SignedMessage string `json:"signed-message,omitempty"`
+	Error         string `json:"error,omitempty"`
+	// Set if the signatures could not be aggregated due to insufficient connected or signed stake weight
+	Details *AggregationErrorDetails `json:"details,omitempty"`
+}
+
+type AggregateSignaturesBatchResponse struct {
+	// One result per requested message, in the same order as the request
+	Results []BatchResult `json:"results"`
+}
+
+func HandleAggregateSignaturesBatchRequest(
+	logger logging.Logger,
+	metrics *metrics.SignatureAggregatorMetrics,
+	signatureAggregator *aggregator.SignatureAggregator,
+) {
+	http.Handle(
+		BatchAPIPath,
+		batchSignatureAggregationAPIHandler(
+			logger,
+			metrics,
+			signatureAggregator,
+		),
+	)
+}
+
+func batchSignatureAggregationAPIHandler(
+	logger logging.Logger,
+	metrics *metrics.SignatureAggregatorMetrics,
+	signatureAggregator *aggregator.SignatureAggregator,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AggregateSignaturesBatchRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			msg := "Could not decode request body"
+			logger.Warn(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusBadRequest, msg)
+			return
+		}
+		if len(req.Messages) == 0 || len(req.Messages) > MaxBatchSize {
+			msg := fmt.Sprintf("Batch must contain between 1 and %d messages", MaxBatchSize)
+			logger.Warn(msg, zap.Int("batchSize", len(req.Messages)))
+			writeJSONError(logger, w, http.StatusBadRequest, msg)
+			return
+		}
+		// Each message in the batch counts towards the client's rate limit
+		if err := auth.Charge(r.Context(), len(req.Messages)-1); errors.Is(err, auth.ErrBurstExceeded) {
+			msg := "Batch size exceeds the client's rate limit burst"
+			logger.Debug(msg, zap.Int("batchSize", len(req.Messages)))
+			writeJSONError(logger, w, http.StatusBadRequest, msg)
+			return
+		} else if err != nil {
+			msg := "Rate limit exceeded"
+			logger.Debug(msg, zap.Int("batchSize", len(req.Messages)))
+			writeJSONError(logger, w, http.StatusTooManyRequests, msg)
+			return
+		}
+		metrics.AggregateSignaturesRequestCount.Add(float64(len(req.Messages)))
+
+		signingSubnetID, quorumPercentage, err := parseSigningParameters(
+			logger,
+			req.SigningSubnetID,
+			req.QuorumPercentage,
+			req.RetryPolicy,
+		)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
+			return
+		}
+		destinationBlockchainID, err := parseDestinationBlockchainID(logger, req.DestinationBlockchainID)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pChainHeight, err := resolvePChainHeight(
+			r.Context(),
+			signatureAggregator,
+			req.PChainHeight,
+			destinationBlockchainID,
+		)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		requests := make([]aggregator.SignatureRequest, len(req.Messages))
+		decodeErrs := make([]error, len(req.Messages))
+		for i, batchMessage := range req.Messages {
+			message, justification, err := decodeMessage(logger, batchMessage.Message, batchMessage.Justification)
+			requests[i] = aggregator.SignatureRequest{
+				UnsignedMessage: message,
+				Justification:   justification,
+			}
+			decodeErrs[i] = err
+		}
+
+		signatureResults := aggregateBatch(
+			r.Context(),
+			logger,
+			signatureAggregator,
+			requests,
+			decodeErrs,
+			signingSubnetID,
+			pChainHeight,
+			quorumPercentage,
+			req.RetryPolicy,
+		)
+		results := make([]BatchResult, len(signatureResults))
+		for i, result := range signatureResults {
+			if result.Err != nil {
+				results[i].Error = result.Err.Error()
+				results[i].Details = aggregationErrorDetails(result.Err)
+				continue
+			}
+			results[i].SignedMessage = hex.EncodeToString(result.SignedMessage.Bytes())
+		}
+
+		resp, err := json.Marshal(
+			AggregateSignaturesBatchResponse{
+				Results: results,
+			},
+		)
+		if err != nil {
+			msg := "Failed to marshal response"
+			logger.Error(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusInternalServerError, msg)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(resp)
+		if err != nil {
+			logger.Error("Error writing response", zap.Error(err))
+		}
+	})
+}
+
+// Collects signatures for the messages of a batch request. Messages that could not be decoded, as indicated
+// by a non-nil entry in [decodeErrs], are reported in their result without failing the rest of the batch.
+// The returned errors are suitable to be returned to the client.
+func aggregateBatch(
+	ctx context.Context,
+	logger logging.Logger,
+	signatureAggregator *aggregator.SignatureAggregator,
+	requests []aggregator.SignatureRequest,
+	decodeErrs []error,
+	signingSubnetID ids.ID,
+	pChainHeight uint64,
+	quorumPercentage uint64,
+	retryPolicy *basecfg.RetryPolicy,
+) []aggregator.SignatureResult {
+	results := make([]aggregator.SignatureResult, len(requests))
+	var (
+		decodedRequests []aggregator.SignatureRequest
+		requestIndices  []int
+	)
+	for i, request := range requests {
+		if decodeErrs[i] != nil {
+			results[i].Err = decodeErrs[i]
+			continue
+		}
+		decodedRequests = append(decodedRequests, request)
+		requestIndices = append(requestIndices, i)
+	}
+
+	signatureResults := signatureAggregator.CreateSignedMessages(
+		ctx,
+		decodedRequests,
+		signingSubnetID,
+		pChainHeight,
+		quorumPercentage,
+		retryPolicy,
+	)
+	for j, result := range signatureResults {
+		i := requestIndices[j]
+		if result.Err != nil {
+			logger.Warn(
+				"Failed to aggregate signatures",
+				zap.String("warpMessageID", decodedRequests[j].UnsignedMessage.ID().String()),
+				zap.Error(result.Err),
+			)
+			results[i].Err = fmt.Errorf("Failed to aggregate signatures: %w", result.Err)
+			continue
+		}
+		results[i] = result
+	}
+	return results
+}
@@ -0,0 +1,292 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/proto/pb/sdk"
+	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/awm-relayer/peers"
+	"github.com/ava-labs/awm-relayer/peers/mocks"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/auth"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/config"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestAggregator(t *testing.T) (
+	*aggregator.SignatureAggregator,
+	*mocks.MockAppRequestNetwork,
+	*metrics.SignatureAggregatorMetrics,
+) {
+	mockNetwork := mocks.NewMockAppRequestNetwork(gomock.NewController(t))
+	messageCreator, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		constants.DefaultNetworkCompressionType,
+		constants.DefaultNetworkMaximumInboundTimeout,
+	)
+	require.NoError(t, err)
+	sigAggMetrics := metrics.NewSignatureAggregatorMetrics(prometheus.NewRegistry())
+	signatureAggregator, err := aggregator.NewSignatureAggregator(
+		mockNetwork,
+		logging.NoLog{},
+		1024,
+		sigAggMetrics,
+		messageCreator,
+		time.Now().Add(-time.Minute),
+		aggregator.QueryAll,
+		nil,
+	)
+	require.NoError(t, err)
+	return signatureAggregator, mockNetwork, sigAggMetrics
+}
+
+// Returns a validator set of [count] validators of equal weight, along with their secret keys
+func makeValidators(t *testing.T, count int) (*peers.ConnectedCanonicalValidators, map[ids.NodeID]*bls.SecretKey) {
+	validatorSet := make([]*avalancheWarp.Validator, count)
+	secretKeys := make(map[ids.NodeID]*bls.SecretKey, count)
+	for i := range validatorSet {
+		secretKey, err := bls.NewSecretKey()
+		require.NoError(t, err)
+		publicKey := bls.PublicFromSecretKey(secretKey)
+		nodeID := ids.GenerateTestNodeID()
+		validatorSet[i] = &avalancheWarp.Validator{
+			PublicKey:      publicKey,
+			PublicKeyBytes: bls.PublicKeyToUncompressedBytes(publicKey),
+			Weight:         1,
+			NodeIDs:        []ids.NodeID{nodeID},
+		}
+		secretKeys[nodeID] = secretKey
+	}
+	utils.Sort(validatorSet)
+	nodeValidatorIndexMap := make(map[ids.NodeID]int, count)
+	for i, validator := range validatorSet {
+		nodeValidatorIndexMap[validator.NodeIDs[0]] = i
+	}
+	return &peers.ConnectedCanonicalValidators{
+		ConnectedWeight:       uint64(count),
+		TotalValidatorWeight:  uint64(count),
+		ValidatorSet:          validatorSet,
+		NodeValidatorIndexMap: nodeValidatorIndexMap,
+	}, secretKeys
+}
+
+// Sets up [mockNetwork] so that the validators of [validators] whose secret keys are in [secretKeys] sign
+// [unsignedMessage] when queried, while the remaining validators respond with an error
+func expectSignatures(
+	t *testing.T,
+	mockNetwork *mocks.MockAppRequestNetwork,
+	subnetID ids.ID,
+	unsignedMessage *avalancheWarp.UnsignedMessage,
+	validators *peers.ConnectedCanonicalValidators,
+	secretKeys map[ids.NodeID]*bls.SecretKey,
+) {
+	mockNetwork.EXPECT().RegisterAppRequest(gomock.Any()).AnyTimes()
+	mockNetwork.EXPECT().RegisterRequestID(gomock.Any(), len(validators.ValidatorSet)).DoAndReturn(
+		func(requestID uint32, _ int) chan message.InboundMessage {
+			responses := make(chan message.InboundMessage, len(validators.ValidatorSet))
+			for _, validator := range validators.ValidatorSet {
+				nodeID := validator.NodeIDs[0]
+				secretKey, ok := secretKeys[nodeID]
+				if !ok {
+					responses <- message.InboundAppError(
+						nodeID,
+						unsignedMessage.SourceChainID,
+						requestID,
+						0,
+						"unavailable",
+					)
+					continue
+				}
+				responseBytes, err := proto.Marshal(&sdk.SignatureResponse{
+					Signature: bls.SignatureToBytes(bls.Sign(secretKey, unsignedMessage.Bytes())),
+				})
+				require.NoError(t, err)
+				responses <- message.InboundAppResponse(
+					unsignedMessage.SourceChainID,
+					requestID,
+					responseBytes,
+					nodeID,
+				)
+			}
+			return responses
+		},
+	)
+	mockNetwork.EXPECT().Send(gomock.Any(), gomock.Any(), subnetID, subnets.NoOpAllower).DoAndReturn(
+		func(_ message.OutboundMessage, nodeIDs set.Set[ids.NodeID], _ ids.ID, _ subnets.Allower) set.Set[ids.NodeID] {
+			return nodeIDs
+		},
+	)
+}
+
+func newUnsignedMessage(t *testing.T, sourceChainID ids.ID) *avalancheWarp.UnsignedMessage {
+	unsignedMessage, err := avalancheWarp.NewUnsignedMessage(
+		constants.UnitTestID,
+		sourceChainID,
+		utils.RandomBytes(32),
+	)
+	require.NoError(t, err)
+	return unsignedMessage
+}
+
+// POSTs [body] encoded as JSON to [handler], with the headers of [header]
+func postJSON(
+	t *testing.T,
+	handler http.Handler,
+	path string,
+	body interface{},
+	header map[string]string,
+) *httptest.ResponseRecorder {
+	reqBytes, err := json.Marshal(body)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(reqBytes)))
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestBatchHandlerRejectsInvalidBatchSizes(t *testing.T) {
+	signatureAggregator, _, sigAggMetrics := newTestAggregator(t)
+	handler := batchSignatureAggregationAPIHandler(logging.NoLog{}, sigAggMetrics, signatureAggregator)
+
+	for _, size := range []int{0, MaxBatchSize + 1} {
+		w := postJSON(t, handler, BatchAPIPath, AggregateSignaturesBatchRequest{
+			Messages: make([]BatchMessage, size),
+		}, nil)
+		require.Equal(t, http.StatusBadRequest, w.Code, size)
+		var resp AggregateSignatureErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Contains(t, resp.Error, "Batch must contain between 1 and 100 messages")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, BatchAPIPath, strings.NewReader("{"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBatchHandlerReportsPerMessageResults(t *testing.T) {
+	signatureAggregator, mockNetwork, sigAggMetrics := newTestAggregator(t)
+	handler := batchSignatureAggregationAPIHandler(logging.NoLog{}, sigAggMetrics, signatureAggregator)
+
+	// Messages from the signed chain are signed by every validator of its subnet, while the validators of the
+	// unsigned chain's subnet are not connected
+	signedChainID, signedSubnetID := ids.GenerateTestID(), ids.GenerateTestID()
+	unsignedChainID, unsignedSubnetID := ids.GenerateTestID(), ids.GenerateTestID()
+	validators, secretKeys := makeValidators(t, 3)
+	signedMessage := newUnsignedMessage(t, signedChainID)
+	unsignedMessage := newUnsignedMessage(t, unsignedChainID)
+
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), signedChainID).Return(signedSubnetID, nil)
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), unsignedChainID).Return(unsignedSubnetID, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), signedSubnetID, uint64(0)).Return(
+		validators,
+		nil,
+	)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), unsignedSubnetID, uint64(0)).Return(
+		&peers.ConnectedCanonicalValidators{
+			ConnectedWeight:      0,
+			TotalValidatorWeight: 1,
+			ValidatorSet:         []*avalancheWarp.Validator{},
+		},
+		nil,
+	)
+	expectSignatures(t, mockNetwork, signedSubnetID, signedMessage, validators, secretKeys)
+
+	w := postJSON(t, handler, BatchAPIPath, AggregateSignaturesBatchRequest{
+		Messages: []BatchMessage{
+			{Message: "not hex"},
+			{Message: hex.EncodeToString(signedMessage.Bytes())},
+			{Message: "0x" + hex.EncodeToString(unsignedMessage.Bytes())},
+		},
+	}, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp AggregateSignaturesBatchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Results, 3)
+
+	require.Equal(t, "Could not decode message", resp.Results[0].Error)
+	require.Empty(t, resp.Results[0].SignedMessage)
+	require.Nil(t, resp.Results[0].Details)
+
+	require.Empty(t, resp.Results[1].Error)
+	messageBytes, err := hex.DecodeString(resp.Results[1].SignedMessage)
+	require.NoError(t, err)
+	message, err := avalancheWarp.ParseMessage(messageBytes)
+	require.NoError(t, err)
+	require.Equal(t, signedMessage.ID(), message.UnsignedMessage.ID())
+
+	require.Contains(t, resp.Results[2].Error, "Failed to aggregate signatures")
+	require.Empty(t, resp.Results[2].SignedMessage)
+	require.NotNil(t, resp.Results[2].Details)
+	require.Zero(t, resp.Results[2].Details.ConnectedWeight)
+	require.Equal(t, uint64(1), resp.Results[2].Details.TotalWeight)
+}
+
+func TestBatchHandlerChargesRateLimit(t *testing.T) {
+	signatureAggregator, _, sigAggMetrics := newTestAggregator(t)
+	authenticator := auth.NewAuthenticator(logging.NoLog{}, sigAggMetrics, []config.APIClientConfig{
+		{Name: "client", APIKey: "key", RequestsPerSecond: 0.001, Burst: 3},
+	})
+	handler := authenticator.HTTPMiddleware(
+		batchSignatureAggregationAPIHandler(logging.NoLog{}, sigAggMetrics, signatureAggregator),
+	)
+	header := map[string]string{auth.APIKeyHeader: "key"}
+	messages := func(count int) []BatchMessage {
+		batch := make([]BatchMessage, count)
+		for i := range batch {
+			batch[i].Message = hex.EncodeToString(newUnsignedMessage(t, ids.GenerateTestID()).Bytes())
+		}
+		return batch
+	}
+
+	// A batch larger than the client's burst could never be admitted
+	w := postJSON(t, handler, BatchAPIPath, AggregateSignaturesBatchRequest{Messages: messages(4)}, header)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "Batch size exceeds the client's rate limit burst")
+
+	// The rejected batch consumed a single request, leaving two for this batch of three
+	w = postJSON(t, handler, BatchAPIPath, AggregateSignaturesBatchRequest{Messages: messages(3)}, header)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Contains(t, w.Body.String(), "Rate limit exceeded")
+}
+
+func TestBatchHandlerRejectsInvalidParameters(t *testing.T) {
+	signatureAggregator, _, sigAggMetrics := newTestAggregator(t)
+	handler := batchSignatureAggregationAPIHandler(logging.NoLog{}, sigAggMetrics, signatureAggregator)
+	message := BatchMessage{Message: hex.EncodeToString(newUnsignedMessage(t, ids.GenerateTestID()).Bytes())}
+
+	for _, req := range []AggregateSignaturesBatchRequest{
+		{Messages: []BatchMessage{message}, SigningSubnetID: "not an ID"},
+		{Messages: []BatchMessage{message}, QuorumPercentage: 101},
+		{Messages: []BatchMessage{message}, DestinationBlockchainID: "not an ID"},
+	} {
+		w := postJSON(t, handler, BatchAPIPath, req, nil)
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	}
+}
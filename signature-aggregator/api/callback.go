@@ -0,0 +1,119 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+// Shared address space used for carrier-grade NAT (RFC 6598), which is not covered by netip.Addr.IsPrivate
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// callbackClient POSTs to job callback URLs. Unless a callback's host is explicitly allowed, it must resolve
+// exclusively to public addresses, so that callers cannot use callbacks to reach loopback, private or
+// link-local addresses such as cloud metadata endpoints. The check is made both when the job is submitted
+// and when connecting, against the addresses that are actually dialed.
+type callbackClient struct {
+	allowedHosts set.Set[string]
+	resolver     *net.Resolver
+	dialer       *net.Dialer
+	client       *http.Client
+}
+
+func newCallbackClient(allowedHosts []string) *callbackClient {
+	c := &callbackClient{
+		allowedHosts: set.NewSet[string](len(allowedHosts)),
+		resolver:     net.DefaultResolver,
+		dialer:       &net.Dialer{Timeout: callbackTimeout},
+	}
+	for _, host := range allowedHosts {
+		c.allowedHosts.Add(strings.ToLower(host))
+	}
+	c.client = &http.Client{
+		Timeout: callbackTimeout,
+		Transport: &http.Transport{
+			// Callbacks are never sent through a proxy, which would connect to the host on our behalf
+			Proxy:               nil,
+			DialContext:         c.dialContext,
+			TLSHandshakeTimeout: callbackTimeout,
+		},
+		// Redirects are not followed, so the callback is only ever delivered to the validated URL
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return c
+}
+
+// validate checks that [callbackURL] is an http or https URL whose host callbacks may be POSTed to
+func (c *callbackClient) validate(ctx context.Context, callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("callback URL scheme must be http or https")
+	}
+	if u.Hostname() == "" {
+		return errors.New("callback URL must include a host")
+	}
+	_, err = c.resolve(ctx, u.Hostname())
+	return err
+}
+
+// resolve returns the addresses of [host]. Returns an error if the host is not allowed and any of its
+// addresses are not public.
+func (c *callbackClient) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	addrs, err := c.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve callback host %s: %w", host, err)
+	}
+	if c.allowedHosts.Contains(strings.ToLower(host)) {
+		return addrs, nil
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return nil, fmt.Errorf("callback host %s resolves to non-public address %s", host, addr)
+		}
+	}
+	return addrs, nil
+}
+
+// dialContext connects to one of the addresses [address] resolves to, after checking them
+func (c *callbackClient) dialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := c.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var dialErr error
+	for _, addr := range addrs {
+		conn, err := c.dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		dialErr = err
+	}
+	if dialErr == nil {
+		dialErr = fmt.Errorf("no addresses found for callback host %s", host)
+	}
+	return nil, dialErr
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
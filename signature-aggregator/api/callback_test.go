@@ -0,0 +1,83 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	testCases := []struct {
+		addr   string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"100.64.0.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00:ec2::254", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.addr, func(t *testing.T) {
+			require.Equal(t, testCase.public, isPublicAddr(netip.MustParseAddr(testCase.addr)))
+		})
+	}
+}
+
+func TestCallbackClientValidate(t *testing.T) {
+	client := newCallbackClient([]string{"10.0.0.5"})
+	ctx := context.Background()
+
+	require.NoError(t, client.validate(ctx, "https://8.8.8.8/callback"))
+	require.NoError(t, client.validate(ctx, "http://10.0.0.5:9000/callback"))
+
+	for _, callbackURL := range []string{
+		"ftp://8.8.8.8/callback",
+		"http:///callback",
+		"http://127.0.0.1/callback",
+		"http://localhost/callback",
+		"http://[::1]:8080/callback",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.6/callback",
+	} {
+		require.Error(t, client.validate(ctx, callbackURL), callbackURL)
+	}
+}
+
+func TestCallbackClientDial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Connections to the loopback server are refused unless its host is allowed
+	resp, err := newCallbackClient(nil).client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	require.ErrorContains(t, err, "non-public address")
+	require.Nil(t, resp)
+
+	resp, err = newCallbackClient([]string{"127.0.0.1"}).client.Post(
+		server.URL,
+		"application/json",
+		strings.NewReader("{}"),
+	)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
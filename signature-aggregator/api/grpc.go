@@ -0,0 +1,300 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	basecfg "github.com/ava-labs/awm-relayer/config"
+	pb "github.com/ava-labs/awm-relayer/proto/pb/signatureaggregator"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/auth"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer serves the SignatureAggregatorService, with the same semantics as the JSON HTTP API
+type grpcServer struct {
+	pb.UnimplementedSignatureAggregatorServiceServer
+
+	logger              logging.Logger
+	metrics             *metrics.SignatureAggregatorMetrics
+	signatureAggregator *aggregator.SignatureAggregator
+}
+
+// NewGRPCServer returns a gRPC server with the SignatureAggregatorService registered
+func NewGRPCServer(
+	logger logging.Logger,
+	metrics *metrics.SignatureAggregatorMetrics,
+	signatureAggregator *aggregator.SignatureAggregator,
+	authenticator *auth.Authenticator,
+) *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(authenticator.UnaryServerInterceptor()))
+	pb.RegisterSignatureAggregatorServiceServer(server, &grpcServer{
+		logger:              logger,
+		metrics:             metrics,
+		signatureAggregator: signatureAggregator,
+	})
+	return server
+}
+
+func (s *grpcServer) AggregateSignatures(
+	ctx context.Context,
+	req *pb.AggregateSignaturesRequest,
+) (*pb.AggregateSignaturesResponse, error) {
+	s.metrics.AggregateSignaturesRequestCount.Inc()
+	startTime := time.Now()
+
+	message, justification, err := unpackMessage(s.logger, req.GetMessage(), req.GetJustification())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	retryPolicy := retryPolicyFromProto(req.GetRetryPolicy())
+	signingSubnetID, quorumPercentage, err := s.parseSigningParameters(
+		req.GetSigningSubnetId(),
+		req.GetQuorumPercentage(),
+		retryPolicy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := s.parseSignatures(req.GetSignatures())
+	if err != nil {
+		return nil, err
+	}
+	pChainHeight, err := s.resolvePChainHeight(ctx, req.GetPChainHeight(), req.GetDestinationBlockchainId())
+	if err != nil {
+		return nil, err
+	}
+
+	signedMessage, progress, quorumReached, err := aggregateSignatures(
+		ctx,
+		s.logger,
+		s.signatureAggregator,
+		message,
+		justification,
+		signatures,
+		signingSubnetID,
+		pChainHeight,
+		quorumPercentage,
+		retryPolicy,
+		req.GetBestEffort(),
+	)
+	if err != nil {
+		s.logger.Warn("Failed to aggregate signatures", zap.Error(err))
+		return nil, aggregationStatus(err)
+	}
+	s.metrics.AggregateSignaturesLatencyMS.Set(
+		float64(time.Since(startTime).Milliseconds()),
+	)
+	return &pb.AggregateSignaturesResponse{
+		SignedMessage: signedMessage.Bytes(),
+		SignedWeight:  progress.SignedWeight,
+		TotalWeight:   progress.TotalWeight,
+		QuorumReached: quorumReached,
+	}, nil
+}
+
+func (s *grpcServer) AggregateSignaturesBatch(
+	ctx context.Context,
+	req *pb.AggregateSignaturesBatchRequest,
+) (*pb.AggregateSignaturesBatchResponse, error) {
+	messages := req.GetMessages()
+	if len(messages) == 0 || len(messages) > MaxBatchSize {
+		msg := fmt.Sprintf("Batch must contain between 1 and %d messages", MaxBatchSize)
+		s.logger.Warn(msg, zap.Int("batchSize", len(messages)))
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+	// Each message in the batch counts towards the client's rate limit
+	if err := auth.Charge(ctx, len(messages)-1); errors.Is(err, auth.ErrBurstExceeded) {
+		return nil, status.Error(codes.InvalidArgument, "Batch size exceeds the client's rate limit burst")
+	} else if err != nil {
+		return nil, status.Error(codes.ResourceExhausted, "Rate limit exceeded")
+	}
+	s.metrics.AggregateSignaturesRequestCount.Add(float64(len(messages)))
+
+	retryPolicy := retryPolicyFromProto(req.GetRetryPolicy())
+	signingSubnetID, quorumPercentage, err := s.parseSigningParameters(
+		req.GetSigningSubnetId(),
+		req.GetQuorumPercentage(),
+		retryPolicy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	pChainHeight, err := s.resolvePChainHeight(ctx, req.GetPChainHeight(), req.GetDestinationBlockchainId())
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]aggregator.SignatureRequest, len(messages))
+	decodeErrs := make([]error, len(messages))
+	for i, batchMessage := range messages {
+		message, justification, err := unpackMessage(s.logger, batchMessage.GetMessage(), batchMessage.GetJustification())
+		requests[i] = aggregator.SignatureRequest{
+			UnsignedMessage: message,
+			Justification:   justification,
+		}
+		decodeErrs[i] = err
+	}
+
+	signatureResults := aggregateBatch(
+		ctx,
+		s.logger,
+		s.signatureAggregator,
+		requests,
+		decodeErrs,
+		signingSubnetID,
+		pChainHeight,
+		quorumPercentage,
+		retryPolicy,
+	)
+	results := make([]*pb.BatchResult, len(signatureResults))
+	for i, result := range signatureResults {
+		if result.Err != nil {
+			results[i] = &pb.BatchResult{
+				Error:   result.Err.Error(),
+				Details: aggregationErrorDetailsProto(result.Err),
+			}
+			continue
+		}
+		results[i] = &pb.BatchResult{
+			SignedMessage: result.SignedMessage.Bytes(),
+		}
+	}
+	return &pb.AggregateSignaturesBatchResponse{
+		Results: results,
+	}, nil
+}
+
+// Validates the signing parameters of a request, returning an InvalidArgument status if they are invalid
+func (s *grpcServer) parseSigningParameters(
+	signingSubnet []byte,
+	requestedQuorumPercentage uint64,
+	retryPolicy *basecfg.RetryPolicy,
+) (ids.ID, uint64, error) {
+	quorumPercentage, err := validateSigningParameters(s.logger, requestedQuorumPercentage, retryPolicy)
+	if err != nil {
+		return ids.Empty, 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var signingSubnetID ids.ID
+	if len(signingSubnet) != 0 {
+		signingSubnetID, err = ids.ToID(signingSubnet)
+		if err != nil {
+			msg := "Error parsing signing subnet ID"
+			s.logger.Warn(msg, zap.Error(err))
+			return ids.Empty, 0, status.Error(codes.InvalidArgument, msg)
+		}
+	}
+	return signingSubnetID, quorumPercentage, nil
+}
+
+// Returns the P-Chain height at which to aggregate signatures, as by resolvePChainHeight
+func (s *grpcServer) resolvePChainHeight(
+	ctx context.Context,
+	pChainHeight uint64,
+	destinationBlockchain []byte,
+) (uint64, error) {
+	var destinationBlockchainID ids.ID
+	if len(destinationBlockchain) != 0 {
+		var err error
+		destinationBlockchainID, err = ids.ToID(destinationBlockchain)
+		if err != nil {
+			msg := "Error parsing destination blockchain ID"
+			s.logger.Warn(msg, zap.Error(err))
+			return 0, status.Error(codes.InvalidArgument, msg)
+		}
+	}
+	pChainHeight, err := resolvePChainHeight(ctx, s.signatureAggregator, pChainHeight, destinationBlockchainID)
+	if err != nil {
+		return 0, status.Error(codes.Unavailable, err.Error())
+	}
+	return pChainHeight, nil
+}
+
+// Parses the provided validator signatures, returning an InvalidArgument status if a node ID is invalid
+func (s *grpcServer) parseSignatures(signatures []*pb.ValidatorSignature) ([]aggregator.ValidatorSignature, error) {
+	parsed := make([]aggregator.ValidatorSignature, len(signatures))
+	for i, signature := range signatures {
+		nodeID, err := ids.ToNodeID(signature.GetNodeId())
+		if err != nil {
+			msg := "Error parsing signature node ID"
+			s.logger.Warn(msg, zap.Error(err))
+			return nil, status.Error(codes.InvalidArgument, msg)
+		}
+		parsed[i] = aggregator.ValidatorSignature{
+			NodeID:    nodeID,
+			Signature: signature.GetSignature(),
+		}
+	}
+	return parsed, nil
+}
+
+func retryPolicyFromProto(retryPolicy *pb.RetryPolicy) *basecfg.RetryPolicy {
+	if retryPolicy == nil {
+		return nil
+	}
+	return &basecfg.RetryPolicy{
+		MaxAttempts:    retryPolicy.GetMaxAttempts(),
+		InitialDelayMs: retryPolicy.GetInitialDelayMs(),
+		Multiplier:     retryPolicy.GetMultiplier(),
+		MaxDelayMs:     retryPolicy.GetMaxDelayMs(),
+		Jitter:         retryPolicy.GetJitter(),
+		DeadlineMs:     retryPolicy.GetDeadlineMs(),
+	}
+}
+
+// Converts an error returned by the aggregator to a status. Failures due to insufficient connected or
+// signed stake weight are reported as Unavailable, with the aggregation diagnostics attached.
+func aggregationStatus(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	if errors.Is(err, aggregator.ErrInvalidSignature) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	details := aggregationErrorDetailsProto(err)
+	if details == nil {
+		return status.Error(codes.Internal, "Failed to aggregate signatures")
+	}
+	st, detailsErr := status.New(codes.Unavailable, "Failed to aggregate signatures").WithDetails(details)
+	if detailsErr != nil {
+		return status.Error(codes.Unavailable, "Failed to aggregate signatures")
+	}
+	return st.Err()
+}
+
+// Returns the diagnostics of [err] if it is an aggregator.AggregationError, and nil otherwise
+func aggregationErrorDetailsProto(err error) *pb.AggregationErrorDetails {
+	var aggErr *aggregator.AggregationError
+	if !errors.As(err, &aggErr) {
+		return nil
+	}
+	return &pb.AggregationErrorDetails{
+		ConnectedWeight:         aggErr.ConnectedWeight,
+		TotalWeight:             aggErr.TotalWeight,
+		SignedWeight:            aggErr.SignedWeight,
+		QuorumPercentage:        aggErr.QuorumPercentage,
+		RequiredWeight:          aggErr.RequiredWeight,
+		TimedOutNodeIds:         nodeIDBytes(aggErr.TimedOutNodes),
+		InvalidSignatureNodeIds: nodeIDBytes(aggErr.InvalidSignatureNodes),
+		UnconnectedNodeIds:      nodeIDBytes(aggErr.UnconnectedNodes),
+	}
+}
+
+func nodeIDBytes(nodeIDs []ids.NodeID) [][]byte {
+	b := make([][]byte, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		b[i] = nodeID.Bytes()
+	}
+	return b
+}
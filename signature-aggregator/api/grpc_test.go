@@ -0,0 +1,277 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	basecfg "github.com/ava-labs/awm-relayer/config"
+	"github.com/ava-labs/awm-relayer/peers"
+	pb "github.com/ava-labs/awm-relayer/proto/pb/signatureaggregator"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/auth"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Serves the gRPC API of [signatureAggregator] over an in-memory connection, and returns a client for it
+func newTestGRPCClient(
+	t *testing.T,
+	signatureAggregator *aggregator.SignatureAggregator,
+	sigAggMetrics *metrics.SignatureAggregatorMetrics,
+) pb.SignatureAggregatorServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(
+		logging.NoLog{},
+		sigAggMetrics,
+		signatureAggregator,
+		auth.NewAuthenticator(logging.NoLog{}, sigAggMetrics, nil),
+	)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+	return pb.NewSignatureAggregatorServiceClient(conn)
+}
+
+// Returns the AggregationErrorDetails attached to the status of [err]
+func statusAggregationErrorDetails(t *testing.T, err error) *pb.AggregationErrorDetails {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	for _, detail := range st.Details() {
+		if details, ok := detail.(*pb.AggregationErrorDetails); ok {
+			return details
+		}
+	}
+	return nil
+}
+
+func TestGRPCAggregateSignatures(t *testing.T) {
+	signatureAggregator, mockNetwork, sigAggMetrics := newTestAggregator(t)
+	client := newTestGRPCClient(t, signatureAggregator, sigAggMetrics)
+
+	chainID, subnetID := ids.GenerateTestID(), ids.GenerateTestID()
+	validators, secretKeys := makeValidators(t, 3)
+	unsignedMessage := newUnsignedMessage(t, chainID)
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(subnetID, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(validators, nil)
+	expectSignatures(t, mockNetwork, subnetID, unsignedMessage, validators, secretKeys)
+
+	resp, err := client.AggregateSignatures(context.Background(), &pb.AggregateSignaturesRequest{
+		Message: unsignedMessage.Bytes(),
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetQuorumReached())
+	require.Equal(t, uint64(3), resp.GetTotalWeight())
+	signedMessage, err := avalancheWarp.ParseMessage(resp.GetSignedMessage())
+	require.NoError(t, err)
+	require.Equal(t, unsignedMessage.ID(), signedMessage.UnsignedMessage.ID())
+}
+
+func TestGRPCAggregateSignaturesFailures(t *testing.T) {
+	signatureAggregator, mockNetwork, sigAggMetrics := newTestAggregator(t)
+	client := newTestGRPCClient(t, signatureAggregator, sigAggMetrics)
+
+	// One of the three validators signs, which is short of the quorum
+	chainID, subnetID := ids.GenerateTestID(), ids.GenerateTestID()
+	validators, secretKeys := makeValidators(t, 3)
+	signerID := validators.ValidatorSet[0].NodeIDs[0]
+	signerKeys := map[ids.NodeID]*bls.SecretKey{signerID: secretKeys[signerID]}
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), chainID).Return(subnetID, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), subnetID, uint64(0)).Return(
+		validators,
+		nil,
+	).Times(2)
+	retryPolicy := &pb.RetryPolicy{MaxAttempts: 1}
+
+	// Without best-effort, the diagnostics are attached to an Unavailable status
+	unsignedMessage := newUnsignedMessage(t, chainID)
+	expectSignatures(t, mockNetwork, subnetID, unsignedMessage, validators, signerKeys)
+	_, err := client.AggregateSignatures(context.Background(), &pb.AggregateSignaturesRequest{
+		Message:     unsignedMessage.Bytes(),
+		RetryPolicy: retryPolicy,
+	})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	details := statusAggregationErrorDetails(t, err)
+	require.NotNil(t, details)
+	require.Equal(t, uint64(1), details.GetSignedWeight())
+	require.Equal(t, uint64(3), details.GetTotalWeight())
+	require.Equal(t, uint64(3), details.GetConnectedWeight())
+	require.Equal(t, uint64(DefaultQuorumPercentage), details.GetQuorumPercentage())
+
+	// With best-effort, the partially signed message is returned
+	unsignedMessage = newUnsignedMessage(t, chainID)
+	expectSignatures(t, mockNetwork, subnetID, unsignedMessage, validators, signerKeys)
+	resp, err := client.AggregateSignatures(context.Background(), &pb.AggregateSignaturesRequest{
+		Message:     unsignedMessage.Bytes(),
+		RetryPolicy: retryPolicy,
+		BestEffort:  true,
+	})
+	require.NoError(t, err)
+	require.False(t, resp.GetQuorumReached())
+	require.Equal(t, uint64(1), resp.GetSignedWeight())
+	signedMessage, err := avalancheWarp.ParseMessage(resp.GetSignedMessage())
+	require.NoError(t, err)
+	require.Equal(t, unsignedMessage.ID(), signedMessage.UnsignedMessage.ID())
+}
+
+func TestGRPCAggregateSignaturesInvalidArguments(t *testing.T) {
+	signatureAggregator, _, sigAggMetrics := newTestAggregator(t)
+	client := newTestGRPCClient(t, signatureAggregator, sigAggMetrics)
+	message := newUnsignedMessage(t, ids.GenerateTestID()).Bytes()
+
+	testCases := []struct {
+		name string
+		req  *pb.AggregateSignaturesRequest
+	}{
+		{
+			name: "invalid message",
+			req:  &pb.AggregateSignaturesRequest{Message: []byte{1, 2, 3}},
+		},
+		{
+			name: "invalid signing subnet",
+			req:  &pb.AggregateSignaturesRequest{Message: message, SigningSubnetId: []byte{1, 2, 3}},
+		},
+		{
+			name: "invalid quorum percentage",
+			req:  &pb.AggregateSignaturesRequest{Message: message, QuorumPercentage: 101},
+		},
+		{
+			name: "invalid retry policy",
+			req: &pb.AggregateSignaturesRequest{
+				Message:     message,
+				RetryPolicy: &pb.RetryPolicy{Multiplier: 0.5},
+			},
+		},
+		{
+			name: "invalid signature node ID",
+			req: &pb.AggregateSignaturesRequest{
+				Message:    message,
+				Signatures: []*pb.ValidatorSignature{{NodeId: []byte{1, 2, 3}}},
+			},
+		},
+		{
+			name: "invalid destination blockchain",
+			req:  &pb.AggregateSignaturesRequest{Message: message, DestinationBlockchainId: []byte{1, 2, 3}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := client.AggregateSignatures(context.Background(), testCase.req)
+			require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+		})
+	}
+
+	for _, size := range []int{0, MaxBatchSize + 1} {
+		messages := make([]*pb.BatchMessage, size)
+		for i := range messages {
+			messages[i] = &pb.BatchMessage{Message: message}
+		}
+		_, err := client.AggregateSignaturesBatch(context.Background(), &pb.AggregateSignaturesBatchRequest{
+			Messages: messages,
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	}
+	_, err := client.AggregateSignaturesBatch(context.Background(), &pb.AggregateSignaturesBatchRequest{
+		Messages:         []*pb.BatchMessage{{Message: message}},
+		QuorumPercentage: 101,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
+
+func TestGRPCAggregateSignaturesBatch(t *testing.T) {
+	signatureAggregator, mockNetwork, sigAggMetrics := newTestAggregator(t)
+	client := newTestGRPCClient(t, signatureAggregator, sigAggMetrics)
+
+	signedChainID, signedSubnetID := ids.GenerateTestID(), ids.GenerateTestID()
+	unsignedChainID, unsignedSubnetID := ids.GenerateTestID(), ids.GenerateTestID()
+	validators, secretKeys := makeValidators(t, 3)
+	signedMessage := newUnsignedMessage(t, signedChainID)
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), signedChainID).Return(signedSubnetID, nil)
+	mockNetwork.EXPECT().GetSubnetID(gomock.Any(), unsignedChainID).Return(unsignedSubnetID, nil)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), signedSubnetID, uint64(0)).Return(
+		validators,
+		nil,
+	)
+	mockNetwork.EXPECT().ConnectToCanonicalValidators(gomock.Any(), unsignedSubnetID, uint64(0)).Return(
+		&peers.ConnectedCanonicalValidators{
+			ConnectedWeight:      0,
+			TotalValidatorWeight: 1,
+			ValidatorSet:         []*avalancheWarp.Validator{},
+		},
+		nil,
+	)
+	expectSignatures(t, mockNetwork, signedSubnetID, signedMessage, validators, secretKeys)
+
+	resp, err := client.AggregateSignaturesBatch(context.Background(), &pb.AggregateSignaturesBatchRequest{
+		Messages: []*pb.BatchMessage{
+			{Message: []byte{1, 2, 3}},
+			{Message: signedMessage.Bytes()},
+			{Message: newUnsignedMessage(t, unsignedChainID).Bytes()},
+		},
+	})
+	require.NoError(t, err)
+	results := resp.GetResults()
+	require.Len(t, results, 3)
+
+	require.Equal(t, "Error unpacking warp message", results[0].GetError())
+	require.Nil(t, results[0].GetDetails())
+
+	require.Empty(t, results[1].GetError())
+	message, err := avalancheWarp.ParseMessage(results[1].GetSignedMessage())
+	require.NoError(t, err)
+	require.Equal(t, signedMessage.ID(), message.UnsignedMessage.ID())
+
+	require.Contains(t, results[2].GetError(), "Failed to aggregate signatures")
+	require.Empty(t, results[2].GetSignedMessage())
+	require.NotNil(t, results[2].GetDetails())
+	require.Equal(t, uint64(1), results[2].GetDetails().GetTotalWeight())
+}
+
+func TestRetryPolicyFromProto(t *testing.T) {
+	require.Nil(t, retryPolicyFromProto(nil))
+	require.Equal(
+		t,
+		&basecfg.RetryPolicy{
+			MaxAttempts:    3,
+			InitialDelayMs: 100,
+			Multiplier:     1.5,
+			MaxDelayMs:     1_000,
+			Jitter:         0.1,
+			DeadlineMs:     10_000,
+		},
+		retryPolicyFromProto(&pb.RetryPolicy{
+			MaxAttempts:    3,
+			InitialDelayMs: 100,
+			Multiplier:     1.5,
+			Jitter:         0.1,
+			DeadlineMs:     10_000,
+			MaxDelayMs:     1_000,
+		}),
+	)
+}
@@ -0,0 +1,462 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	basecfg "github.com/ava-labs/awm-relayer/config"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/auth"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/config"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	JobsAPIPath = APIPath + "/jobs"
+	// Duration for which finished jobs can be polled before they are discarded
+	jobRetention = time.Hour
+	// Timeout of each POST to a job's callback URL
+	callbackTimeout = 10 * time.Second
+)
+
+var errTooManyJobs = errors.New("too many pending jobs")
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// Defines a request interface for submitting an asynchronous signature aggregation job.
+type AggregateSignatureJobRequest struct {
+	AggregateSignatureRequest
+	// Optional http or https URL to which the job's status is POSTed once the message has been signed
+	CallbackURL string `json:"callback-url"`
+}
+
+type AggregateSignatureJobSubmitResponse struct {
+	JobID string `json:"job-id"`
+}
+
+type AggregateSignatureJobResponse struct {
+	JobID  string    `json:"job-id"`
+	Status JobStatus `json:"status"`
+	// The current query attempt, starting at 1
+	Attempt uint64 `json:"attempt"`
+	// False if the message was signed on a best-effort basis without reaching the requested quorum
+	QuorumReached bool `json:"quorum-reached"`
+	// Combined weight of the validators whose signatures have been collected so far
+	SignedWeight uint64 `json:"signed-weight"`
+	// Total weight of the signing subnet's validator set
+	TotalWeight uint64 `json:"total-weight"`
+	// hex encoding of the signed message. Set if the job succeeded.
+	SignedMessage string `json:"signed-message,omitempty"`
+	// Set if the job failed
+	Error string `json:"error,omitempty"`
+	// Set if the job failed due to insufficient connected or signed stake weight
+	Details *AggregationErrorDetails `json:"details,omitempty"`
+}
+
+type job struct {
+	id            string
+	callbackURL   string
+	lock          sync.Mutex
+	status        JobStatus
+	progress      aggregator.Progress
+	signedMsg     *avalancheWarp.Message
+	quorumReached bool
+	err           error
+	finishedAt    time.Time
+}
+
+func (j *job) setProgress(progress aggregator.Progress) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.progress = progress
+}
+
+func (j *job) finish(signedMsg *avalancheWarp.Message, quorumReached bool, err error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.signedMsg = signedMsg
+	j.quorumReached = quorumReached
+	j.err = err
+	j.status = JobSucceeded
+	if err != nil {
+		j.status = JobFailed
+	}
+	j.finishedAt = time.Now()
+}
+
+func (j *job) response() AggregateSignatureJobResponse {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	resp := AggregateSignatureJobResponse{
+		JobID:         j.id,
+		Status:        j.status,
+		Attempt:       j.progress.Attempt,
+		QuorumReached: j.quorumReached,
+		SignedWeight:  j.progress.SignedWeight,
+		TotalWeight:   j.progress.TotalWeight,
+	}
+	if j.signedMsg != nil {
+		resp.SignedMessage = hex.EncodeToString(j.signedMsg.Bytes())
+	}
+	if j.err != nil {
+		resp.Error = "Failed to aggregate signatures: " + j.err.Error()
+		resp.Details = aggregationErrorDetails(j.err)
+	}
+	return resp
+}
+
+// JobStore holds signature aggregation jobs in memory. Jobs are lost when the process restarts.
+type JobStore struct {
+	lock      sync.Mutex
+	jobs      map[string]*job
+	maxJobs   int
+	timeout   time.Duration
+	callbacks *callbackClient
+}
+
+func NewJobStore(cfg *config.JobsConfig) *JobStore {
+	return &JobStore{
+		jobs:      make(map[string]*job),
+		maxJobs:   cfg.GetMaxJobs(),
+		timeout:   cfg.GetTimeout(),
+		callbacks: newCallbackClient(cfg.CallbackAllowedHosts),
+	}
+}
+
+// add registers a new pending job, and discards finished jobs that are past their retention period. If the
+// store is full, the oldest finished job is discarded, or errTooManyJobs is returned if every job is pending.
+func (s *JobStore) add(callbackURL string) (*job, error) {
+	var idBytes [16]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, err
+	}
+	j := &job{
+		id:          hex.EncodeToString(idBytes[:]),
+		callbackURL: callbackURL,
+		status:      JobPending,
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		oldestID       string
+		oldestFinished time.Time
+	)
+	for id, existing := range s.jobs {
+		existing.lock.Lock()
+		finished := existing.status != JobPending
+		finishedAt := existing.finishedAt
+		existing.lock.Unlock()
+		if !finished {
+			continue
+		}
+		if time.Since(finishedAt) > jobRetention {
+			delete(s.jobs, id)
+		} else if oldestID == "" || finishedAt.Before(oldestFinished) {
+			oldestID, oldestFinished = id, finishedAt
+		}
+	}
+	if len(s.jobs) >= s.maxJobs {
+		if oldestID == "" {
+			return nil, errTooManyJobs
+		}
+		delete(s.jobs, oldestID)
+	}
+	s.jobs[j.id] = j
+	return j, nil
+}
+
+func (s *JobStore) get(id string) (*job, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	j, ok := s.jobs[id]
+	return j, ok
+}
+
+func HandleAggregateSignatureJobRequests(
+	logger logging.Logger,
+	metrics *metrics.SignatureAggregatorMetrics,
+	signatureAggregator *aggregator.SignatureAggregator,
+	jobs *JobStore,
+) {
+	http.Handle(
+		http.MethodPost+" "+JobsAPIPath,
+		submitJobAPIHandler(
+			logger,
+			metrics,
+			signatureAggregator,
+			jobs,
+		),
+	)
+	http.Handle(
+		http.MethodGet+" "+JobsAPIPath+"/{id}",
+		getJobAPIHandler(
+			logger,
+			jobs,
+		),
+	)
+}
+
+func submitJobAPIHandler(
+	logger logging.Logger,
+	metrics *metrics.SignatureAggregatorMetrics,
+	signatureAggregator *aggregator.SignatureAggregator,
+	jobs *JobStore,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metrics.AggregateSignaturesRequestCount.Inc()
+
+		var req AggregateSignatureJobRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			msg := "Could not decode request body"
+			logger.Warn(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusBadRequest, msg)
+			return
+		}
+		message, justification, err := decodeMessage(logger, req.Message, req.Justification)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
+			return
+		}
+		signingSubnetID, quorumPercentage, err := parseSigningParameters(
+			logger,
+			req.SigningSubnetID,
+			req.QuorumPercentage,
+			req.RetryPolicy,
+		)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
+			return
+		}
+		signatures, err := decodeSignatures(logger, req.Signatures)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
+			return
+		}
+		destinationBlockchainID, err := parseDestinationBlockchainID(logger, req.DestinationBlockchainID)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pChainHeight, err := resolvePChainHeight(
+			r.Context(),
+			signatureAggregator,
+			req.PChainHeight,
+			destinationBlockchainID,
+		)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if req.CallbackURL != "" {
+			if err := jobs.callbacks.validate(r.Context(), req.CallbackURL); err != nil {
+				msg := "Invalid callback URL"
+				logger.Warn(msg, zap.String("callbackURL", req.CallbackURL), zap.Error(err))
+				writeJSONError(logger, w, http.StatusBadRequest, msg)
+				return
+			}
+		}
+
+		j, err := jobs.add(req.CallbackURL)
+		if errors.Is(err, errTooManyJobs) {
+			msg := "Too many pending jobs"
+			logger.Warn(msg)
+			writeJSONError(logger, w, http.StatusServiceUnavailable, msg)
+			return
+		}
+		if err != nil {
+			msg := "Failed to create job"
+			logger.Error(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusInternalServerError, msg)
+			return
+		}
+		logger.Info(
+			"Submitted signature aggregation job",
+			zap.String("jobID", j.id),
+			zap.String("warpMessageID", message.ID().String()),
+		)
+		// The job outlives the request, so it must not inherit the request's context. It holds the
+		// client's concurrency slot until it finishes.
+		go runJob(
+			logger,
+			metrics,
+			signatureAggregator,
+			jobs,
+			j,
+			auth.Detach(r.Context()),
+			message,
+			justification,
+			signatures,
+			signingSubnetID,
+			pChainHeight,
+			quorumPercentage,
+			req.RetryPolicy,
+			req.BestEffort,
+		)
+
+		resp, err := json.Marshal(
+			AggregateSignatureJobSubmitResponse{
+				JobID: j.id,
+			},
+		)
+		if err != nil {
+			msg := "Failed to marshal response"
+			logger.Error(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusInternalServerError, msg)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, err = w.Write(resp)
+		if err != nil {
+			logger.Error("Error writing response", zap.Error(err))
+		}
+	})
+}
+
+func getJobAPIHandler(
+	logger logging.Logger,
+	jobs *JobStore,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		j, ok := jobs.get(r.PathValue("id"))
+		if !ok {
+			writeJSONError(logger, w, http.StatusNotFound, "Job not found")
+			return
+		}
+		resp, err := json.Marshal(j.response())
+		if err != nil {
+			msg := "Failed to marshal response"
+			logger.Error(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusInternalServerError, msg)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(resp)
+		if err != nil {
+			logger.Error("Error writing response", zap.Error(err))
+		}
+	})
+}
+
+func runJob(
+	logger logging.Logger,
+	metrics *metrics.SignatureAggregatorMetrics,
+	signatureAggregator *aggregator.SignatureAggregator,
+	jobs *JobStore,
+	j *job,
+	release func(),
+	message *avalancheWarp.UnsignedMessage,
+	justification []byte,
+	signatures []aggregator.ValidatorSignature,
+	signingSubnetID ids.ID,
+	pChainHeight uint64,
+	quorumPercentage uint64,
+	retryPolicy *basecfg.RetryPolicy,
+	bestEffort bool,
+) {
+	defer release()
+
+	startTime := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), jobs.timeout)
+	defer cancel()
+	ctx = aggregator.WithProgress(ctx, j.setProgress)
+	if bestEffort {
+		ctx = aggregator.WithBestEffort(ctx)
+	}
+	signedMessage, err := createSignedMessage(
+		ctx,
+		signatureAggregator,
+		message,
+		justification,
+		signatures,
+		signingSubnetID,
+		pChainHeight,
+		quorumPercentage,
+		retryPolicy,
+	)
+	quorumReached := err == nil
+	if err != nil && bestEffort {
+		if partialMessage := partialSignedMessage(err); partialMessage != nil {
+			signedMessage, err = partialMessage, nil
+		}
+	}
+	j.finish(signedMessage, quorumReached, err)
+	if err != nil {
+		logger.Warn(
+			"Failed to aggregate signatures",
+			zap.String("jobID", j.id),
+			zap.Error(err),
+		)
+		return
+	}
+	metrics.AggregateSignaturesLatencyMS.Set(
+		float64(time.Since(startTime).Milliseconds()),
+	)
+	if j.callbackURL != "" {
+		postCallback(logger, jobs.callbacks, j)
+	}
+}
+
+// postCallback POSTs the final status of the job to its callback URL. Failures are logged,
+// since the job's status can still be polled.
+func postCallback(logger logging.Logger, callbacks *callbackClient, j *job) {
+	body, err := json.Marshal(j.response())
+	if err != nil {
+		logger.Error("Failed to marshal callback body", zap.String("jobID", j.id), zap.Error(err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), callbackTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.callbackURL, bytes.NewReader(body))
+	if err != nil {
+		logger.Error("Failed to create callback request", zap.String("jobID", j.id), zap.Error(err))
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := callbacks.client.Do(req)
+	if err != nil {
+		logger.Warn(
+			"Failed to deliver job callback",
+			zap.String("jobID", j.id),
+			zap.String("callbackURL", j.callbackURL),
+			zap.Error(err),
+		)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logger.Warn(
+			"Job callback rejected",
+			zap.String("jobID", j.id),
+			zap.String("callbackURL", j.callbackURL),
+			zap.Int("statusCode", resp.StatusCode),
+		)
+	}
+}
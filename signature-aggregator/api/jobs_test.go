@@ -0,0 +1,49 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/awm-relayer/signature-aggregator/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobStoreLimit(t *testing.T) {
+	jobs := NewJobStore(&config.JobsConfig{MaxJobs: 2})
+
+	first, err := jobs.add("")
+	require.NoError(t, err)
+	second, err := jobs.add("")
+	require.NoError(t, err)
+
+	// Every job is pending, so no more can be added
+	_, err = jobs.add("")
+	require.ErrorIs(t, err, errTooManyJobs)
+
+	// Once jobs finish, the oldest is discarded to make room
+	first.finish(nil, false, errors.New("failed"))
+	second.finish(nil, false, errors.New("failed"))
+	third, err := jobs.add("")
+	require.NoError(t, err)
+	_, ok := jobs.get(first.id)
+	require.False(t, ok)
+	_, ok = jobs.get(second.id)
+	require.True(t, ok)
+	_, ok = jobs.get(third.id)
+	require.True(t, ok)
+
+	// Finished jobs past their retention period are discarded
+	second.lock.Lock()
+	second.finishedAt = time.Now().Add(-2 * jobRetention)
+	second.lock.Unlock()
+	fourth, err := jobs.add("")
+	require.NoError(t, err)
+	_, ok = jobs.get(second.id)
+	require.False(t, ok)
+	_, ok = jobs.get(fourth.id)
+	require.True(t, ok)
+}
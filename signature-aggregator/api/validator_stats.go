@@ -0,0 +1,94 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
+	"go.uber.org/zap"
+)
+
+const ValidatorStatsAPIPath = "/validator-stats"
+
+// Observed reliability of a single validator node
+type ValidatorStats struct {
+	NodeID            string  `json:"node-id"`
+	Requests          uint64  `json:"requests"`
+	Successes         uint64  `json:"successes"`
+	InvalidSignatures uint64  `json:"invalid-signatures"`
+	EmptySignatures   uint64  `json:"empty-signatures"`
+	Timeouts          uint64  `json:"timeouts"`
+	SendFailures      uint64  `json:"send-failures"`
+	SuccessRate       float64 `json:"success-rate"`
+	// Moving average of the response latency, in milliseconds
+	LatencyMs int64 `json:"latency-ms"`
+	// Set if the node is temporarily excluded from signature requests after repeated failures
+	ExcludedUntil *time.Time `json:"excluded-until,omitempty"`
+}
+
+type ValidatorStatsResponse struct {
+	Validators []ValidatorStats `json:"validators"`
+}
+
+func HandleValidatorStatsRequest(
+	logger logging.Logger,
+	signatureAggregator *aggregator.SignatureAggregator,
+) {
+	http.Handle(
+		ValidatorStatsAPIPath,
+		validatorStatsAPIHandler(
+			logger,
+			signatureAggregator,
+		),
+	)
+}
+
+func validatorStatsAPIHandler(
+	logger logging.Logger,
+	aggregator *aggregator.SignatureAggregator,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reliability := aggregator.ValidatorReliability()
+		validators := make([]ValidatorStats, 0, len(reliability))
+		for _, node := range reliability {
+			stats := ValidatorStats{
+				NodeID:            node.NodeID.String(),
+				Requests:          node.Requests,
+				Successes:         node.Successes,
+				InvalidSignatures: node.InvalidSignatures,
+				EmptySignatures:   node.EmptySignatures,
+				Timeouts:          node.Timeouts,
+				SendFailures:      node.SendFailures,
+				SuccessRate:       node.SuccessRate,
+				LatencyMs:         node.Latency.Milliseconds(),
+			}
+			if !node.ExcludedUntil.IsZero() {
+				excludedUntil := node.ExcludedUntil
+				stats.ExcludedUntil = &excludedUntil
+			}
+			validators = append(validators, stats)
+		}
+
+		resp, err := json.Marshal(
+			ValidatorStatsResponse{
+				Validators: validators,
+			},
+		)
+		if err != nil {
+			msg := "Failed to marshal response"
+			logger.Error(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusInternalServerError, msg)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(resp)
+		if err != nil {
+			logger.Error("Error writing response", zap.Error(err))
+		}
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
+	"github.com/ava-labs/awm-relayer/utils"
+	"go.uber.org/zap"
+)
+
+const VerifyAPIPath = "/verify"
+
+// Defines a request to verify the signature of a signed Warp message
+type VerifyRequest struct {
+	// Required. hex-encoded signed message, optionally prefixed with "0x".
+	SignedMessage string `json:"signed-message"`
+	// Optional hex or cb58 encoded signing subnet ID. If omitted will default to the subnetID of the source blockchain
+	SigningSubnetID string `json:"signing-subnet-id"`
+	// Optional. Integer from 0 to 100 representing the percentage of the validator set's weight that must have
+	// signed the message. Defaults to 67 if omitted.
+	QuorumPercentage uint64 `json:"quorum-percentage"`
+	// Optional. P-Chain height of the validator set to verify the signature against. Defaults to the current height.
+	PChainHeight uint64 `json:"p-chain-height"`
+}
+
+type VerifyResponse struct {
+	// True if the signature is valid and reaches the requested quorum
+	Verified bool `json:"verified"`
+	// True if the aggregate signature is valid for the validators that it claims signed the message
+	Valid bool `json:"valid"`
+	// True if the signers hold at least the requested quorum percentage of the validator set's weight
+	QuorumReached bool   `json:"quorum-reached"`
+	SignedWeight  uint64 `json:"signed-weight"`
+	TotalWeight   uint64 `json:"total-weight"`
+	// The signing subnet and P-Chain height whose validator set the signature was verified against
+	SigningSubnetID string `json:"signing-subnet-id"`
+	PChainHeight    uint64 `json:"p-chain-height"`
+	// Set if the signature is not valid
+	Reason string `json:"reason,omitempty"`
+}
+
+func HandleVerifyRequest(
+	logger logging.Logger,
+	pChainState validators.State,
+) {
+	http.Handle(
+		"POST "+VerifyAPIPath,
+		verifyAPIHandler(
+			logger,
+			pChainState,
+		),
+	)
+}
+
+func verifyAPIHandler(
+	logger logging.Logger,
+	pChainState validators.State,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req VerifyRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			msg := "Could not decode request body"
+			logger.Warn(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusBadRequest, msg)
+			return
+		}
+		messageBytes, err := hex.DecodeString(utils.SanitizeHexString(req.SignedMessage))
+		if err != nil {
+			msg := "Could not decode signed message"
+			logger.Warn(msg, zap.String("signedMessage", req.SignedMessage), zap.Error(err))
+			writeJSONError(logger, w, http.StatusBadRequest, msg)
+			return
+		}
+		signedMessage, err := avalancheWarp.ParseMessage(messageBytes)
+		if err != nil {
+			msg := "Error parsing signed warp message"
+			logger.Warn(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusBadRequest, msg)
+			return
+		}
+		signingSubnetID, quorumPercentage, err := parseSigningParameters(
+			logger,
+			req.SigningSubnetID,
+			req.QuorumPercentage,
+			nil,
+		)
+		if err != nil {
+			writeJSONError(logger, w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		result, err := aggregator.VerifySignedMessage(
+			r.Context(),
+			pChainState,
+			signedMessage,
+			signingSubnetID,
+			req.PChainHeight,
+			quorumPercentage,
+		)
+		if err != nil {
+			msg := "Failed to get validator set"
+			logger.Warn(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusInternalServerError, msg)
+			return
+		}
+		response := VerifyResponse{
+			Verified:        result.Verified(),
+			Valid:           result.Valid,
+			QuorumReached:   result.QuorumReached,
+			SignedWeight:    result.SignedWeight,
+			TotalWeight:     result.TotalWeight,
+			SigningSubnetID: result.SigningSubnetID.String(),
+			PChainHeight:    result.PChainHeight,
+		}
+		if result.Reason != nil {
+			response.Reason = result.Reason.Error()
+		}
+		resp, err := json.Marshal(response)
+		if err != nil {
+			msg := "Failed to marshal response"
+			logger.Error(msg, zap.Error(err))
+			writeJSONError(logger, w, http.StatusInternalServerError, msg)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(resp)
+		if err != nil {
+			logger.Error("Error writing response", zap.Error(err))
+		}
+	})
+}
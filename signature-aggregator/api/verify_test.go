@@ -0,0 +1,173 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/snow/validators/validatorstest"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a P-Chain state serving [validator] with node ID [nodeID] as the only validator of [subnetID]
+// at [height]
+func newTestPChainState(
+	subnetID ids.ID,
+	height uint64,
+	validator *avalancheWarp.Validator,
+	nodeID ids.NodeID,
+) *validatorstest.State {
+	return &validatorstest.State{
+		GetSubnetIDF: func(context.Context, ids.ID) (ids.ID, error) {
+			return subnetID, nil
+		},
+		GetCurrentHeightF: func(context.Context) (uint64, error) {
+			return height, nil
+		},
+		GetValidatorSetF: func(
+			_ context.Context,
+			requestedHeight uint64,
+			requestedSubnetID ids.ID,
+		) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			if requestedHeight != height || requestedSubnetID != subnetID {
+				return nil, errors.New("unknown validator set")
+			}
+			return map[ids.NodeID]*validators.GetValidatorOutput{
+				nodeID: {
+					NodeID:    nodeID,
+					PublicKey: validator.PublicKey,
+					Weight:    validator.Weight,
+				},
+			}, nil
+		},
+	}
+}
+
+func TestVerifyHandler(t *testing.T) {
+	var (
+		subnetID = ids.GenerateTestID()
+		height   = uint64(10)
+	)
+	validatorSet, secretKeys := makeValidators(t, 1)
+	validator := validatorSet.ValidatorSet[0]
+	nodeID := validator.NodeIDs[0]
+	handler := verifyAPIHandler(logging.NoLog{}, newTestPChainState(subnetID, height, validator, nodeID))
+
+	unsignedMessage := newUnsignedMessage(t, ids.GenerateTestID())
+	signature := bls.Sign(secretKeys[nodeID], unsignedMessage.Bytes())
+	signers := set.NewBits(0)
+	bitSetSignature := &avalancheWarp.BitSetSignature{Signers: signers.Bytes()}
+	copy(bitSetSignature.Signature[:], bls.SignatureToBytes(signature))
+	signedMessage, err := avalancheWarp.NewMessage(unsignedMessage, bitSetSignature)
+	require.NoError(t, err)
+
+	// A message signed by the whole validator set is verified at the current height
+	w := postJSON(t, handler, VerifyAPIPath, VerifyRequest{
+		SignedMessage: hex.EncodeToString(signedMessage.Bytes()),
+	}, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp VerifyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.True(t, resp.Verified)
+	require.Equal(t, uint64(1), resp.SignedWeight)
+	require.Equal(t, subnetID.String(), resp.SigningSubnetID)
+	require.Equal(t, height, resp.PChainHeight)
+	require.Empty(t, resp.Reason)
+
+	// A message signed by another key is reported as invalid, rather than failing the request
+	otherSecretKey, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	invalidSignature := &avalancheWarp.BitSetSignature{Signers: signers.Bytes()}
+	copy(invalidSignature.Signature[:], bls.SignatureToBytes(bls.Sign(otherSecretKey, unsignedMessage.Bytes())))
+	invalidMessage, err := avalancheWarp.NewMessage(unsignedMessage, invalidSignature)
+	require.NoError(t, err)
+	w = postJSON(t, handler, VerifyAPIPath, VerifyRequest{
+		SignedMessage: "0x" + hex.EncodeToString(invalidMessage.Bytes()),
+	}, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	resp = VerifyResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.False(t, resp.Verified)
+	require.False(t, resp.Valid)
+	require.True(t, resp.QuorumReached)
+	require.NotEmpty(t, resp.Reason)
+
+	// Failing to fetch the validator set is an internal error
+	w = postJSON(t, handler, VerifyAPIPath, VerifyRequest{
+		SignedMessage: hex.EncodeToString(signedMessage.Bytes()),
+		PChainHeight:  height + 1,
+	}, nil)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestVerifyHandlerRejectsMalformedRequests(t *testing.T) {
+	handler := verifyAPIHandler(logging.NoLog{}, &validatorstest.State{T: t})
+	signedMessage, err := avalancheWarp.NewMessage(
+		newUnsignedMessage(t, ids.GenerateTestID()),
+		&avalancheWarp.BitSetSignature{},
+	)
+	require.NoError(t, err)
+	signedMessageHex := hex.EncodeToString(signedMessage.Bytes())
+
+	testCases := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{
+			name: "invalid JSON",
+			body: `{"signed-message":`,
+			err:  "Could not decode request body",
+		},
+		{
+			name: "invalid hex",
+			body: `{"signed-message":"0xzz"}`,
+			err:  "Could not decode signed message",
+		},
+		{
+			name: "missing message",
+			body: `{}`,
+			err:  "Error parsing signed warp message",
+		},
+		{
+			name: "unsigned message",
+			body: `{"signed-message":"` + hex.EncodeToString(newUnsignedMessage(t, ids.Empty).Bytes()) + `"}`,
+			err:  "Error parsing signed warp message",
+		},
+		{
+			name: "invalid signing subnet",
+			body: `{"signed-message":"` + signedMessageHex + `","signing-subnet-id":"invalid"}`,
+			err:  "Error parsing signing subnet ID",
+		},
+		{
+			name: "invalid quorum percentage",
+			body: `{"signed-message":"` + signedMessageHex + `","quorum-percentage":101}`,
+			err:  "Invalid quorum number",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, VerifyAPIPath, strings.NewReader(testCase.body))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			var resp AggregateSignatureErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Equal(t, testCase.err, resp.Error)
+		})
+	}
+}
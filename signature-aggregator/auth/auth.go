@@ -0,0 +1,268 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/config"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// HTTP header carrying the client's API key. The key may alternatively be provided as a bearer token
+	// in the Authorization header.
+	APIKeyHeader = "X-API-Key"
+	// gRPC metadata key carrying the client's API key
+	APIKeyMetadataKey = "x-api-key"
+
+	unauthenticatedClient = "unauthenticated"
+
+	resultAdmitted           = "admitted"
+	resultUnauthorized       = "unauthorized"
+	resultRateLimited        = "rate_limited"
+	resultConcurrencyLimited = "concurrency_limited"
+)
+
+var (
+	// Returned by Charge if a request fans out to more aggregations than the client's burst, and so would
+	// never be admitted by its rate limit
+	ErrBurstExceeded = errors.New("request exceeds the client's rate limit burst")
+
+	errUnauthorized       = errors.New("missing or invalid API key")
+	errRateLimited        = errors.New("rate limit exceeded")
+	errConcurrencyLimited = errors.New("too many concurrent requests")
+)
+
+type client struct {
+	name    string
+	limiter *rate.Limiter
+	// Buffered to the client's maximum number of concurrent requests. Nil if unlimited.
+	inFlight chan struct{}
+}
+
+// A request admitted on behalf of a client
+type admission struct {
+	client *client
+	// Releases the client's concurrency slot
+	release func()
+	// Set if the slot has been handed over by Detach, and so must not be released once the request is handled
+	detached bool
+}
+
+func (a *admission) done() {
+	if !a.detached {
+		a.release()
+	}
+}
+
+type admissionKey struct{}
+
+// Authenticator authenticates API requests by API key, and enforces each client's rate limit and
+// concurrency cap. If no clients are configured, all requests are admitted.
+type Authenticator struct {
+	logger  logging.Logger
+	metrics *metrics.SignatureAggregatorMetrics
+	// Indexed by the SHA-256 digest of the API key, so that keys are not compared in variable time
+	clients map[[sha256.Size]byte]*client
+}
+
+func NewAuthenticator(
+	logger logging.Logger,
+	metrics *metrics.SignatureAggregatorMetrics,
+	clientConfigs []config.APIClientConfig,
+) *Authenticator {
+	clients := make(map[[sha256.Size]byte]*client, len(clientConfigs))
+	for _, cfg := range clientConfigs {
+		limit := rate.Inf
+		if cfg.RequestsPerSecond > 0 {
+			limit = rate.Limit(cfg.RequestsPerSecond)
+		}
+		c := &client{
+			name:    cfg.Name,
+			limiter: rate.NewLimiter(limit, cfg.GetBurst()),
+		}
+		if cfg.MaxConcurrentRequests > 0 {
+			c.inFlight = make(chan struct{}, cfg.MaxConcurrentRequests)
+		}
+		clients[sha256.Sum256([]byte(cfg.APIKey))] = c
+	}
+	return &Authenticator{
+		logger:  logger,
+		metrics: metrics,
+		clients: clients,
+	}
+}
+
+func (a *Authenticator) enabled() bool {
+	return len(a.clients) != 0
+}
+
+// admit authenticates [apiKey], and reserves a single request from the client's rate limit and a
+// concurrency slot. The returned function must be called to release the slot once the request is handled.
+func (a *Authenticator) admit(apiKey string) (*client, func(), error) {
+	c, ok := a.clients[sha256.Sum256([]byte(apiKey))]
+	if !ok || apiKey == "" {
+		a.metrics.ClientRequests.WithLabelValues(unauthenticatedClient, resultUnauthorized).Inc()
+		return nil, nil, errUnauthorized
+	}
+	if !c.limiter.Allow() {
+		a.metrics.ClientRequests.WithLabelValues(c.name, resultRateLimited).Inc()
+		return nil, nil, errRateLimited
+	}
+	if c.inFlight != nil {
+		select {
+		case c.inFlight <- struct{}{}:
+		default:
+			a.metrics.ClientRequests.WithLabelValues(c.name, resultConcurrencyLimited).Inc()
+			return nil, nil, errConcurrencyLimited
+		}
+	}
+	a.metrics.ClientRequests.WithLabelValues(c.name, resultAdmitted).Inc()
+	a.metrics.ClientInFlightRequests.WithLabelValues(c.name).Inc()
+	release := func() {
+		a.metrics.ClientInFlightRequests.WithLabelValues(c.name).Dec()
+		if c.inFlight != nil {
+			<-c.inFlight
+		}
+	}
+	return c, release, nil
+}
+
+// Charge consumes [n] additional requests from the rate limit of the client that made the request
+// associated with [ctx], for requests that fan out to multiple signature aggregations. Returns
+// ErrBurstExceeded if the request and its [n] additional requests exceed the client's burst, since they
+// could never be admitted, or an error if the client's rate limit would otherwise be exceeded. Always
+// succeeds if authentication is disabled.
+func Charge(ctx context.Context, n int) error {
+	adm, ok := ctx.Value(admissionKey{}).(*admission)
+	if !ok || n <= 0 {
+		return nil
+	}
+	limiter := adm.client.limiter
+	if limiter.Limit() != rate.Inf && n+1 > limiter.Burst() {
+		return ErrBurstExceeded
+	}
+	if !limiter.AllowN(time.Now(), n) {
+		return errRateLimited
+	}
+	return nil
+}
+
+// Detach hands over the concurrency slot of the request associated with [ctx] to the caller, for
+// requests that start work which outlives them, such as asynchronous jobs. The slot is then held until
+// the returned function is called, rather than until the request is handled. Returns a no-op function
+// if authentication is disabled.
+func Detach(ctx context.Context) func() {
+	adm, ok := ctx.Value(admissionKey{}).(*admission)
+	if !ok {
+		return func() {}
+	}
+	adm.detached = true
+	return adm.release
+}
+
+// HTTPMiddleware wraps [next] so that requests are admitted only if they present a valid API key, and
+// are within the client's limits. Requests to [exemptPaths] are always admitted.
+func (a *Authenticator) HTTPMiddleware(next http.Handler, exemptPaths ...string) http.Handler {
+	if !a.enabled() {
+		return next
+	}
+	exempt := make(map[string]struct{}, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = struct{}{}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := exempt[r.URL.Path]; ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		c, release, err := a.admit(httpAPIKey(r))
+		if err != nil {
+			a.logger.Debug("Rejected API request", zap.String("path", r.URL.Path), zap.Error(err))
+			writeJSONError(w, httpStatus(err), err.Error())
+			return
+		}
+		adm := &admission{client: c, release: release}
+		defer adm.done()
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), admissionKey{}, adm)))
+	})
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor that applies the same checks as HTTPMiddleware
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !a.enabled() {
+			return handler(ctx, req)
+		}
+		var apiKey string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(APIKeyMetadataKey); len(values) > 0 {
+				apiKey = values[0]
+			}
+		}
+		c, release, err := a.admit(apiKey)
+		if err != nil {
+			a.logger.Debug("Rejected API request", zap.String("method", info.FullMethod), zap.Error(err))
+			return nil, status.Error(grpcCode(err), err.Error())
+		}
+		adm := &admission{client: c, release: release}
+		defer adm.done()
+		return handler(context.WithValue(ctx, admissionKey{}, adm), req)
+	}
+}
+
+func httpAPIKey(r *http.Request) string {
+	if apiKey := r.Header.Get(APIKeyHeader); apiKey != "" {
+		return apiKey
+	}
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// Writes an error response with the same format as the API's other error responses
+func writeJSONError(w http.ResponseWriter, httpStatusCode int, errorMsg string) {
+	resp, err := json.Marshal(struct {
+		Error string `json:"error"`
+	}{
+		Error: errorMsg,
+	})
+	if err != nil {
+		resp = []byte(errorMsg)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatusCode)
+	_, _ = w.Write(resp)
+}
+
+func httpStatus(err error) int {
+	if errors.Is(err, errUnauthorized) {
+		return http.StatusUnauthorized
+	}
+	return http.StatusTooManyRequests
+}
+
+func grpcCode(err error) codes.Code {
+	if errors.Is(err, errUnauthorized) {
+		return codes.Unauthenticated
+	}
+	return codes.ResourceExhausted
+}
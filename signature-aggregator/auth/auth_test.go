@@ -0,0 +1,172 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/config"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAPIKey     = "test-api-key"
+	testClientName = "test-client"
+)
+
+func newTestAuthenticator(
+	clientConfigs ...config.APIClientConfig,
+) (*Authenticator, *metrics.SignatureAggregatorMetrics) {
+	m := metrics.NewSignatureAggregatorMetrics(prometheus.NewRegistry())
+	return NewAuthenticator(logging.NoLog{}, m, clientConfigs), m
+}
+
+func serve(handler http.Handler, path string, apiKey string) int {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if apiKey != "" {
+		req.Header.Set(APIKeyHeader, apiKey)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestHTTPMiddlewareDisabledWithoutClients(t *testing.T) {
+	authenticator, _ := newTestAuthenticator()
+	handler := authenticator.HTTPMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	require.Equal(t, http.StatusOK, serve(handler, "/aggregate-signatures", ""))
+}
+
+func TestHTTPMiddlewareAuthenticates(t *testing.T) {
+	authenticator, m := newTestAuthenticator(config.APIClientConfig{
+		Name:   testClientName,
+		APIKey: testAPIKey,
+	})
+	handler := authenticator.HTTPMiddleware(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+		"/health",
+	)
+
+	require.Equal(t, http.StatusUnauthorized, serve(handler, "/aggregate-signatures", ""))
+	require.Equal(t, http.StatusUnauthorized, serve(handler, "/aggregate-signatures", "wrong-key"))
+	require.Equal(t, http.StatusOK, serve(handler, "/health", ""))
+	require.Equal(t, http.StatusOK, serve(handler, "/aggregate-signatures", testAPIKey))
+
+	// The key may also be provided as a bearer token
+	req := httptest.NewRequest(http.MethodPost, "/aggregate-signatures", nil)
+	req.Header.Set("Authorization", "Bearer "+testAPIKey)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(
+		m.ClientRequests.WithLabelValues(unauthenticatedClient, resultUnauthorized),
+	))
+	require.Equal(t, 2.0, testutil.ToFloat64(m.ClientRequests.WithLabelValues(testClientName, resultAdmitted)))
+}
+
+func TestHTTPMiddlewareRateLimits(t *testing.T) {
+	authenticator, m := newTestAuthenticator(config.APIClientConfig{
+		Name:              testClientName,
+		APIKey:            testAPIKey,
+		RequestsPerSecond: 0.001,
+		Burst:             2,
+	})
+	handler := authenticator.HTTPMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	require.Equal(t, http.StatusOK, serve(handler, "/aggregate-signatures", testAPIKey))
+	require.Equal(t, http.StatusOK, serve(handler, "/aggregate-signatures", testAPIKey))
+	require.Equal(t, http.StatusTooManyRequests, serve(handler, "/aggregate-signatures", testAPIKey))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.ClientRequests.WithLabelValues(testClientName, resultRateLimited)))
+}
+
+func TestHTTPMiddlewareChargesBatches(t *testing.T) {
+	authenticator, _ := newTestAuthenticator(config.APIClientConfig{
+		Name:              testClientName,
+		APIKey:            testAPIKey,
+		RequestsPerSecond: 0.001,
+		Burst:             3,
+	})
+	var charged []error
+	handler := authenticator.HTTPMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		charged = append(charged, Charge(r.Context(), 2))
+	}))
+
+	// The first request consumes one token, and its batch the remaining two
+	require.Equal(t, http.StatusOK, serve(handler, "/aggregate-signatures/batch", testAPIKey))
+	require.Equal(t, []error{nil}, charged)
+	require.Equal(t, http.StatusTooManyRequests, serve(handler, "/aggregate-signatures/batch", testAPIKey))
+}
+
+func TestChargeRejectsBatchesExceedingBurst(t *testing.T) {
+	authenticator, _ := newTestAuthenticator(config.APIClientConfig{
+		Name:              testClientName,
+		APIKey:            testAPIKey,
+		RequestsPerSecond: 1000,
+		Burst:             3,
+	})
+	var charged []error
+	handler := authenticator.HTTPMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		charged = append(charged, Charge(r.Context(), 3))
+	}))
+
+	// A batch of four messages can never fit in a burst of three, however long the client waits
+	require.Equal(t, http.StatusOK, serve(handler, "/aggregate-signatures/batch", testAPIKey))
+	require.Len(t, charged, 1)
+	require.ErrorIs(t, charged[0], ErrBurstExceeded)
+}
+
+func TestHTTPMiddlewareLimitsConcurrency(t *testing.T) {
+	authenticator, m := newTestAuthenticator(config.APIClientConfig{
+		Name:                  testClientName,
+		APIKey:                testAPIKey,
+		MaxConcurrentRequests: 1,
+	})
+	var nestedCode int
+	var handler http.Handler
+	handler = authenticator.HTTPMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		// A second request while the first is still being handled is rejected
+		if r.URL.Path == "/outer" {
+			require.Equal(t, 1.0, testutil.ToFloat64(m.ClientInFlightRequests.WithLabelValues(testClientName)))
+			nestedCode = serve(handler, "/inner", testAPIKey)
+		}
+	}))
+
+	require.Equal(t, http.StatusOK, serve(handler, "/outer", testAPIKey))
+	require.Equal(t, http.StatusTooManyRequests, nestedCode)
+	require.Equal(t, 0.0, testutil.ToFloat64(m.ClientInFlightRequests.WithLabelValues(testClientName)))
+
+	// The slot is released once the request completes
+	require.Equal(t, http.StatusOK, serve(handler, "/inner", testAPIKey))
+}
+
+func TestDetachHoldsConcurrencySlot(t *testing.T) {
+	authenticator, m := newTestAuthenticator(config.APIClientConfig{
+		Name:                  testClientName,
+		APIKey:                testAPIKey,
+		MaxConcurrentRequests: 1,
+	})
+	var release func()
+	handler := authenticator.HTTPMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		if release == nil {
+			release = Detach(r.Context())
+		}
+	}))
+
+	// The detached slot is held after the request completes, until it is released
+	require.Equal(t, http.StatusOK, serve(handler, "/aggregate-signatures/jobs", testAPIKey))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.ClientInFlightRequests.WithLabelValues(testClientName)))
+	require.Equal(t, http.StatusTooManyRequests, serve(handler, "/aggregate-signatures/jobs", testAPIKey))
+
+	release()
+	require.Equal(t, 0.0, testutil.ToFloat64(m.ClientInFlightRequests.WithLabelValues(testClientName)))
+	require.Equal(t, http.StatusOK, serve(handler, "/aggregate-signatures/jobs", testAPIKey))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.ClientInFlightRequests.WithLabelValues(testClientName)))
+}
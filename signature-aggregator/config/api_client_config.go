@@ -0,0 +1,68 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// API client configuration. If any clients are configured, every API request must present the API key
+// of one of the clients, and is subject to that client's limits.
+type APIClientConfig struct {
+	// Name used to identify the client in logs and metrics
+	Name   string `mapstructure:"name" json:"name"`
+	APIKey string `mapstructure:"api-key" json:"api-key"`
+	// Sustained rate at which the client may make requests. Each message in a batch request counts as
+	// a request. Unlimited if zero.
+	RequestsPerSecond float64 `mapstructure:"requests-per-second" json:"requests-per-second"`
+	// Maximum number of requests the client may make in a burst. Defaults to RequestsPerSecond, rounded up.
+	// Batch requests with more messages than the burst are rejected, since they could never be admitted.
+	Burst uint64 `mapstructure:"burst" json:"burst"`
+	// Maximum number of requests from the client that may be handled concurrently, including asynchronous
+	// jobs until they finish. Unlimited if zero.
+	MaxConcurrentRequests uint64 `mapstructure:"max-concurrent-requests" json:"max-concurrent-requests"`
+}
+
+func (c *APIClientConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("api client name must be provided")
+	}
+	if c.APIKey == "" {
+		return fmt.Errorf("api key must be provided for client %s", c.Name)
+	}
+	if c.RequestsPerSecond < 0 {
+		return fmt.Errorf("invalid requests-per-second for client %s: %f", c.Name, c.RequestsPerSecond)
+	}
+	return nil
+}
+
+// GetBurst returns the configured burst, or the default if omitted
+func (c *APIClientConfig) GetBurst() int {
+	if c.Burst != 0 {
+		return int(c.Burst)
+	}
+	return int(math.Max(1, math.Ceil(c.RequestsPerSecond)))
+}
+
+func validateAPIClients(clients []APIClientConfig) error {
+	names := make(map[string]struct{}, len(clients))
+	keys := make(map[string]struct{}, len(clients))
+	for i := range clients {
+		client := &clients[i]
+		if err := client.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[client.Name]; ok {
+			return fmt.Errorf("duplicate api client name %s", client.Name)
+		}
+		names[client.Name] = struct{}{}
+		if _, ok := keys[client.APIKey]; ok {
+			return fmt.Errorf("api key of client %s is shared with another client", client.Name)
+		}
+		keys[client.APIKey] = struct{}{}
+	}
+	return nil
+}
@@ -15,8 +15,12 @@ import (
 const (
 	defaultAPIPort     = uint16(8080)
 	defaultMetricsPort = uint16(8081)
+	defaultGRPCPort    = uint16(8082)
 
 	DefaultSignatureCacheSize = uint64(1024 * 1024)
+
+	// Overall time budget of an aggregation, unless the signature retry policy sets a shorter one
+	DefaultSignatureRetryDeadlineMs = uint64(60_000)
 )
 
 var defaultLogLevel = logging.Info.String()
@@ -34,8 +38,20 @@ type Config struct {
 	InfoAPI            *basecfg.APIConfig `mapstructure:"info-api" json:"info-api"`
 	APIPort            uint16             `mapstructure:"api-port" json:"api-port"`
 	MetricsPort        uint16             `mapstructure:"metrics-port" json:"metrics-port"`
+	GRPCPort           uint16             `mapstructure:"grpc-port" json:"grpc-port"`
 	SignatureCacheSize uint64             `mapstructure:"signature-cache-size" json:"signature-cache-size"`
 
+	// Caching of the canonical validator sets fetched from the P-Chain. Disabled if omitted.
+	ValidatorSetCache basecfg.ValidatorSetCacheConfig `mapstructure:"validator-set-cache" json:"validator-set-cache"`
+	// Strategy used to select which validators to query for signatures. Either "all" or "weighted".
+	SignatureQueryStrategy string `mapstructure:"signature-query-strategy" json:"signature-query-strategy"`
+	// Bounds the retry policy requested by clients, and is used for requests that don't specify one
+	SignatureRetryPolicy basecfg.RetryPolicy `mapstructure:"signature-retry-policy" json:"signature-retry-policy"`
+	// Limits of the asynchronous signature aggregation jobs API
+	Jobs JobsConfig `mapstructure:"jobs" json:"jobs"`
+	// Clients permitted to use the API. If omitted, the API is open to anyone who can reach it.
+	APIClients []APIClientConfig `mapstructure:"api-clients" json:"api-clients"`
+
 	// mapstructure doesn't support time.Time out of the box so handle it manually
 	EtnaTime time.Time `json:"etna-time"`
 }
@@ -54,6 +70,15 @@ func (c *Config) Validate() error {
 	if err := c.InfoAPI.Validate(); err != nil {
 		return err
 	}
+	if err := c.SignatureRetryPolicy.Validate(); err != nil {
+		return err
+	}
+	if err := c.Jobs.Validate(); err != nil {
+		return err
+	}
+	if err := validateAPIClients(c.APIClients); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -67,3 +92,17 @@ func (c *Config) GetPChainAPI() *basecfg.APIConfig {
 func (c *Config) GetInfoAPI() *basecfg.APIConfig {
 	return c.InfoAPI
 }
+
+func (c *Config) GetValidatorSetCacheConfig() *basecfg.ValidatorSetCacheConfig {
+	return &c.ValidatorSetCache
+}
+
+// GetSignatureRetryPolicy returns the signature retry policy, with a deadline of
+// DefaultSignatureRetryDeadlineMs if none is configured
+func (c *Config) GetSignatureRetryPolicy() *basecfg.RetryPolicy {
+	policy := c.SignatureRetryPolicy
+	if policy.DeadlineMs == 0 {
+		policy.DeadlineMs = DefaultSignatureRetryDeadlineMs
+	}
+	return &policy
+}
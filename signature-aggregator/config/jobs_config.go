@@ -0,0 +1,57 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMaxJobs           = uint64(1000)
+	DefaultJobTimeoutSeconds = uint64(300)
+)
+
+// Configuration of the asynchronous signature aggregation jobs API
+type JobsConfig struct {
+	// Maximum number of jobs held in memory, pending or finished. Once reached, the oldest finished jobs are
+	// discarded to make room for new ones, and new jobs are rejected if every job is still pending.
+	// Defaults to 1000.
+	MaxJobs uint64 `mapstructure:"max-jobs" json:"max-jobs"`
+	// Time after which a pending job is cancelled and fails. Defaults to 300 seconds.
+	TimeoutSeconds uint64 `mapstructure:"timeout-seconds" json:"timeout-seconds"`
+	// Hosts to which job callbacks may be POSTed regardless of the addresses they resolve to. Callbacks to any
+	// other host are only permitted if it resolves exclusively to public addresses.
+	CallbackAllowedHosts []string `mapstructure:"callback-allowed-hosts" json:"callback-allowed-hosts"`
+}
+
+func (c *JobsConfig) Validate() error {
+	for _, host := range c.CallbackAllowedHosts {
+		if host == "" || strings.Contains(host, "/") {
+			return errors.New("jobs callback-allowed-hosts must be host names or IP addresses")
+		}
+		if _, _, err := net.SplitHostPort(host); err == nil {
+			return errors.New("jobs callback-allowed-hosts must not include a port")
+		}
+	}
+	return nil
+}
+
+// GetMaxJobs returns the configured maximum number of jobs, or the default if omitted
+func (c *JobsConfig) GetMaxJobs() int {
+	if c.MaxJobs != 0 {
+		return int(c.MaxJobs)
+	}
+	return int(DefaultMaxJobs)
+}
+
+// GetTimeout returns the configured job timeout, or the default if omitted
+func (c *JobsConfig) GetTimeout() time.Duration {
+	if c.TimeoutSeconds != 0 {
+		return time.Duration(c.TimeoutSeconds) * time.Second
+	}
+	return time.Duration(DefaultJobTimeoutSeconds) * time.Second
+}
@@ -10,11 +10,17 @@ const (
 	HelpKey       = "help"
 
 	// Top-level configuration keys
-	LogLevelKey           = "log-level"
-	PChainAPIKey          = "p-chain-api"
-	InfoAPIKey            = "info-api"
-	APIPortKey            = "api-port"
-	MetricsPortKey        = "metrics-port"
-	SignatureCacheSizeKey = "signature-cache-size"
-	EtnaTimeKey           = "etna-time"
+	LogLevelKey               = "log-level"
+	PChainAPIKey              = "p-chain-api"
+	InfoAPIKey                = "info-api"
+	APIPortKey                = "api-port"
+	MetricsPortKey            = "metrics-port"
+	GRPCPortKey               = "grpc-port"
+	SignatureCacheSizeKey     = "signature-cache-size"
+	ValidatorSetCacheKey      = "validator-set-cache"
+	SignatureQueryStrategyKey = "signature-query-strategy"
+	SignatureRetryPolicyKey   = "signature-retry-policy"
+	JobsKey                   = "jobs"
+	APIClientsKey             = "api-clients"
+	EtnaTimeKey               = "etna-time"
 )
@@ -53,6 +53,7 @@ func SetDefaultConfigValues(v *viper.Viper) {
 	v.SetDefault(LogLevelKey, defaultLogLevel)
 	v.SetDefault(APIPortKey, defaultAPIPort)
 	v.SetDefault(MetricsPortKey, defaultMetricsPort)
+	v.SetDefault(GRPCPortKey, defaultGRPCPort)
 	v.SetDefault(
 		SignatureCacheSizeKey,
 		DefaultSignatureCacheSize,
@@ -7,6 +7,8 @@ import (
 	"github.com/alexliesenfeld/health"
 )
 
+const HealthAPIPath = "/health"
+
 func HandleHealthCheckRequest() {
 	healthChecker := health.NewChecker(
 		health.WithCheck(health.Check{
@@ -15,5 +17,5 @@ func HandleHealthCheckRequest() {
 		}),
 	)
 
-	http.Handle("/health", health.NewHandler(healthChecker))
+	http.Handle(HealthAPIPath, health.NewHandler(healthChecker))
 }
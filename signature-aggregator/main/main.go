@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 
@@ -14,8 +15,10 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/awm-relayer/peers"
+	"github.com/ava-labs/awm-relayer/peers/validators"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/aggregator"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/api"
+	"github.com/ava-labs/awm-relayer/signature-aggregator/auth"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/config"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/healthcheck"
 	"github.com/ava-labs/awm-relayer/signature-aggregator/metrics"
@@ -111,6 +114,11 @@ func main() {
 	registry := metrics.Initialize(cfg.MetricsPort)
 	metricsInstance := metrics.NewSignatureAggregatorMetrics(registry)
 
+	queryStrategy, err := aggregator.ParseQueryStrategy(cfg.SignatureQueryStrategy)
+	if err != nil {
+		logger.Fatal("Invalid signature query strategy", zap.Error(err))
+		panic(err)
+	}
 	signatureAggregator, err := aggregator.NewSignatureAggregator(
 		network,
 		logger,
@@ -118,6 +126,8 @@ func main() {
 		metricsInstance,
 		messageCreator,
 		cfg.EtnaTime,
+		queryStrategy,
+		cfg.GetSignatureRetryPolicy(),
 	)
 	if err != nil {
 		logger.Fatal("Failed to create signature aggregator", zap.Error(err))
@@ -129,10 +139,55 @@ func main() {
 		metricsInstance,
 		signatureAggregator,
 	)
+	api.HandleAggregateSignaturesBatchRequest(
+		logger,
+		metricsInstance,
+		signatureAggregator,
+	)
+	api.HandleAggregateSignatureJobRequests(
+		logger,
+		metricsInstance,
+		signatureAggregator,
+		api.NewJobStore(&cfg.Jobs),
+	)
+	api.HandleValidatorStatsRequest(
+		logger,
+		signatureAggregator,
+	)
+	api.HandleVerifyRequest(
+		logger,
+		validators.NewCanonicalValidatorClient(
+			logger,
+			cfg.GetPChainAPI(),
+			cfg.GetValidatorSetCacheConfig(),
+		),
+	)
 	healthcheck.HandleHealthCheckRequest()
+	authenticator := auth.NewAuthenticator(logger, metricsInstance, cfg.APIClients)
+
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+	if err != nil {
+		logger.Fatal("Failed to listen on gRPC port", zap.Error(err))
+		panic(err)
+	}
+	grpcServer := api.NewGRPCServer(
+		logger,
+		metricsInstance,
+		signatureAggregator,
+		authenticator,
+	)
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.Error("gRPC server error", zap.Error(err))
+			log.Fatal(err)
+		}
+	}()
 
 	logger.Info("Initialization complete")
-	err = http.ListenAndServe(fmt.Sprintf(":%d", cfg.APIPort), nil)
+	err = http.ListenAndServe(
+		fmt.Sprintf(":%d", cfg.APIPort),
+		authenticator.HTTPMiddleware(http.DefaultServeMux, healthcheck.HealthAPIPath),
+	)
 	if errors.Is(err, http.ErrServerClosed) {
 		logger.Info("server closed")
 	} else if err != nil {
@@ -32,6 +32,12 @@ var Opts = struct {
 	SignatureCacheHits                 prometheus.CounterOpts
 	SignatureCacheMisses               prometheus.CounterOpts
 	ConnectedStakeWeightPercentage     prometheus.GaugeOpts
+	ValidatorRequestOutcomes           prometheus.CounterOpts
+	ValidatorResponseLatencyMS         prometheus.GaugeOpts
+	ValidatorExcluded                  prometheus.GaugeOpts
+	ClientRequests                     prometheus.CounterOpts
+	ClientInFlightRequests             prometheus.GaugeOpts
+	CoalescedAggregations              prometheus.CounterOpts
 }{
 	AggregateSignaturesLatencyMS: prometheus.GaugeOpts{
 		Name: "agg_sigs_latency_ms",
@@ -77,6 +83,30 @@ var Opts = struct {
 		Name: "connected_stake_weight_percentage",
 		Help: "The percentage of connected stake weight for a specific subnet",
 	},
+	ValidatorRequestOutcomes: prometheus.CounterOpts{
+		Name: "validator_request_outcomes",
+		Help: "Number of signature requests to a validator node, by outcome",
+	},
+	ValidatorResponseLatencyMS: prometheus.GaugeOpts{
+		Name: "validator_response_latency_ms",
+		Help: "Moving average of the latency of a validator node's responses to signature requests",
+	},
+	ValidatorExcluded: prometheus.GaugeOpts{
+		Name: "validator_excluded",
+		Help: "Whether a validator node is temporarily excluded from signature requests after repeated failures",
+	},
+	ClientRequests: prometheus.CounterOpts{
+		Name: "client_requests",
+		Help: "Number of API requests by client, and whether they were admitted or rejected",
+	},
+	ClientInFlightRequests: prometheus.GaugeOpts{
+		Name: "client_in_flight_requests",
+		Help: "Number of API requests currently being handled for a client",
+	},
+	CoalescedAggregations: prometheus.CounterOpts{
+		Name: "coalesced_aggregations",
+		Help: "Number of signature aggregation requests that shared an aggregation already in progress",
+	},
 }
 
 type SignatureAggregatorMetrics struct {
@@ -91,6 +121,12 @@ type SignatureAggregatorMetrics struct {
 	SignatureCacheHits                 prometheus.Counter
 	SignatureCacheMisses               prometheus.Counter
 	ConnectedStakeWeightPercentage     *prometheus.GaugeVec
+	ValidatorRequestOutcomes           *prometheus.CounterVec
+	ValidatorResponseLatencyMS         *prometheus.GaugeVec
+	ValidatorExcluded                  *prometheus.GaugeVec
+	ClientRequests                     *prometheus.CounterVec
+	ClientInFlightRequests             *prometheus.GaugeVec
+	CoalescedAggregations              prometheus.Counter
 
 	// TODO: consider other failures to monitor. Issue #384 requires
 	// "network failures", but we probably don't handle those directly.
@@ -139,6 +175,29 @@ func NewSignatureAggregatorMetrics(
 			Opts.ConnectedStakeWeightPercentage,
 			[]string{"subnetID"},
 		),
+		ValidatorRequestOutcomes: prometheus.NewCounterVec(
+			Opts.ValidatorRequestOutcomes,
+			[]string{"nodeID", "outcome"},
+		),
+		ValidatorResponseLatencyMS: prometheus.NewGaugeVec(
+			Opts.ValidatorResponseLatencyMS,
+			[]string{"nodeID"},
+		),
+		ValidatorExcluded: prometheus.NewGaugeVec(
+			Opts.ValidatorExcluded,
+			[]string{"nodeID"},
+		),
+		ClientRequests: prometheus.NewCounterVec(
+			Opts.ClientRequests,
+			[]string{"client", "result"},
+		),
+		ClientInFlightRequests: prometheus.NewGaugeVec(
+			Opts.ClientInFlightRequests,
+			[]string{"client"},
+		),
+		CoalescedAggregations: prometheus.NewCounter(
+			Opts.CoalescedAggregations,
+		),
 	}
 
 	registerer.MustRegister(m.AggregateSignaturesLatencyMS)
@@ -152,6 +211,12 @@ func NewSignatureAggregatorMetrics(
 	registerer.MustRegister(m.SignatureCacheHits)
 	registerer.MustRegister(m.SignatureCacheMisses)
 	registerer.MustRegister(m.ConnectedStakeWeightPercentage)
+	registerer.MustRegister(m.ValidatorRequestOutcomes)
+	registerer.MustRegister(m.ValidatorResponseLatencyMS)
+	registerer.MustRegister(m.ValidatorExcluded)
+	registerer.MustRegister(m.ClientRequests)
+	registerer.MustRegister(m.ClientInFlightRequests)
+	registerer.MustRegister(m.CoalescedAggregations)
 
 	return &m
 }
@@ -35,6 +35,8 @@ type WarpBlockInfo struct {
 type WarpMessageInfo struct {
 	SourceAddress   common.Address
 	UnsignedMessage *avalancheWarp.UnsignedMessage
+	// The number of the source chain block that the message was sent in. Zero if not known.
+	BlockNumber uint64
 }
 
 // Extract Warp logs from the block, if they exist
@@ -92,6 +94,7 @@ func NewWarpMessageInfo(log types.Log) (*WarpMessageInfo, error) {
 	return &WarpMessageInfo{
 		SourceAddress:   common.BytesToAddress(log.Topics[1][:]),
 		UnsignedMessage: unsignedMsg,
+		BlockNumber:     log.BlockNumber,
 	}, nil
 }
 